@@ -0,0 +1,1068 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func newInvoiceCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "invoice", Short: "Create and inspect invoices"}
+
+	in := &pb.Invoice{}
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "List a new invoice for an existing issuer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			invoice, err := client.CreateInvoice(ctx, in)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, invoice)
+		},
+	}
+	create.Flags().StringVar(&in.IssuerId, "issuer-id", "", "id of the issuer")
+	create.Flags().Float32Var(&in.Price, "price", 0, "asking price of the invoice")
+	create.Flags().StringVar(&in.Status, "status", "open", "initial status")
+	create.Flags().StringVar(&in.InvestorId, "investor-id", "", "id of the investor, if already known")
+	create.Flags().Float32Var(&in.FaceValue, "face-value", 0, "amount repaid at maturity, defaults to the price")
+	create.Flags().StringVar(&in.DueDate, "due-date", "", "maturity date as YYYY-MM-DD")
+	create.Flags().StringVar(&in.InvoiceNumber, "invoice-number", "", "the issuer's number of the invoice, unique per issuer")
+	create.Flags().StringVar(&in.DebtorName, "debtor-name", "", "name of the debtor who owes the invoice")
+	create.Flags().StringVar(&in.DebtorReference, "debtor-reference", "", "reference of the debtor, e.g. its company registration number")
+	create.Flags().StringVar(&in.IssueDate, "issue-date", "", "issue date as YYYY-MM-DD, defaults to today")
+	create.Flags().StringVar(&in.Currency, "currency", "", "ISO 4217 currency code, defaults to EUR")
+	create.Flags().StringVar(&in.AuctionEndsAt, "auction-ends-at", "", "end of a timed auction as an RFC 3339 time, open auctions run until a bid matches the price")
+	create.Flags().Float32Var(&in.ReservePrice, "reserve-price", 0, "lowest amount accepted, hidden from investors")
+	create.Flags().Float32Var(&in.MinBidIncrement, "min-bid-increment", 0, "amount a bid has to top the best bid by")
+	create.Flags().Float32Var(&in.MaxBid, "max-bid", 0, "highest amount a bid may be, defaults to the price")
+	create.Flags().Int32Var(&in.AntiSnipingSeconds, "anti-sniping-seconds", 0, "extend a timed auction to this many seconds after a bid in its final seconds")
+	create.Flags().Var(&lineItemsFlag{items: &in.LineItems}, "line-item", "line item as DESCRIPTION:QUANTITY:UNIT_PRICE:TAX_RATE, repeat for every line")
+	create.MarkFlagRequired("issuer-id")
+	create.MarkFlagRequired("price")
+
+	get := &cobra.Command{
+		Use:   "get ID",
+		Short: "Show an invoice",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			invoice, err := client.GetInvoice(ctx, &pb.Invoice{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, invoice)
+		},
+	}
+
+	repayment := &pb.Repayment{}
+	repay := &cobra.Command{
+		Use:   "repay ID",
+		Short: "Record a repayment of a funded invoice",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			repayment.InvoiceId = args[0]
+			recorded, err := client.RecordRepayment(ctx, repayment)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, recorded)
+		},
+	}
+	repay.Flags().Float32Var(&repayment.Amount, "amount", 0, "amount repaid")
+	repay.Flags().StringVar(&repayment.Payer, "payer", "issuer", "who repays: issuer or debtor")
+	repay.MarkFlagRequired("amount")
+
+	upload := &cobra.Command{
+		Use:   "upload ID FILE",
+		Short: "Upload a PDF or image document of an invoice as the issuer of --token",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			stream, err := client.UploadInvoiceDocument(ctx)
+			if err != nil {
+				return err
+			}
+			chunk := &pb.InvoiceDocumentChunk{InvoiceId: args[0], FileName: filepath.Base(args[1])}
+			buf := make([]byte, documentChunkSize)
+			for {
+				n, err := f.Read(buf)
+				if n > 0 {
+					chunk.Data = buf[:n]
+					if err := stream.Send(chunk); err != nil {
+						break // the server's error is returned by CloseAndRecv
+					}
+					chunk = &pb.InvoiceDocumentChunk{}
+				}
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+			document, err := stream.CloseAndRecv()
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, document)
+		},
+	}
+
+	request := &pb.DocumentRequest{}
+	var file string
+	download := &cobra.Command{
+		Use:   "download ID [DOCUMENT_ID]",
+		Short: "Download a document of an invoice as the party of --token, the latest one by default",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			request.InvoiceId = args[0]
+			if len(args) == 2 {
+				request.DocumentId = args[1]
+			}
+			stream, err := client.DownloadInvoiceDocument(ctx, request)
+			if err != nil {
+				return err
+			}
+			return saveDocument(cmd.OutOrStdout(), stream, file)
+		},
+	}
+	download.Flags().StringVarP(&file, "file", "f", "", "file to write, defaults to the document's file name")
+
+	importRequest := &pb.InvoiceImport{}
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "List an invoice from a UBL 2.1 / Peppol BIS Billing 3.0 XML e-invoice",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			document, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			importRequest.Document = document
+			invoice, err := client.ImportInvoice(ctx, importRequest)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, invoice)
+		},
+	}
+	importCmd.Flags().Float32Var(&importRequest.Price, "price", 0, "asking price of the invoice")
+	importCmd.Flags().StringVar(&importRequest.IssuerId, "issuer-id", "", "id of the issuer to register the supplier's Peppol endpoint for, on its first import")
+	importCmd.MarkFlagRequired("price")
+
+	csvImport := &pb.InvoiceCsvChunk{}
+	importCsv := &cobra.Command{
+		Use:   "import-csv FILE",
+		Short: "List the invoices of a CSV file, one per row after the header",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			stream, err := client.ImportInvoicesCsv(ctx)
+			if err != nil {
+				return err
+			}
+			chunk := proto.Clone(csvImport).(*pb.InvoiceCsvChunk)
+			buf := make([]byte, documentChunkSize)
+			for {
+				n, err := f.Read(buf)
+				if n > 0 {
+					chunk.Data = buf[:n]
+					if err := stream.Send(chunk); err != nil {
+						break // the server's error is returned by CloseAndRecv
+					}
+					chunk = &pb.InvoiceCsvChunk{}
+				}
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+			if chunk.GetIssuerId() != "" {
+				// The file was empty, the server still needs the metadata
+				if err := stream.Send(chunk); err != nil && !errors.Is(err, io.EOF) {
+					return err
+				}
+			}
+			summary, err := stream.CloseAndRecv()
+			if err != nil {
+				return err
+			}
+			return printDetails(cmd.OutOrStdout(), opts.output, summary, messages(summary.GetErrors()))
+		},
+	}
+	importCsv.Flags().StringVar(&csvImport.IssuerId, "issuer-id", "", "id of the issuer of the invoices")
+	importCsv.Flags().StringToStringVar(&csvImport.Columns, "column", nil, "header of the column of an invoice field as FIELD=HEADER, e.g. price=Amount, repeat for every field")
+	importCsv.Flags().BoolVar(&csvImport.DryRun, "dry-run", false, "only validate the rows and report the errors")
+	importCsv.MarkFlagRequired("issuer-id")
+
+	cmd.AddCommand(create, get, repay, upload, download, importCmd, importCsv)
+	return cmd
+}
+
+func newIssuerCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "issuer", Short: "Inspect issuers"}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get ID",
+		Short: "Show an issuer",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			issuer, err := client.GetIssuer(ctx, &pb.Issuer{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, issuer)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "summary ID",
+		Short: "Show the dashboard of an issuer: invoices by status, best bids and repayment obligations",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			summary, err := client.GetIssuerSummary(ctx, &pb.Issuer{Id: args[0]})
+			if err != nil {
+				return err
+			}
+			return printDetails(cmd.OutOrStdout(), opts.output, summary, messages(summary.GetByStatus()),
+				messages(summary.GetListedInvoices()), messages(summary.GetRepaymentObligations()))
+		},
+	})
+	return cmd
+}
+
+func newInvestorsCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "investors", Short: "Inspect investors"}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List all investors",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			stream, err := client.GetInvestors(ctx, &emptypb.Empty{})
+			if err != nil {
+				return err
+			}
+			var investors []proto.Message
+			for {
+				investor, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				investors = append(investors, investor)
+			}
+			return printList(cmd.OutOrStdout(), opts.output, investors)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set-tier ID TIER",
+		Short: "Move an investor to another tier",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			investor, err := client.AssignInvestorTier(ctx, &pb.Investor{Id: args[0], Tier: args[1]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, investor)
+		},
+	})
+
+	portfolioRequest := &pb.PortfolioRequest{}
+	portfolio := &cobra.Command{
+		Use:   "portfolio ID",
+		Short: "Show the positions, returns and exposure of an investor",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			portfolioRequest.InvestorId = args[0]
+			portfolio, err := client.GetPortfolio(ctx, portfolioRequest)
+			if err != nil {
+				return err
+			}
+			return printDetails(cmd.OutOrStdout(), opts.output, portfolio, messages(portfolio.GetPositions()))
+		},
+	}
+	portfolio.Flags().Int32Var(&portfolioRequest.PageSize, "page-size", 0, "positions per page, 100 by default")
+	portfolio.Flags().StringVar(&portfolioRequest.PageToken, "page-token", "", "next_page_token of the previous page")
+	cmd.AddCommand(portfolio)
+
+	request := &pb.StatementRequest{}
+	var file string
+	statement := &cobra.Command{
+		Use:   "statement ID",
+		Short: "Export the statement of an investor for a date range",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			request.InvestorId = args[0]
+			stream, err := client.ExportInvestorStatement(ctx, request)
+			if err != nil {
+				return err
+			}
+			return saveStatement(cmd.OutOrStdout(), stream, file)
+		},
+	}
+	statement.Flags().StringVar(&request.From, "from", "", "first day of the statement, YYYY-MM-DD")
+	statement.Flags().StringVar(&request.To, "to", "", "last day of the statement, YYYY-MM-DD, defaults to today")
+	statement.Flags().StringVar(&request.Format, "format", "csv", "csv, ndjson or html")
+	statement.Flags().StringVarP(&file, "file", "f", "", "file to write, defaults to stdout")
+	statement.MarkFlagRequired("from")
+	cmd.AddCommand(statement)
+	return cmd
+}
+
+func newTierCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "tier", Short: "Manage investor tiers and their exposure limits"}
+
+	in := &pb.InvestorTier{}
+	set := &cobra.Command{
+		Use:   "set NAME",
+		Short: "Create a tier or replace its limits",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			in.Name = args[0]
+			tier, err := client.SetInvestorTier(ctx, in)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, tier)
+		},
+	}
+	set.Flags().Float32Var(&in.MaxIssuerConcentration, "max-issuer-concentration", 0, "maximum share of the portfolio with a single issuer in percent, 0 for no limit")
+	set.Flags().Float32Var(&in.MaxInvoiceAmount, "max-invoice-amount", 0, "maximum amount of a single bid, 0 for no limit")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List all tiers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			stream, err := client.ListInvestorTiers(ctx, &emptypb.Empty{})
+			if err != nil {
+				return err
+			}
+			var tiers []proto.Message
+			for {
+				tier, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				tiers = append(tiers, tier)
+			}
+			return printList(cmd.OutOrStdout(), opts.output, tiers)
+		},
+	}
+	cmd.AddCommand(set, list)
+	return cmd
+}
+
+func newBidCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "bid", Short: "Bid on invoices"}
+	in := &pb.Bid{}
+	place := &cobra.Command{
+		Use:   "place",
+		Short: "Place a bid on an invoice",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			bid, err := client.PlaceBid(ctx, in)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, bid)
+		},
+	}
+	addBidFlags(place, in)
+	place.Flags().Float32Var(&in.Rate, "rate", 0, "annual rate in percent, instead of an amount")
+	place.Flags().StringVar(&in.RateType, "rate-type", "", "how the rate applies: discount (default) or apr")
+	place.Flags().StringVar(&in.DayCount, "day-count", "", "day count convention: ACT/360, ACT/365 or 30/360")
+	// Bids either set an amount or a rate
+	place.MarkFlagsOneRequired("amount", "rate")
+	place.MarkFlagsMutuallyExclusive("amount", "rate")
+
+	cancellation := &pb.BidCancellation{}
+	cancel := &cobra.Command{
+		Use:   "cancel BID_ID",
+		Short: "Withdraw a pending bid and refund it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			cancellation.BidId = args[0]
+			bid, err := client.CancelBid(ctx, cancellation)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, bid)
+		},
+	}
+	cancel.Flags().StringVar(&cancellation.Reason, "reason", "", "why the bid is withdrawn, kept with the cancellation event")
+	cmd.AddCommand(place, cancel)
+	return cmd
+}
+
+func newAutoBidCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "autobid", Short: "Manage the auto-bid rules of the investor of the token"}
+
+	in := &pb.AutoBidRule{}
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a rule that bids on new listings and answers outbids",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			rule, err := client.CreateAutoBidRule(ctx, in)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, rule)
+		},
+	}
+	addAutoBidRuleFlags(create, in)
+
+	update := &cobra.Command{
+		Use:   "update RULE_ID",
+		Short: "Replace the criteria of a rule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			in.Id = args[0]
+			rule, err := client.UpdateAutoBidRule(ctx, in)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, rule)
+		},
+	}
+	addAutoBidRuleFlags(update, in)
+
+	pause := newAutoBidStatusCommand(opts, "pause", "Stop a rule from bidding", "paused")
+	resume := newAutoBidStatusCommand(opts, "resume", "Let a paused rule bid again", "active")
+
+	investor := &pb.Investor{}
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the rules with the budget their bids hold",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			stream, err := client.ListAutoBidRules(ctx, investor)
+			if err != nil {
+				return err
+			}
+			var rules []proto.Message
+			for {
+				rule, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				rules = append(rules, rule)
+			}
+			return printList(cmd.OutOrStdout(), opts.output, rules)
+		},
+	}
+
+	request := &pb.AutoBidExecutionRequest{}
+	logCmd := &cobra.Command{
+		Use:   "log RULE_ID",
+		Short: "Show the executions of a rule, latest first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			request.RuleId = args[0]
+			stream, err := client.ListAutoBidExecutions(ctx, request)
+			if err != nil {
+				return err
+			}
+			var executions []proto.Message
+			for {
+				execution, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				executions = append(executions, execution)
+			}
+			return printList(cmd.OutOrStdout(), opts.output, executions)
+		},
+	}
+	logCmd.Flags().Int32Var(&request.Limit, "limit", 0, "number of executions to show, 100 by default")
+
+	cmd.AddCommand(create, update, pause, resume, list, logCmd)
+	return cmd
+}
+
+// newAutoBidStatusCommand returns a command setting the status of a rule
+func newAutoBidStatusCommand(opts *options, use, short, ruleStatus string) *cobra.Command {
+	in := &pb.AutoBidRuleStatus{Status: ruleStatus}
+	cmd := &cobra.Command{
+		Use:   use + " RULE_ID",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			in.Id = args[0]
+			rule, err := client.SetAutoBidRuleStatus(ctx, in)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, rule)
+		},
+	}
+	return cmd
+}
+
+func addAutoBidRuleFlags(cmd *cobra.Command, in *pb.AutoBidRule) {
+	cmd.Flags().StringSliceVar(&in.IssuerGrades, "issuer-grades", nil, "risk grades of the issuers to bid on, any grade when empty")
+	cmd.Flags().Int32Var(&in.MaxTenorDays, "max-tenor-days", 0, "longest time until the due date in days, 0 for no limit")
+	cmd.Flags().Float32Var(&in.MinYield, "min-yield", 0, "lowest effective yield in percent")
+	cmd.Flags().Float32Var(&in.MaxAmountPerInvoice, "max-amount-per-invoice", 0, "most the rule bids on a single invoice")
+	cmd.Flags().Float32Var(&in.TotalBudget, "total-budget", 0, "most the pending and funded bids of the rule may hold at once")
+	cmd.MarkFlagRequired("max-amount-per-invoice")
+	cmd.MarkFlagRequired("total-budget")
+}
+
+func newTradeCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "trade", Short: "Settle trades"}
+	in := &pb.Bid{}
+	approve := &cobra.Command{
+		Use:   "approve",
+		Short: "Approve the trade of a bid and close the invoice",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			bid, err := client.ApproveTrade(ctx, in)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, bid)
+		},
+	}
+	addBidFlags(approve, in)
+	approve.MarkFlagRequired("amount")
+	cmd.AddCommand(approve)
+	return cmd
+}
+
+func addBidFlags(cmd *cobra.Command, in *pb.Bid) {
+	cmd.Flags().StringVar(&in.InvoiceId, "invoice-id", "", "id of the invoice")
+	cmd.Flags().StringVar(&in.InvestorId, "investor-id", "", "id of the investor")
+	cmd.Flags().Float32Var(&in.Amount, "amount", 0, "bid amount")
+	cmd.MarkFlagRequired("invoice-id")
+	cmd.MarkFlagRequired("investor-id")
+}
+
+func newKycCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "kyc", Short: "Onboard issuers and investors"}
+
+	status := &cobra.Command{
+		Use:   "status issuer|investor ID",
+		Short: "Show the onboarding state and document checklist of a party",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			kycStatus, err := client.GetKycStatus(ctx, &pb.KycParty{PartyType: args[0], PartyId: args[1]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, kycStatus)
+		},
+	}
+
+	document := &pb.KycDocument{}
+	submit := &cobra.Command{
+		Use:   "submit issuer|investor ID",
+		Short: "Add a document to the checklist of a pending party",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			document.PartyType, document.PartyId = args[0], args[1]
+			submitted, err := client.SubmitKycDocument(ctx, document)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, submitted)
+		},
+	}
+	submit.Flags().StringVar(&document.DocumentType, "document-type", "", "checklist entry the document is for, e.g. proof_of_address")
+	submit.Flags().StringVar(&document.Reference, "reference", "", "where the document is stored")
+	submit.MarkFlagRequired("document-type")
+	submit.MarkFlagRequired("reference")
+
+	verify := &cobra.Command{
+		Use:   "verify issuer|investor ID",
+		Short: "Have the KYC provider review the documents of a pending party",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			kycStatus, err := client.VerifyKyc(ctx, &pb.KycParty{PartyType: args[0], PartyId: args[1]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, kycStatus)
+		},
+	}
+
+	review := &pb.KycReview{}
+	reviewCmd := &cobra.Command{
+		Use:   "review issuer|investor ID",
+		Short: "Change the onboarding state of a party by hand",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			review.PartyType, review.PartyId = args[0], args[1]
+			kycStatus, err := client.ReviewKyc(ctx, review)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, kycStatus)
+		},
+	}
+	reviewCmd.Flags().StringVar(&review.Status, "status", "", "new state: pending, verified, rejected or suspended")
+	reviewCmd.Flags().StringVar(&review.Reason, "reason", "", "why the state changes")
+	reviewCmd.MarkFlagRequired("status")
+	reviewCmd.MarkFlagRequired("reason")
+
+	for _, sub := range []*cobra.Command{status, submit, verify, reviewCmd} {
+		sub.ValidArgsFunction = completePartyType
+	}
+	cmd.AddCommand(status, submit, verify, reviewCmd)
+	return cmd
+}
+
+func newWebhookCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{Use: "webhook", Short: "Manage the webhook endpoints of issuers and investors"}
+
+	endpoint := &pb.WebhookEndpoint{}
+	register := &cobra.Command{
+		Use:   "register issuer|investor ID",
+		Short: "Register an endpoint, the response shows its signing secret once",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			endpoint.PartyType, endpoint.PartyId = args[0], args[1]
+			registered, err := client.RegisterWebhookEndpoint(ctx, endpoint)
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, registered)
+		},
+	}
+	register.Flags().StringVar(&endpoint.Url, "url", "", "http or https URL the events are posted to")
+	register.Flags().StringSliceVar(&endpoint.EventTypes, "events", nil, "events to send: bid.outbid, invoice.funded, trade.settled, invoice.repaid; every event when empty")
+	register.Flags().StringVar(&endpoint.Secret, "secret", "", "key the payloads are signed with, generated when empty")
+	register.MarkFlagRequired("url")
+
+	list := &cobra.Command{
+		Use:   "list issuer|investor ID",
+		Short: "List the endpoints of a party",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			stream, err := client.ListWebhookEndpoints(ctx, &pb.WebhookEndpoint{PartyType: args[0], PartyId: args[1]})
+			if err != nil {
+				return err
+			}
+			var endpoints []proto.Message
+			for {
+				endpoint, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				endpoints = append(endpoints, endpoint)
+			}
+			return printList(cmd.OutOrStdout(), opts.output, endpoints)
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete issuer|investor ID ENDPOINT_ID",
+		Short: "Disable an endpoint, nothing is sent to it anymore",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			disabled, err := client.DeleteWebhookEndpoint(ctx, &pb.WebhookEndpoint{PartyType: args[0], PartyId: args[1], Id: args[2]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, disabled)
+		},
+	}
+
+	test := &cobra.Command{
+		Use:   "test issuer|investor ID ENDPOINT_ID",
+		Short: "Send a sample event to an endpoint and show the response",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			delivery, err := client.TestWebhookEndpoint(ctx, &pb.WebhookEndpoint{PartyType: args[0], PartyId: args[1], Id: args[2]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, delivery)
+		},
+	}
+
+	deadLetters := &cobra.Command{
+		Use:   "dead-letters issuer|investor ID ENDPOINT_ID",
+		Short: "List the deliveries to an endpoint that failed every attempt, latest first",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			stream, err := client.ListWebhookDeadLetters(ctx, &pb.WebhookEndpoint{PartyType: args[0], PartyId: args[1], Id: args[2]})
+			if err != nil {
+				return err
+			}
+			var letters []proto.Message
+			for {
+				letter, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				letters = append(letters, letter)
+			}
+			return printList(cmd.OutOrStdout(), opts.output, letters)
+		},
+	}
+
+	replay := &cobra.Command{
+		Use:   "replay issuer|investor ID DEAD_LETTER_ID",
+		Short: "Queue the delivery of a dead letter again",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, ctx, done, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer done()
+			delivery, err := client.ReplayWebhookDeadLetter(ctx, &pb.WebhookReplay{PartyType: args[0], PartyId: args[1], DeadLetterId: args[2]})
+			if err != nil {
+				return err
+			}
+			return printMessage(cmd.OutOrStdout(), opts.output, delivery)
+		},
+	}
+
+	for _, sub := range []*cobra.Command{register, list, deleteCmd, test, deadLetters, replay} {
+		sub.ValidArgsFunction = completePartyType
+	}
+	cmd.AddCommand(register, list, deleteCmd, test, deadLetters, replay)
+	return cmd
+}
+
+// completePartyType completes the party type, the first argument of the kyc and webhook commands
+func completePartyType(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return []string{"issuer", "investor"}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// lineItemsFlag parses repeated --line-item flags
+type lineItemsFlag struct {
+	items *[]*pb.LineItem
+}
+
+func (f *lineItemsFlag) String() string {
+	if f.items == nil {
+		return ""
+	}
+	lines := make([]string, 0, len(*f.items))
+	for _, item := range *f.items {
+		lines = append(lines, fmt.Sprintf("%s:%g:%g:%g", item.GetDescription(), item.GetQuantity(), item.GetUnitPrice(), item.GetTaxRate()))
+	}
+	return strings.Join(lines, ",")
+}
+
+func (f *lineItemsFlag) Set(value string) error {
+	// The description may contain colons, the last three fields are numbers
+	parts := strings.Split(value, ":")
+	if len(parts) < 4 {
+		return fmt.Errorf("line item %q must be DESCRIPTION:QUANTITY:UNIT_PRICE:TAX_RATE", value)
+	}
+	numbers := make([]float32, 3)
+	for i, part := range parts[len(parts)-3:] {
+		n, err := strconv.ParseFloat(part, 32)
+		if err != nil {
+			return fmt.Errorf("line item %q: %q is not a number", value, part)
+		}
+		numbers[i] = float32(n)
+	}
+	*f.items = append(*f.items, &pb.LineItem{
+		Description: strings.Join(parts[:len(parts)-3], ":"), Quantity: numbers[0], UnitPrice: numbers[1], TaxRate: numbers[2],
+	})
+	return nil
+}
+
+func (f *lineItemsFlag) Type() string {
+	return "lineItem"
+}
+
+// documentChunkSize is the size of the chunks documents are uploaded in
+const documentChunkSize = 64 * 1024
+
+// saveDocument writes a downloaded document to file, or to the file name sent by the server, once its content
+// matches the sha256 digest of the first chunk
+func saveDocument(w io.Writer, stream pb.InvoiceService_DownloadInvoiceDocumentClient, file string) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		file = filepath.Base(first.GetFileName())
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	digest := sha256.New()
+	out := io.MultiWriter(tmp, digest)
+	size := 0
+	chunk := first
+	for {
+		n, err := out.Write(chunk.GetData())
+		if err != nil {
+			return err
+		}
+		size += n
+		chunk, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if got := hex.EncodeToString(digest.Sum(nil)); got != first.GetSha256() {
+		return fmt.Errorf("downloaded document has sha256 %s, expected %s", got, first.GetSha256())
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Saved %s (%s, %d bytes, sha256 %s)\n", file, first.GetContentType(), size, first.GetSha256())
+	return err
+}
+
+// saveStatement writes an exported statement as it is received to file, or to w without one. A file is only
+// created once the whole statement arrived.
+func saveStatement(w io.Writer, stream pb.InvoiceService_ExportInvestorStatementClient, file string) error {
+	var out io.Writer = w
+	var tmp *os.File
+	if file != "" {
+		var err error
+		tmp, err = os.CreateTemp(filepath.Dir(file), ".statement-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		out = tmp
+	}
+	size := 0
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		n, err := out.Write(chunk.GetData())
+		if err != nil {
+			return err
+		}
+		size += n
+	}
+	if tmp == nil {
+		return nil
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Saved %s (%d bytes)\n", file, size)
+	return err
+}
@@ -0,0 +1,120 @@
+// invoicectl is a command line client for the InvoiceService
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// options shared by every subcommand
+type options struct {
+	target             string
+	timeout            time.Duration
+	output             string
+	tls                bool
+	caFile             string
+	serverName         string
+	insecureSkipVerify bool
+	token              string
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+	root := &cobra.Command{
+		Use:           "invoicectl",
+		Short:         "Command line client for the InvoiceService",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := formats[opts.output]; !ok {
+				return fmt.Errorf("unknown output format %q, use table, json or yaml", opts.output)
+			}
+			return nil
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.target, "target", envOr("INVOICECTL_TARGET", "localhost:50051"), "address of the gRPC server (env INVOICECTL_TARGET)")
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout of each RPC")
+	flags.StringVarP(&opts.output, "output", "o", "table", "output format: table, json or yaml")
+	flags.BoolVar(&opts.tls, "tls", false, "connect over TLS")
+	flags.StringVar(&opts.caFile, "ca-file", "", "PEM file with the CA certificates used to verify the server, defaults to the system pool")
+	flags.StringVar(&opts.serverName, "server-name", "", "override the server name used to verify the certificate")
+	flags.BoolVar(&opts.insecureSkipVerify, "insecure-skip-verify", false, "don't verify the server certificate")
+	flags.StringVar(&opts.token, "token", os.Getenv("INVOICECTL_TOKEN"), "bearer token sent in the authorization metadata (env INVOICECTL_TOKEN)")
+	root.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	root.AddCommand(
+		newInvoiceCommand(opts),
+		newIssuerCommand(opts),
+		newInvestorsCommand(opts),
+		newBidCommand(opts),
+		newAutoBidCommand(opts),
+		newTradeCommand(opts),
+		newTierCommand(opts),
+		newKycCommand(opts),
+		newWebhookCommand(opts),
+	)
+	return root
+}
+
+// connect opens a connection to the server and returns a context carrying the auth token and timeout
+func (o *options) connect(ctx context.Context) (pb.InvoiceServiceClient, context.Context, func(), error) {
+	creds := insecure.NewCredentials()
+	if o.tls {
+		tlsConfig := &tls.Config{ServerName: o.serverName, InsecureSkipVerify: o.insecureSkipVerify}
+		if o.caFile != "" {
+			pem, err := os.ReadFile(o.caFile)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, nil, nil, errors.New("no certificates found in CA file")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(o.target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to connect to %s: %w", o.target, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	if o.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+o.token)
+	}
+	return pb.NewInvoiceServiceClient(conn), ctx, func() {
+		cancel()
+		conn.Close()
+	}, nil
+}
+
+func envOr(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	"github.com/berdebotond/bankable_technical_test/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+// runCommand runs invoicectl against a server backed by sqlmock and returns its output. The server accepts
+// admin-token, issuer-token for issuer 2 and investor-token for investor-id.
+func runCommand(t *testing.T, expect func(mock sqlmock.Sqlmock), args ...string) (string, error) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	expect(mock)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	tokens := filepath.Join(t.TempDir(), "tokens.json")
+	digest := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+	assert.NoError(t, os.WriteFile(tokens, []byte(`{"tokens": [
+		{"sha256": "`+digest("admin-token")+`", "role": "admin"},
+		{"sha256": "`+digest("issuer-token")+`", "role": "issuer", "party_id": "2"},
+		{"sha256": "`+digest("investor-token")+`", "role": "investor", "party_id": "investor-id"}
+	]}`), 0o600))
+	s, _, _, err := pkg.SetupServer(db, &cfg.Config{AuthTokenFile: tokens})
+	assert.NoError(t, err)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(append([]string{"--target", lis.Addr().String()}, args...))
+	err = root.Execute()
+	assert.NoError(t, mock.ExpectationsWereMet())
+	return out.String(), err
+}
+
+func expectIssuer(mock sqlmock.Sqlmock) {
+	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).AddRow("1", "Issuer Name", 100.0)
+	mock.ExpectQuery("SELECT id, name, balance FROM issuer WHERE id = \\$1").WithArgs("1").WillReturnRows(rows)
+	risk := sqlmock.NewRows([]string{"repaid", "defaulted", "overdue", "funded", "outstanding", "listed"}).AddRow(0, 0, 0, 0, 0.0, 0.0)
+	mock.ExpectQuery("FROM invoice WHERE issuer_id = \\$1").WithArgs("1").WillReturnRows(risk)
+}
+
+func TestIssuerGetTable(t *testing.T) {
+	out, err := runCommand(t, expectIssuer, "issuer", "get", "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ID  BALANCE  NAME         RISK_SCORE  RISK_GRADE  FUNDING_LIMIT  OUTSTANDING\n1   100      Issuer Name  75          B           250000         0\n", out)
+}
+
+func TestIssuerGetJSON(t *testing.T) {
+	out, err := runCommand(t, expectIssuer, "issuer", "get", "1", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "1", "name": "Issuer Name", "balance": 100, "risk_score": 75, "risk_grade": "B", "funding_limit": 250000, "outstanding": 0}`, out)
+}
+
+func TestIssuerGetYAML(t *testing.T) {
+	out, err := runCommand(t, expectIssuer, "issuer", "get", "1", "-o", "yaml")
+
+	assert.NoError(t, err)
+	// ids stay strings, fields keep their proto order
+	assert.Equal(t, "id: \"1\"\nbalance: 100\nname: Issuer Name\nrisk_score: 75\nrisk_grade: B\nfunding_limit: 250000\noutstanding: 0\n", out)
+}
+
+func TestInvestorsListJSON(t *testing.T) {
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "name", "balance", "tier"}).
+			AddRow("1", "Investor 1", 1000.0, "standard").
+			AddRow("2", "Investor 2", 2000.0, "standard")
+		mock.ExpectQuery("SELECT id, name, balance, tier FROM Investor").WillReturnRows(rows)
+	}, "investors", "list", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"id": "1", "name": "Investor 1", "balance": 1000, "tier": "standard"}, {"id": "2", "name": "Investor 2", "balance": 2000, "tier": "standard"}]`, out)
+}
+
+func TestUnknownOutputFormat(t *testing.T) {
+	_, err := runCommand(t, func(mock sqlmock.Sqlmock) {}, "issuer", "get", "1", "-o", "xml")
+
+	assert.ErrorContains(t, err, "unknown output format")
+}
+
+func TestInvoiceUploadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invoice.pdf")
+	assert.NoError(t, os.WriteFile(path, []byte("%PDF-1.7\ninvoice\n"), 0o600))
+
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("FROM invoice WHERE id = \\$1").WithArgs("1", "2").WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(true))
+		mock.ExpectQuery("INSERT INTO invoice_document").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("3"))
+	}, "invoice", "upload", "1", path, "--token", "issuer-token", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "3", "invoice_id": "1", "file_name": "invoice.pdf", "content_type": "application/pdf", "size": "17",
+		"sha256": "6fc703faf706f01d0dc319dfc31805dba4e2cc9d5996bad94c92a38d023e1319"}`, out)
+}
+
+func TestInvoiceCreateLineItems(t *testing.T) {
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT kyc_status FROM issuer").WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"kyc_status"}).AddRow("verified"))
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM issuer WHERE id = \\$1 FOR UPDATE").WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+		risk := sqlmock.NewRows([]string{"repaid", "defaulted", "overdue", "funded", "outstanding", "listed"}).AddRow(0, 0, 0, 0, 0.0, 0.0)
+		mock.ExpectQuery("FROM invoice WHERE issuer_id = \\$1").WithArgs("1").WillReturnRows(risk)
+		mock.ExpectQuery("INSERT INTO invoice").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+		mock.ExpectExec("INSERT INTO invoice_line_item").WithArgs("2", 1, "Widgets: blue", float32(2), float32(50), float32(20), float32(100), float32(20)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}, "invoice", "create", "--issuer-id", "1", "--price", "110", "--invoice-number", "INV-7", "--issue-date", "2024-03-01",
+		"--line-item", "Widgets: blue:2:50:20", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "2", "issuer_id": "1", "status": "open", "investor_id": "", "price": 110, "face_value": 120, "due_date": "",
+		"repaid_amount": 0, "issuer_risk_grade": "", "invoice_number": "INV-7", "debtor_name": "", "debtor_reference": "", "issue_date": "2024-03-01",
+		"currency": "EUR", "line_items": [{"description": "Widgets: blue", "quantity": 2, "unit_price": 50, "tax_rate": 20, "net_amount": 100, "tax_amount": 20}],
+		"net_total": 100, "tax_total": 20, "auction_ends_at": "", "reserve_price": 0,
+		"min_bid_increment": 0, "max_bid": 0, "anti_sniping_seconds": 0}`, out)
+}
+
+func TestInvoiceImportCsvDryRunTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invoices.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("Number,Amount\nINV-1,100\nINV-2,abc\n"), 0o600))
+
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT kyc_status FROM issuer").WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"kyc_status"}).AddRow("verified"))
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM issuer WHERE id = \\$1 FOR UPDATE").WithArgs("1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+		mock.ExpectQuery("SELECT invoice_number FROM invoice").WillReturnRows(sqlmock.NewRows([]string{"invoice_number"}))
+		risk := sqlmock.NewRows([]string{"repaid", "defaulted", "overdue", "funded", "outstanding", "listed"}).AddRow(0, 0, 0, 0, 0.0, 0.0)
+		mock.ExpectQuery("FROM invoice WHERE issuer_id = \\$1").WithArgs("1").WillReturnRows(risk)
+		mock.ExpectRollback()
+	}, "invoice", "import-csv", path, "--issuer-id", "1", "--column", "invoice_number=Number", "--column", "price=Amount", "--dry-run")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "DRY_RUN  ROWS  VALID  IMPORTED  FACE_VALUE  ERRORS   INVOICE_IDS\ntrue     2     1      0         100         1 items  0 items\n\n"+
+		"ROW  COLUMN  MESSAGE\n3    Amount  \"abc\" is not a number\n", out)
+}
+
+func TestInvestorsStatementFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statement.ndjson")
+
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT name, balance FROM investor WHERE id = \\$1").WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"name", "balance"}).AddRow("Investor 1", 1000.0))
+		mock.ExpectQuery("SELECT time, kind, invoice_id, reference, funded, amount FROM").
+			WillReturnRows(sqlmock.NewRows([]string{"time", "kind", "invoice_id", "reference", "funded", "amount"}).
+				AddRow(time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC), "deposit", "", "2", 0.0, 1000.0))
+		mock.ExpectCommit()
+	}, "investors", "statement", "1", "--from", "2024-03-01", "--to", "2024-03-31", "--format", "ndjson", "-f", path, "--token", "admin-token")
+
+	assert.NoError(t, err)
+	assert.Regexp(t, `^Saved .*statement.ndjson \(\d+ bytes\)\n$`, out)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"time":"2024-03-01T00:00:00Z","kind":"opening_balance","amount":0,"balance":0}
+{"time":"2024-03-05T09:00:00Z","kind":"deposit","reference":"2","amount":1000,"balance":1000}
+{"time":"2024-04-01T00:00:00Z","kind":"closing_balance","amount":0,"balance":1000}
+`, string(data))
+}
+
+func TestInvestorsPortfolioTable(t *testing.T) {
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("FROM investor LEFT JOIN position ON true").WithArgs("1", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"balance", "reserved", "positions", "funded", "outstanding", "expected", "realized", "yield"}).
+				AddRow(500.0, 0.0, 1, 90.0, 100.0, 10.0, 0.0, 12.5))
+		mock.ExpectQuery("SELECT 'issuer'").WillReturnRows(sqlmock.NewRows([]string{"dimension", "key", "name", "positions", "funded", "outstanding"}))
+		mock.ExpectQuery("FROM bid WHERE investor_id = \\$1 AND status = 'pending'").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "invoice_id", "amount", "status", "rate", "rate_type", "day_count", "effective_yield"}))
+		mock.ExpectQuery("FROM position WHERE invoice_id::text > \\$3").WithArgs("1", sqlmock.AnyArg(), "", int32(11)).
+			WillReturnRows(sqlmock.NewRows([]string{"invoice_id", "issuer_id", "issuer_name", "invoice_number", "status", "due_date",
+				"funded", "fees", "face_value", "received", "outstanding", "expected_return", "realized_return", "yield", "maturity_bucket"}).
+				AddRow("3", "2", "Issuer Name", "INV-1", "closed", "2099-01-31", 90.0, 0.0, 100.0, 0.0, 100.0, 10.0, 0.0, 12.5, "90+"))
+		mock.ExpectCommit()
+	}, "investors", "portfolio", "1", "--page-size", "10", "--token", "admin-token")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "1 items    -\n\nINVOICE_ID  ISSUER_ID")
+	assert.Contains(t, out, "3           2          Issuer Name  INV-1           closed  2099-01-31  90      0     100         0         100          10               0                12.5   90+\n")
+}
+
+func TestAutoBidPauseJSON(t *testing.T) {
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "investor_id", "issuer_grades", "max_tenor_days", "min_yield", "max_amount_per_invoice",
+			"total_budget", "status", "committed", "created_at"}).
+			AddRow("rule-id", "investor-id", "{A,B}", 90, 8.0, 5000.0, 20000.0, "paused", 4000.0, "2024-01-01T00:00:00Z")
+		mock.ExpectQuery("UPDATE auto_bid_rule SET status").WithArgs("rule-id", "investor-id", "paused").WillReturnRows(rows)
+	}, "autobid", "pause", "rule-id", "--token", "investor-token", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "rule-id", "investor_id": "investor-id", "issuer_grades": ["A", "B"], "max_tenor_days": 90, "min_yield": 8,
+		"max_amount_per_invoice": 5000, "total_budget": 20000, "status": "paused", "committed": 4000, "created_at": "2024-01-01T00:00:00Z"}`, out)
+}
+
+func TestWebhookReplayJSON(t *testing.T) {
+	out, err := runCommand(t, func(mock sqlmock.Sqlmock) {
+		rows := sqlmock.NewRows([]string{"id", "endpoint_id", "event_type", "payload", "status", "attempts", "response_code", "last_error",
+			"next_attempt_at", "created_at"}).
+			AddRow("delivery-id", "endpoint-id", "bid.outbid", "{}", "pending", 0, 0, "", "2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z")
+		mock.ExpectQuery("UPDATE webhook_dead_letter").WithArgs("letter-id", "investor", "investor-id").WillReturnRows(rows)
+	}, "webhook", "replay", "investor", "investor-id", "letter-id", "--token", "investor-token", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "delivery-id", "endpoint_id": "endpoint-id", "event_type": "bid.outbid", "payload": "{}", "status": "pending",
+		"attempts": 0, "response_code": 0, "last_error": "", "next_attempt_at": "2024-01-02T00:00:00Z", "created_at": "2024-01-01T00:00:00Z"}`, out)
+}
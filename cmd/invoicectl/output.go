@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// formats maps --output values to their printer, list tells whether msgs is a list or a single message
+var formats = map[string]func(w io.Writer, msgs []proto.Message, list bool) error{
+	"table": printTable,
+	"json":  printJSON,
+	"yaml":  printYAML,
+}
+
+var jsonOptions = protojson.MarshalOptions{EmitUnpopulated: true, UseProtoNames: true}
+
+func printMessage(w io.Writer, format string, msg proto.Message) error {
+	return formats[format](w, []proto.Message{msg}, false)
+}
+
+func printList(w io.Writer, format string, msgs []proto.Message) error {
+	return formats[format](w, msgs, true)
+}
+
+// printDetails prints msg and, in the table format, each non-empty list of its details as a table of its own
+// since the table only counts the items of nested lists
+func printDetails(w io.Writer, format string, msg proto.Message, lists ...[]proto.Message) error {
+	if err := printMessage(w, format, msg); err != nil || format != "table" {
+		return err
+	}
+	for _, list := range lists {
+		if len(list) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		if err := printList(w, format, list); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messages converts a list of generated messages for printList
+func messages[T proto.Message](items []T) []proto.Message {
+	msgs := make([]proto.Message, len(items))
+	for i, item := range items {
+		msgs[i] = item
+	}
+	return msgs
+}
+
+// toJSON encodes messages with protojson, as an array for lists
+func toJSON(msgs []proto.Message, list bool) ([]byte, error) {
+	encoded := make([]json.RawMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		b, err := jsonOptions.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, b)
+	}
+	if !list {
+		return encoded[0], nil
+	}
+	return json.Marshal(encoded)
+}
+
+func printJSON(w io.Writer, msgs []proto.Message, list bool) error {
+	b, err := toJSON(msgs, list)
+	if err != nil {
+		return err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, b, "", "  "); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, indented.String())
+	return err
+}
+
+func printYAML(w io.Writer, msgs []proto.Message, list bool) error {
+	b, err := toJSON(msgs, list)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, decoding it into a node keeps the field order of the message
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle turns the flow style nodes produced from JSON into regular block style YAML
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Style &^= yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// printTable prints one row per message with the top level fields as columns
+func printTable(w io.Writer, msgs []proto.Message, list bool) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(msgs) == 0 {
+		return tw.Flush()
+	}
+
+	fields := msgs[0].ProtoReflect().Descriptor().Fields()
+	header := make([]string, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		header[i] = strings.ToUpper(string(fields.Get(i).Name()))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, msg := range msgs {
+		m := msg.ProtoReflect()
+		row := make([]string, fields.Len())
+		for i := 0; i < fields.Len(); i++ {
+			row[i] = formatValue(fields.Get(i), m.Get(fields.Get(i)))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch {
+	case fd.IsList():
+		return fmt.Sprintf("%d items", v.List().Len())
+	case fd.IsMap():
+		return fmt.Sprintf("%d entries", v.Map().Len())
+	case fd.Kind() == protoreflect.MessageKind:
+		b, err := protojson.Marshal(v.Message().Interface())
+		if err != nil {
+			return "?"
+		}
+		return string(b)
+	case fd.Kind() == protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return fmt.Sprint(v.Enum())
+	default:
+		s := fmt.Sprint(v.Interface())
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+}
@@ -0,0 +1,77 @@
+// reconcile checks the balance invariants once and prints the discrepancy report as JSON.
+// It takes the same flags, environment variables and config file as the server.
+// The exit code is 0 when the books balance, 2 when discrepancies were found and 1 on errors.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	"github.com/berdebotond/bankable_technical_test/pkg"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	config, err := cfg.LoadConfig()
+	if errors.Is(err, pflag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	pkg.SetupLogging(config.LogLevel)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Only read the books, never migrate or seed them
+	db, err := pkg.OpenDatabase(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	defer db.Close()
+	pending, err := pkg.PendingMigrations(ctx, db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if pending > 0 {
+		fmt.Fprintf(os.Stderr, "the schema is %d migrations behind, start the server to migrate it first\n", pending)
+		return 1
+	}
+
+	report, err := pkg.Reconcile(ctx, db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reconciliation failed: %v\n", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write report: %v\n", err)
+		return 1
+	}
+	if config.ReconciliationReportFile != "" {
+		if err := report.WriteReport(config.ReconciliationReportFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+	if !report.OK {
+		return 2
+	}
+	return 0
+}
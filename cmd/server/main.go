@@ -1,32 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"net"
 	"os"
+	"os/signal"
+	"syscall"
 
 	cfg "github.com/berdebotond/bankable_technical_test/config"
 	"github.com/berdebotond/bankable_technical_test/pkg"
+	"github.com/spf13/pflag"
 )
 
 func main() {
 	config, err := cfg.LoadConfig()
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
 	if err != nil {
 		fmt.Printf("failed to load config: %v\n", err)
 		os.Exit(1)
 	}
-	db := pkg.SetupDatabase(config.DatabaseHost, config.DatabasePort, config.DatabaseUser, config.DatabasePassword, config.DatabaseName)
-	defer db.Close()
 
-	s := pkg.SetupServer(db)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	log.Printf("Server started on port 50051")
-	lis, err := net.Listen("tcp", ":50051")
-	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
-	}
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	if err := pkg.Run(ctx, config); err != nil {
+		fmt.Fprintf(os.Stderr, "server stopped: %v\n", err)
+		os.Exit(1)
 	}
 }
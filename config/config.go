@@ -1,30 +1,267 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+// EnvPrefix is prepended to the env tag of every field
+const EnvPrefix = "INVOICE_"
+
+// DefaultConfigFile is read when neither --config nor INVOICE_CONFIG is set. It is fine for it not to exist.
+const DefaultConfigFile = "./config/config.json"
+
+// Config holds every setting of the server. Each field can be set, from lowest to highest precedence, by
+// its default tag, the config file (key from the mapstructure tag), the environment (INVOICE_ + env tag)
+// and the command line (--flag tag).
 type Config struct {
-	DatabaseHost     string `json:"databaseHost" default:"localhost"`
-	DatabasePort     string `json:"databasePort", default:"5432"`
-	DatabaseUser     string `json:"databaseUser", default:"username"`
-	DatabasePassword string `json:"databasePassword", default:"password"`
-	DatabaseName     string `json:"databaseName", default:"test"`
-	// Add more fields as needed
+	ListenAddress  string `mapstructure:"ListenAddress" default:":50051" env:"LISTEN_ADDRESS" flag:"listen-address" usage:"address the gRPC server listens on"`
+	MetricsAddress string `mapstructure:"MetricsAddress" default:":9090" env:"METRICS_ADDRESS" flag:"metrics-address" usage:"address of the /metrics HTTP server, empty to disable it"`
+	GatewayAddress string `mapstructure:"GatewayAddress" default:":8080" env:"GATEWAY_ADDRESS" flag:"gateway-address" usage:"address of the REST/JSON gateway, empty to disable it"`
+
+	TLSEnabled  bool   `mapstructure:"TLSEnabled" default:"false" env:"TLS_ENABLED" flag:"tls" usage:"serve gRPC over TLS"`
+	TLSCertFile string `mapstructure:"TLSCertFile" default:"" env:"TLS_CERT_FILE" flag:"tls-cert-file" usage:"PEM certificate used when TLS is enabled"`
+	TLSKeyFile  string `mapstructure:"TLSKeyFile" default:"" env:"TLS_KEY_FILE" flag:"tls-key-file" usage:"PEM private key used when TLS is enabled"`
+
+	DatabaseHost         string        `mapstructure:"DatabaseHost" default:"localhost" env:"DATABASE_HOST" flag:"database-host" usage:"PostgreSQL host"`
+	DatabasePort         string        `mapstructure:"DatabasePort" default:"5432" env:"DATABASE_PORT" flag:"database-port" usage:"PostgreSQL port"`
+	DatabaseUser         string        `mapstructure:"DatabaseUser" default:"username" env:"DATABASE_USER" flag:"database-user" usage:"PostgreSQL user"`
+	DatabasePassword     string        `mapstructure:"DatabasePassword" default:"" env:"DATABASE_PASSWORD" flag:"database-password" usage:"PostgreSQL password, prefer database-password-file"`
+	DatabasePasswordFile string        `mapstructure:"DatabasePasswordFile" default:"" env:"DATABASE_PASSWORD_FILE" flag:"database-password-file" usage:"file containing the PostgreSQL password, overrides database-password"`
+	DatabaseName         string        `mapstructure:"DatabaseName" default:"test" env:"DATABASE_NAME" flag:"database-name" usage:"PostgreSQL database name"`
+	DatabaseSSLMode      string        `mapstructure:"DatabaseSSLMode" default:"disable" env:"DATABASE_SSLMODE" flag:"database-sslmode" usage:"PostgreSQL sslmode (disable, allow, prefer, require, verify-ca, verify-full)"`
+	DatabaseMaxOpenConns int           `mapstructure:"DatabaseMaxOpenConns" default:"25" env:"DATABASE_MAX_OPEN_CONNS" flag:"database-max-open-conns" usage:"maximum number of open database connections, 0 for unlimited"`
+	DatabaseMaxIdleConns int           `mapstructure:"DatabaseMaxIdleConns" default:"5" env:"DATABASE_MAX_IDLE_CONNS" flag:"database-max-idle-conns" usage:"maximum number of idle database connections"`
+	DatabaseConnMaxLife  time.Duration `mapstructure:"DatabaseConnMaxLife" default:"30m" env:"DATABASE_CONN_MAX_LIFE" flag:"database-conn-max-life" usage:"maximum lifetime of a database connection, 0 to keep them forever"`
+
+	LogLevel string `mapstructure:"LogLevel" default:"info" env:"LOG_LEVEL" flag:"log-level" usage:"minimum log level (debug, info, warn, error)"`
+
+	TracingExporter string `mapstructure:"TracingExporter" default:"none" env:"TRACING_EXPORTER" flag:"tracing-exporter" usage:"trace exporter (none, stdout, file, otlp)"`
+	TracingEndpoint string `mapstructure:"TracingEndpoint" default:"localhost:4317" env:"TRACING_ENDPOINT" flag:"tracing-endpoint" usage:"OTLP collector endpoint"`
+	TracingFile     string `mapstructure:"TracingFile" default:"traces.json" env:"TRACING_FILE" flag:"tracing-file" usage:"file spans are written to by the file exporter"`
+
+	// ShutdownTimeout is how long in-flight RPCs get to finish on shutdown
+	ShutdownTimeout time.Duration `mapstructure:"ShutdownTimeout" default:"30s" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"how long in-flight RPCs get to finish on shutdown"`
+
+	// AuthTokenFile is a JSON file with the SHA-256 digests of the bearer tokens and the callers they authenticate, see README
+	AuthTokenFile string `mapstructure:"AuthTokenFile" default:"" env:"AUTH_TOKEN_FILE" flag:"auth-token-file" usage:"JSON file with the bearer tokens of admins, issuers and investors, empty to only allow anonymous RPCs"`
+
+	// FeeScheduleFile is a JSON file with the default and per issuer fee schedules, see README
+	FeeScheduleFile string `mapstructure:"FeeScheduleFile" default:"" env:"FEE_SCHEDULE_FILE" flag:"fee-schedule-file" usage:"JSON file with the trade fee schedules, empty to charge no fees"`
+	KycProvider     string `mapstructure:"KycProvider" default:"fake" env:"KYC_PROVIDER" flag:"kyc-provider" usage:"provider verifying KYC documents, only fake is available"`
+	// RiskModelFile is a JSON file with the issuer risk weights, grades and funding limits, see README
+	RiskModelFile string `mapstructure:"RiskModelFile" default:"" env:"RISK_MODEL_FILE" flag:"risk-model-file" usage:"JSON file with the issuer risk model, empty to use the default one"`
+
+	// Invoice documents are kept on the local filesystem under DocumentDir, or in memory for development
+	DocumentStore   string `mapstructure:"DocumentStore" default:"local" env:"DOCUMENT_STORE" flag:"document-store" usage:"where invoice documents are stored (local, memory)"`
+	DocumentDir     string `mapstructure:"DocumentDir" default:"documents" env:"DOCUMENT_DIR" flag:"document-dir" usage:"directory of the local document store"`
+	MaxDocumentSize int    `mapstructure:"MaxDocumentSize" default:"10485760" env:"MAX_DOCUMENT_SIZE" flag:"max-document-size" usage:"maximum size of an invoice document in bytes, 0 for no limit"`
+
+	DayCountConvention string `mapstructure:"DayCountConvention" default:"ACT/360" env:"DAY_COUNT_CONVENTION" flag:"day-count-convention" usage:"day count convention of bids that don't set one (ACT/360, ACT/365, 30/360)"`
+	// BidCancelLockout is how long before the end of a timed auction its leading bid can no longer be cancelled
+	BidCancelLockout time.Duration `mapstructure:"BidCancelLockout" default:"5m" env:"BID_CANCEL_LOCKOUT" flag:"bid-cancel-lockout" usage:"final period of a timed auction in which its leading bid can't be cancelled, 0 to always allow it"`
+
+	MaturityCheckInterval time.Duration `mapstructure:"MaturityCheckInterval" default:"24h" env:"MATURITY_CHECK_INTERVAL" flag:"maturity-check-interval" usage:"how often funded invoices are checked for being overdue, 0 to disable the job"`
+	DefaultAfterDays      int           `mapstructure:"DefaultAfterDays" default:"90" env:"DEFAULT_AFTER_DAYS" flag:"default-after-days" usage:"days past the due date after which an overdue invoice is defaulted"`
+
+	ReconciliationInterval   time.Duration `mapstructure:"ReconciliationInterval" default:"1h" env:"RECONCILIATION_INTERVAL" flag:"reconciliation-interval" usage:"how often balances are reconciled, 0 to disable the job"`
+	ReconciliationReportFile string        `mapstructure:"ReconciliationReportFile" default:"" env:"RECONCILIATION_REPORT_FILE" flag:"reconciliation-report-file" usage:"file the latest reconciliation report is written to as JSON, empty to only log it"`
+
+	// Webhook deliveries are retried after WebhookRetryBackoff, doubling with every failed attempt until WebhookMaxAttempts
+	WebhookDeliveryInterval time.Duration `mapstructure:"WebhookDeliveryInterval" default:"5s" env:"WEBHOOK_DELIVERY_INTERVAL" flag:"webhook-delivery-interval" usage:"how often due webhook deliveries are sent, 0 to disable webhooks"`
+	WebhookTimeout          time.Duration `mapstructure:"WebhookTimeout" default:"10s" env:"WEBHOOK_TIMEOUT" flag:"webhook-timeout" usage:"timeout of a single webhook request"`
+	WebhookRetryBackoff     time.Duration `mapstructure:"WebhookRetryBackoff" default:"30s" env:"WEBHOOK_RETRY_BACKOFF" flag:"webhook-retry-backoff" usage:"delay before the first webhook retry, doubled after every failed attempt"`
+	WebhookMaxAttempts      int           `mapstructure:"WebhookMaxAttempts" default:"8" env:"WEBHOOK_MAX_ATTEMPTS" flag:"webhook-max-attempts" usage:"attempts after which a webhook delivery is moved to the dead letters"`
+	// Webhook requests to loopback, link-local and private addresses are refused unless allowed, for local testing
+	WebhookAllowPrivateNetworks bool `mapstructure:"WebhookAllowPrivateNetworks" default:"false" env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS" flag:"webhook-allow-private-networks" usage:"allow webhook requests to loopback, link-local and private addresses"`
+
+	// Feature toggles
+	SeedMockData     bool `mapstructure:"SeedMockData" default:"true" env:"SEED_MOCK_DATA" flag:"seed-mock-data" usage:"insert random issuers and investors on startup"`
+	EnableReflection bool `mapstructure:"EnableReflection" default:"true" env:"ENABLE_REFLECTION" flag:"enable-reflection" usage:"register gRPC server reflection"`
 }
 
+// LoadConfig loads the configuration using the process' command line arguments
 func LoadConfig() (*Config, error) {
-	viper.SetConfigFile("./config/config.json") // Specify the configuration file path
-	err := viper.ReadInConfig()
-	if err != nil {
+	return Load(os.Args[1:])
+}
+
+// Load loads the configuration from defaults, the config file, environment variables and args, then validates it
+func Load(args []string) (*Config, error) {
+	v := viper.New()
+	flags := pflag.NewFlagSet("server", pflag.ContinueOnError)
+	configFile := flags.String("config", DefaultConfigFile, "path of the JSON configuration file (env "+EnvPrefix+"CONFIG)")
+
+	fields := reflect.TypeOf(Config{})
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if err := registerFlag(flags, field); err != nil {
+			return nil, err
+		}
+		v.SetDefault(key, field.Tag.Get("default"))
+		if err := v.BindEnv(key, EnvPrefix+field.Tag.Get("env")); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := flags.Parse(args); err != nil {
 		return nil, err
 	}
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		if err := v.BindPFlag(field.Tag.Get("mapstructure"), flags.Lookup(field.Tag.Get("flag"))); err != nil {
+			return nil, err
+		}
+	}
+
+	// A missing default config file is fine, one that was asked for explicitly is not
+	path, explicit := *configFile, flags.Changed("config")
+	if env, ok := os.LookupEnv(EnvPrefix + "CONFIG"); ok && !explicit {
+		path, explicit = env, true
+	}
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		if explicit || !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
 
 	var config Config
-	err = viper.Unmarshal(&config)
-	if err != nil {
+	if err := v.Unmarshal(&config); err != nil {
 		return nil, err
 	}
 
+	if err := config.resolveSecrets(); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
+
+// registerFlag adds the command line flag of a Config field, using its default tag as default value
+func registerFlag(flags *pflag.FlagSet, field reflect.StructField) error {
+	name, usage, def := field.Tag.Get("flag"), field.Tag.Get("usage"), field.Tag.Get("default")
+	usage = fmt.Sprintf("%s (env %s%s)", usage, EnvPrefix, field.Tag.Get("env"))
+
+	switch {
+	case field.Type == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(def)
+		if err != nil {
+			return fmt.Errorf("invalid default for %s: %w", field.Name, err)
+		}
+		flags.Duration(name, d, usage)
+	case field.Type.Kind() == reflect.String:
+		flags.String(name, def, usage)
+	case field.Type.Kind() == reflect.Int:
+		n, err := strconv.Atoi(def)
+		if err != nil {
+			return fmt.Errorf("invalid default for %s: %w", field.Name, err)
+		}
+		flags.Int(name, n, usage)
+	case field.Type.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return fmt.Errorf("invalid default for %s: %w", field.Name, err)
+		}
+		flags.Bool(name, b, usage)
+	default:
+		return fmt.Errorf("unsupported config field type %s for %s", field.Type, field.Name)
+	}
+	return nil
+}
+
+// resolveSecrets reads secrets that were given as a file path
+func (c *Config) resolveSecrets() error {
+	if c.DatabasePasswordFile == "" {
+		return nil
+	}
+	password, err := os.ReadFile(c.DatabasePasswordFile)
+	if err != nil {
+		return fmt.Errorf("failed to read database password file: %w", err)
+	}
+	c.DatabasePassword = strings.TrimRight(string(password), "\r\n")
+	return nil
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validAddress(c.ListenAddress), "ListenAddress %q must be a host:port address", c.ListenAddress)
+	check(c.MetricsAddress == "" || validAddress(c.MetricsAddress), "MetricsAddress %q must be a host:port address or empty", c.MetricsAddress)
+	check(c.GatewayAddress == "" || validAddress(c.GatewayAddress), "GatewayAddress %q must be a host:port address or empty", c.GatewayAddress)
+
+	if c.TLSEnabled {
+		check(c.TLSCertFile != "", "TLSCertFile is required when TLS is enabled")
+		check(c.TLSKeyFile != "", "TLSKeyFile is required when TLS is enabled")
+	}
+
+	check(c.DatabaseHost != "", "DatabaseHost is required")
+	port, err := strconv.Atoi(c.DatabasePort)
+	check(err == nil && port > 0 && port < 65536, "DatabasePort %q must be a port number", c.DatabasePort)
+	check(c.DatabaseUser != "", "DatabaseUser is required")
+	check(c.DatabaseName != "", "DatabaseName is required")
+	check(oneOf(c.DatabaseSSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+		"DatabaseSSLMode %q is not a valid sslmode", c.DatabaseSSLMode)
+	check(c.DatabaseMaxOpenConns >= 0, "DatabaseMaxOpenConns must not be negative")
+	check(c.DatabaseMaxIdleConns >= 0, "DatabaseMaxIdleConns must not be negative")
+	check(c.DatabaseMaxOpenConns == 0 || c.DatabaseMaxIdleConns <= c.DatabaseMaxOpenConns,
+		"DatabaseMaxIdleConns (%d) must not exceed DatabaseMaxOpenConns (%d)", c.DatabaseMaxIdleConns, c.DatabaseMaxOpenConns)
+	check(c.DatabaseConnMaxLife >= 0, "DatabaseConnMaxLife must not be negative")
+
+	check(oneOf(c.LogLevel, "debug", "info", "warn", "error"), "LogLevel %q must be one of debug, info, warn, error", c.LogLevel)
+
+	check(oneOf(c.TracingExporter, "none", "stdout", "file", "otlp"), "TracingExporter %q must be one of none, stdout, file, otlp", c.TracingExporter)
+	check(c.TracingExporter != "file" || c.TracingFile != "", "TracingFile is required for the file exporter")
+	check(c.TracingExporter != "otlp" || c.TracingEndpoint != "", "TracingEndpoint is required for the otlp exporter")
+
+	check(c.ShutdownTimeout > 0, "ShutdownTimeout must be positive")
+	check(oneOf(c.KycProvider, "fake"), "KycProvider %q must be fake", c.KycProvider)
+	check(oneOf(c.DocumentStore, "local", "memory"), "DocumentStore %q must be one of local, memory", c.DocumentStore)
+	check(c.DocumentStore != "local" || c.DocumentDir != "", "DocumentDir is required for the local document store")
+	check(c.MaxDocumentSize >= 0, "MaxDocumentSize must not be negative")
+	check(oneOf(c.DayCountConvention, "ACT/360", "ACT/365", "30/360"), "DayCountConvention %q must be one of ACT/360, ACT/365, 30/360", c.DayCountConvention)
+	check(c.BidCancelLockout >= 0, "BidCancelLockout must not be negative")
+	check(c.MaturityCheckInterval >= 0, "MaturityCheckInterval must not be negative")
+	check(c.DefaultAfterDays > 0, "DefaultAfterDays must be positive")
+	check(c.ReconciliationInterval >= 0, "ReconciliationInterval must not be negative")
+	check(c.WebhookDeliveryInterval >= 0, "WebhookDeliveryInterval must not be negative")
+	check(c.WebhookTimeout > 0, "WebhookTimeout must be positive")
+	check(c.WebhookRetryBackoff > 0, "WebhookRetryBackoff must be positive")
+	check(c.WebhookMaxAttempts > 0, "WebhookMaxAttempts must be positive")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validAddress(address string) bool {
+	_, port, err := net.SplitHostPort(address)
+	return err == nil && port != ""
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	config, err := Load([]string{"--config", writeFile(t, "config.json", "{}")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ":50051", config.ListenAddress)
+	assert.Equal(t, "localhost", config.DatabaseHost)
+	assert.Equal(t, "5432", config.DatabasePort)
+	assert.Equal(t, "disable", config.DatabaseSSLMode)
+	assert.Equal(t, 25, config.DatabaseMaxOpenConns)
+	assert.Equal(t, 30*time.Minute, config.DatabaseConnMaxLife)
+	assert.Equal(t, 30*time.Second, config.ShutdownTimeout)
+	assert.True(t, config.SeedMockData)
+	assert.False(t, config.TLSEnabled)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, "config.json", `{
+		"DatabaseHost": "file-host",
+		"DatabaseUser": "file-user",
+		"DatabaseName": "file-db",
+		"ShutdownTimeout": "10s"
+	}`)
+	t.Setenv(EnvPrefix+"DATABASE_USER", "env-user")
+	t.Setenv(EnvPrefix+"DATABASE_NAME", "env-db")
+	t.Setenv(EnvPrefix+"SEED_MOCK_DATA", "false")
+
+	config, err := Load([]string{"--config", path, "--database-name", "flag-db"})
+
+	assert.NoError(t, err)
+	// flags > env > file > defaults
+	assert.Equal(t, "flag-db", config.DatabaseName)
+	assert.Equal(t, "env-user", config.DatabaseUser)
+	assert.Equal(t, "file-host", config.DatabaseHost)
+	assert.Equal(t, 10*time.Second, config.ShutdownTimeout)
+	assert.Equal(t, "5432", config.DatabasePort)
+	assert.False(t, config.SeedMockData)
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	t.Setenv(EnvPrefix+"CONFIG", writeFile(t, "config.json", `{"ListenAddress": ":6000"}`))
+
+	config, err := Load(nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ":6000", config.ListenAddress)
+}
+
+func TestLoadMissingExplicitConfigFile(t *testing.T) {
+	_, err := Load([]string{"--config", filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+}
+
+func TestLoadPasswordFile(t *testing.T) {
+	path := writeFile(t, "config.json", `{"DatabasePassword": "plaintext"}`)
+	secret := writeFile(t, "db-password", "s3cret\n")
+
+	config, err := Load([]string{"--config", path, "--database-password-file", secret})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", config.DatabasePassword)
+}
+
+func TestLoadValidation(t *testing.T) {
+	path := writeFile(t, "config.json", `{
+		"ListenAddress": "50051",
+		"DatabasePort": "postgres",
+		"DatabaseSSLMode": "sometimes",
+		"DatabaseMaxOpenConns": 2,
+		"DatabaseMaxIdleConns": 5,
+		"LogLevel": "verbose",
+		"TLSEnabled": true
+	}`)
+
+	_, err := Load([]string{"--config", path})
+
+	assert.Error(t, err)
+	for _, field := range []string{"ListenAddress", "DatabasePort", "DatabaseSSLMode", "DatabaseMaxIdleConns", "LogLevel", "TLSCertFile", "TLSKeyFile"} {
+		assert.Contains(t, err.Error(), field)
+	}
+}
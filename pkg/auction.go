@@ -0,0 +1,278 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Auction types of an invoice, invoices with an auction end are timed
+const (
+	// AuctionOpen runs until a bid matches the price, its pending bids can be cancelled at any time
+	AuctionOpen = "open"
+	// AuctionTimed stops taking bids at its end, its leading bid can't be cancelled in the final minutes
+	AuctionTimed = "timed"
+)
+
+// Rules of bid cancellation, used as the violation type of a rejected cancellation
+const (
+	CancelRuleBidWon           = "BID_WON"
+	CancelRuleBidNotPending    = "BID_NOT_PENDING"
+	CancelRuleAuctionClosed    = "AUCTION_CLOSED"
+	CancelRuleLeadingBidLocked = "LEADING_BID_LOCKED"
+)
+
+// Rules of the auction of an invoice, used as the violation type of a rejected bid
+const (
+	BidRuleBelowReserve      = "BELOW_RESERVE"
+	BidRuleAboveMaximum      = "ABOVE_MAXIMUM_BID"
+	BidRuleIncrementTooSmall = "INCREMENT_TOO_SMALL"
+)
+
+// Kinds of bid events
+const (
+	// BidEventCancelled is recorded when an investor withdraws their bid
+	BidEventCancelled = "cancelled"
+	// BidEventAuctionExtended is recorded when a bid in the final seconds of a timed auction extends it
+	BidEventAuctionExtended = "auction_extended"
+)
+
+// ErrAuctionEnded is returned for bids placed after the end of a timed auction
+var ErrAuctionEnded = errors.New("auction has ended")
+
+// ErrBidNotCancellable is wrapped by every CancelBidError
+var ErrBidNotCancellable = errors.New("bid can't be cancelled")
+
+// CancelBidError is returned for cancellations that break a rule of the auction. gRPC reports it as
+// FailedPrecondition with the broken rule in a PreconditionFailure detail.
+type CancelBidError struct {
+	Rule   string
+	BidID  string
+	Reason string
+}
+
+func (e *CancelBidError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrBidNotCancellable, e.Reason)
+}
+
+func (e *CancelBidError) Unwrap() error {
+	return ErrBidNotCancellable
+}
+
+// GRPCStatus is used by grpc to turn the error into a status
+func (e *CancelBidError) GRPCStatus() *status.Status {
+	return preconditionStatus(e, e.Rule, "bid:"+e.BidID, e.Reason)
+}
+
+// ErrBidRejected is wrapped by every AuctionRuleError
+var ErrBidRejected = errors.New("bid rejected by the auction rules")
+
+// AuctionRuleError is returned for bids that break a rule of the invoice's auction. gRPC reports it as
+// FailedPrecondition with the broken rule in a PreconditionFailure detail.
+type AuctionRuleError struct {
+	Rule      string
+	InvoiceID string
+	Reason    string
+}
+
+func (e *AuctionRuleError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrBidRejected, e.Reason)
+}
+
+func (e *AuctionRuleError) Unwrap() error {
+	return ErrBidRejected
+}
+
+// GRPCStatus is used by grpc to turn the error into a status
+func (e *AuctionRuleError) GRPCStatus() *status.Status {
+	return preconditionStatus(e, e.Rule, "invoice:"+e.InvoiceID, e.Reason)
+}
+
+// ValidateAuctionRules checks the auction rules of a new invoice: the reserve and the maximum bid have to be
+// within its price, and the maximum bid can't be under the reserve
+func ValidateAuctionRules(in *pb.Invoice) error {
+	switch {
+	case in.GetReservePrice() < 0 || in.GetReservePrice() > in.GetPrice():
+		return errors.New("reserve price must be between 0 and the price")
+	case in.GetMinBidIncrement() < 0:
+		return errors.New("minimum bid increment must not be negative")
+	case in.GetMaxBid() < 0 || in.GetMaxBid() > in.GetPrice():
+		return errors.New("maximum bid must be between 0 and the price")
+	case in.GetMaxBid() > 0 && in.GetMaxBid() < in.GetReservePrice():
+		return errors.New("maximum bid must not be lower than the reserve price")
+	case in.GetAntiSnipingSeconds() < 0:
+		return errors.New("anti-sniping seconds must not be negative")
+	}
+	return nil
+}
+
+// CheckAuctionRules fails with an AuctionRuleError when the priced bid is under the hidden reserve, over the
+// maximum bid, or doesn't top the best bid (nil without bids) by the minimum increment. The reserve isn't
+// disclosed in the reason.
+func CheckAuctionRules(in *pb.Bid, terms invoiceTerms, best *bestBid) error {
+	amount := float64(in.GetAmount())
+	maxBid := terms.maxBid
+	if maxBid == 0 {
+		maxBid = terms.price
+	}
+	var ruleErr *AuctionRuleError
+	switch {
+	case amount < terms.reservePrice:
+		ruleErr = &AuctionRuleError{Rule: BidRuleBelowReserve, Reason: "bid is below the reserve price"}
+	case amount > maxBid:
+		ruleErr = &AuctionRuleError{Rule: BidRuleAboveMaximum, Reason: fmt.Sprintf("bid of %.2f is over the maximum bid of %.2f", amount, maxBid)}
+	case best != nil && terms.minBidIncrement > 0 && roundCents(amount) < roundCents(best.amount+terms.minBidIncrement):
+		ruleErr = &AuctionRuleError{Rule: BidRuleIncrementTooSmall, Reason: fmt.Sprintf("bid has to be at least %.2f, the best bid plus the minimum increment of %.2f",
+			best.amount+terms.minBidIncrement, terms.minBidIncrement)}
+	default:
+		return nil
+	}
+	ruleErr.InvoiceID = in.GetInvoiceId()
+	bidsRejectedByRule.WithLabelValues(ruleErr.Rule).Inc()
+	return ruleErr
+}
+
+// auctionExtension returns the new end of a timed auction when a bid at now lands in its final anti-sniping
+// seconds: that many seconds after the bid
+func auctionExtension(terms invoiceTerms, now time.Time) (time.Time, bool) {
+	window := time.Duration(terms.antiSnipingSeconds) * time.Second
+	if auctionType(terms.auctionEndsAt) != AuctionTimed || window <= 0 || terms.auctionEndsAt.Time.Sub(now) >= window {
+		return time.Time{}, false
+	}
+	return now.Add(window), true
+}
+
+// ExtendAuction moves the end of the timed auction of the bid's invoice and records the extension as an event
+// of the bid. An end already further out is kept.
+func ExtendAuction(ctx context.Context, db dbtx, bid *pb.Bid, endsAt time.Time) (err error) {
+	ctx, span := startSpan(ctx, "ExtendAuction", bid)
+	defer func() { endSpan(span, err) }()
+	log.Printf("Extending the auction of invoice %s to %s", bid.GetInvoiceId(), endsAt.Format(time.RFC3339))
+	_, err = db.ExecContext(ctx, "UPDATE invoice SET auction_ends_at = $2 WHERE id = $1 AND auction_ends_at < $2", bid.GetInvoiceId(), endsAt)
+	if err != nil {
+		return fmt.Errorf("failed to extend auction: %w", err)
+	}
+	return RecordBidEvent(ctx, db, bid, BidEventAuctionExtended, "auction extended to "+endsAt.Format(time.RFC3339))
+}
+
+// auctionType returns the type of an auction from its end
+func auctionType(endsAt sql.NullTime) string {
+	if endsAt.Valid {
+		return AuctionTimed
+	}
+	return AuctionOpen
+}
+
+// auctionEnded tells whether a timed auction stopped taking bids
+func auctionEnded(endsAt sql.NullTime, now time.Time) bool {
+	return endsAt.Valid && !now.Before(endsAt.Time)
+}
+
+// bidCancellation is a bid with the state of the auction its cancellation is checked against
+type bidCancellation struct {
+	bid           *pb.Bid
+	invoiceStatus string
+	auctionEndsAt sql.NullTime
+	// leading is set when the bid is the best pending bid of its invoice
+	leading bool
+}
+
+// GetBidForCancellation returns the bid with its auction and locks the bid until the end of the transaction
+func GetBidForCancellation(ctx context.Context, db dbtx, bidID string) (c bidCancellation, err error) {
+	ctx, span := startSpan(ctx, "GetBidForCancellation", nil)
+	defer func() { endSpan(span, err) }()
+	c.bid = &pb.Bid{}
+	err = db.QueryRowContext(ctx, `SELECT bid.id, bid.investor_id, bid.invoice_id, bid.amount, bid.status, COALESCE(bid.rate, 0), COALESCE(bid.rate_type, ''),
+			COALESCE(bid.day_count, ''), COALESCE(bid.effective_yield, 0), invoice.status, invoice.auction_ends_at,
+			COALESCE(bid.id = (SELECT id FROM bid leading WHERE leading.invoice_id = bid.invoice_id AND leading.status = 'pending' `+bestBidOrder+` LIMIT 1), false)
+		FROM bid JOIN invoice ON invoice.id = bid.invoice_id
+		WHERE bid.id = $1
+		FOR UPDATE OF bid`, bidID).
+		Scan(&c.bid.Id, &c.bid.InvestorId, &c.bid.InvoiceId, &c.bid.Amount, &c.bid.Status, &c.bid.Rate, &c.bid.RateType,
+			&c.bid.DayCount, &c.bid.EffectiveYield, &c.invoiceStatus, &c.auctionEndsAt, &c.leading)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c, status.Errorf(codes.NotFound, "bid %s not found", bidID)
+		}
+		return c, fmt.Errorf("failed to get bid: %w", err)
+	}
+	return c, nil
+}
+
+// CheckBidCancellation fails with a CancelBidError when the bid can't be withdrawn at now: bids that won or are
+// no longer pending, bids on closed auctions and, in a timed auction, the leading bid once fewer than lockout
+// remain before the end
+func CheckBidCancellation(c bidCancellation, now time.Time, lockout time.Duration) error {
+	bid := c.bid
+	switch {
+	case bid.GetStatus() == "approved":
+		return &CancelBidError{Rule: CancelRuleBidWon, BidID: bid.GetId(),
+			Reason: fmt.Sprintf("bid won the auction of invoice %s", bid.GetInvoiceId())}
+	case bid.GetStatus() != "pending":
+		return &CancelBidError{Rule: CancelRuleBidNotPending, BidID: bid.GetId(),
+			Reason: fmt.Sprintf("bid is already %s", bid.GetStatus())}
+	case c.invoiceStatus != "open" || auctionEnded(c.auctionEndsAt, now):
+		return &CancelBidError{Rule: CancelRuleAuctionClosed, BidID: bid.GetId(),
+			Reason: fmt.Sprintf("auction of invoice %s is closed", bid.GetInvoiceId())}
+	}
+	if auctionType(c.auctionEndsAt) == AuctionTimed && c.leading && c.auctionEndsAt.Time.Sub(now) < lockout {
+		return &CancelBidError{Rule: CancelRuleLeadingBidLocked, BidID: bid.GetId(),
+			Reason: fmt.Sprintf("the leading bid can't be cancelled in the final %s of the auction", lockout)}
+	}
+	return nil
+}
+
+// CancelPendingBid marks the bid cancelled, returns its funds to the investor with a refund ledger entry and
+// records the cancellation event. Run it in a transaction.
+func CancelPendingBid(ctx context.Context, db dbtx, bid *pb.Bid, reason string) (err error) {
+	ctx, span := startSpan(ctx, "CancelPendingBid", bid)
+	defer func() { endSpan(span, err) }()
+	log.Printf("Cancelling bid %s of investor %s", bid.GetId(), bid.GetInvestorId())
+	// Only a bid this UPDATE takes out of pending is refunded, with the amount it held
+	var amount float32
+	err = db.QueryRowContext(ctx, "UPDATE bid SET status = 'cancelled', closed_at = now() WHERE id = $1 AND status = 'pending' RETURNING amount",
+		bid.GetId()).Scan(&amount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &CancelBidError{Rule: CancelRuleBidNotPending, BidID: bid.GetId(), Reason: "bid is no longer pending"}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel bid: %w", err)
+	}
+	bid.Status, bid.Amount = "cancelled", amount
+
+	_, err = db.ExecContext(ctx, "UPDATE investor SET balance = balance + $1 WHERE id = $2", bid.GetAmount(), bid.GetInvestorId())
+	if err != nil {
+		return fmt.Errorf("failed to refund bid: %w", err)
+	}
+	_, err = db.ExecContext(ctx, "INSERT INTO ledger (account_type, account_id, kind, amount) VALUES ('investor', $1, 'refund', $2)",
+		bid.GetInvestorId(), bid.GetAmount())
+	if err != nil {
+		return fmt.Errorf("failed to record refund: %w", err)
+	}
+	// The invoice no longer has a leading investor
+	_, err = db.ExecContext(ctx, "UPDATE invoice SET investor_id = NULL WHERE id = $1 AND status = 'open' AND investor_id = $2",
+		bid.GetInvoiceId(), bid.GetInvestorId())
+	if err != nil {
+		return fmt.Errorf("failed to update investor in invoice: %w", err)
+	}
+	return RecordBidEvent(ctx, db, bid, BidEventCancelled, reason)
+}
+
+// RecordBidEvent stores an event in the history of the bid
+func RecordBidEvent(ctx context.Context, db dbtx, bid *pb.Bid, kind, reason string) (err error) {
+	ctx, span := startSpan(ctx, "RecordBidEvent", bid)
+	defer func() { endSpan(span, err) }()
+	_, err = db.ExecContext(ctx, "INSERT INTO bid_event (bid_id, invoice_id, investor_id, kind, amount, reason) VALUES ($1, $2, $3, $4, $5, $6)",
+		bid.GetId(), bid.GetInvoiceId(), bid.GetInvestorId(), kind, bid.GetAmount(), reason)
+	if err != nil {
+		return fmt.Errorf("failed to record bid event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// expectBidForCancellation expects the lookup of the bid, on an invoice whose auction ends at endsAt (nil for open)
+func expectBidForCancellation(mock sqlmock.Sqlmock, bidStatus string, endsAt any, leading bool) {
+	mock.ExpectQuery("SELECT bid.id, (.+) FROM bid JOIN invoice ON invoice.id = bid.invoice_id WHERE bid.id = \\$1 FOR UPDATE OF bid").
+		WithArgs("bid-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "investor_id", "invoice_id", "amount", "status", "rate", "rate_type", "day_count",
+			"effective_yield", "invoice_status", "auction_ends_at", "leading"}).
+			AddRow("bid-id", "investor-id", "invoice-id", 900.0, bidStatus, 0.0, "", DayCountACT360, 8.5, "open", endsAt, leading))
+}
+
+func TestCancelBid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, bidCancelLockout: 5 * time.Minute}
+
+	mock.ExpectBegin()
+	// The leading bid of a timed auction can be cancelled well before its end
+	expectBidForCancellation(mock, "pending", time.Now().UTC().Add(time.Hour), true)
+	mock.ExpectQuery("UPDATE bid SET status = 'cancelled', closed_at = now\\(\\) WHERE id = \\$1 AND status = 'pending' RETURNING amount").WithArgs("bid-id").
+		WillReturnRows(sqlmock.NewRows([]string{"amount"}).AddRow(900.0))
+	mock.ExpectExec("UPDATE investor SET balance = balance \\+ \\$1 WHERE id = \\$2").WithArgs(float32(900), "investor-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ledger \\(account_type, account_id, kind, amount\\) VALUES \\('investor', \\$1, 'refund', \\$2\\)").
+		WithArgs("investor-id", float32(900)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE invoice SET investor_id = NULL").WithArgs("invoice-id", "investor-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO bid_event").WithArgs("bid-id", "invoice-id", "investor-id", BidEventCancelled, float32(900), "changed my mind").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	bid, err := s.CancelBid(asParty(PartyInvestor, "investor-id"), &pb.BidCancellation{BidId: "bid-id", Reason: "changed my mind"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", bid.Status)
+	assert.Equal(t, float32(900), bid.Amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelBidOfAnotherInvestor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	expectBidForCancellation(mock, "pending", nil, true)
+	mock.ExpectRollback()
+
+	// The investor is the caller of the token, echoing the bid's investor doesn't help
+	_, err = s.CancelBid(asParty(PartyInvestor, "other-id"), &pb.BidCancellation{BidId: "bid-id", InvestorId: "investor-id"})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Nothing is read without an investor token
+	_, err = s.CancelBid(context.Background(), &pb.BidCancellation{BidId: "bid-id", InvestorId: "investor-id"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.CancelBid(asParty(PartyIssuer, "investor-id"), &pb.BidCancellation{BidId: "bid-id", InvestorId: "investor-id"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelBidNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM bid JOIN invoice").WithArgs("bid-id").WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = s.CancelBid(asParty(PartyInvestor, "investor-id"), &pb.BidCancellation{BidId: "bid-id"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = s.CancelBid(asParty(PartyInvestor, "investor-id"), &pb.BidCancellation{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCancelBidWon(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	expectBidForCancellation(mock, "approved", nil, false)
+	mock.ExpectRollback()
+
+	bid, err := s.CancelBid(asParty(PartyInvestor, "investor-id"), &pb.BidCancellation{BidId: "bid-id"})
+
+	// Nothing is refunded for a bid that won
+	assert.Nil(t, bid)
+	assert.True(t, errors.Is(err, ErrBidNotCancellable))
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Len(t, st.Details(), 1)
+	assert.Equal(t, CancelRuleBidWon, st.Details()[0].(*errdetails.PreconditionFailure).Violations[0].Type)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelPendingBidAlreadyClosed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// The bid was closed by a trade after it was read, its funds were already returned
+	mock.ExpectQuery("UPDATE bid SET status = 'cancelled'").WithArgs("bid-id").WillReturnRows(sqlmock.NewRows([]string{"amount"}))
+
+	err = CancelPendingBid(context.Background(), db, &pb.Bid{Id: "bid-id", InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 900}, "")
+
+	assert.True(t, errors.Is(err, ErrBidNotCancellable))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckBidCancellation(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	endsIn := func(d time.Duration) sql.NullTime { return sql.NullTime{Time: now.Add(d), Valid: true} }
+	tests := []struct {
+		name    string
+		status  string
+		invoice string
+		endsAt  sql.NullTime
+		leading bool
+		rule    string
+	}{
+		{"open auction", "pending", "open", sql.NullTime{}, true, ""},
+		{"won", "approved", "closed", sql.NullTime{}, false, CancelRuleBidWon},
+		{"outbid", "closed", "open", sql.NullTime{}, false, CancelRuleBidNotPending},
+		{"already cancelled", "cancelled", "open", sql.NullTime{}, false, CancelRuleBidNotPending},
+		{"invoice closed", "pending", "closed", sql.NullTime{}, true, CancelRuleAuctionClosed},
+		{"auction ended", "pending", "open", endsIn(-time.Second), false, CancelRuleAuctionClosed},
+		{"leading bid before the lockout", "pending", "open", endsIn(10 * time.Minute), true, ""},
+		{"leading bid in the lockout", "pending", "open", endsIn(4 * time.Minute), true, CancelRuleLeadingBidLocked},
+		{"trailing bid in the lockout", "pending", "open", endsIn(4 * time.Minute), false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := bidCancellation{
+				bid:           &pb.Bid{Id: "bid-id", InvoiceId: "invoice-id", Status: tt.status},
+				invoiceStatus: tt.invoice,
+				auctionEndsAt: tt.endsAt,
+				leading:       tt.leading,
+			}
+			err := CheckBidCancellation(c, now, 5*time.Minute)
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var cancelErr *CancelBidError
+			assert.True(t, errors.As(err, &cancelErr))
+			assert.Equal(t, tt.rule, cancelErr.Rule)
+		})
+	}
+}
+
+func TestPlaceBidAfterAuctionEnd(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, dayCount: DayCountACT360}
+
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{faceValue: 1000, price: 1000, auctionEndsAt: sql.NullTime{Time: time.Now().UTC().Add(-time.Minute), Valid: true}})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycVerified)
+	mock.ExpectRollback()
+
+	bid, err := s.PlaceBid(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 900})
+
+	assert.Nil(t, bid)
+	assert.ErrorIs(t, err, ErrAuctionEnded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceBidOnClosedInvoice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, dayCount: DayCountACT360}
+
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{status: "closed", faceValue: 1000, price: 1000})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycVerified)
+	mock.ExpectRollback()
+
+	bid, err := s.PlaceBid(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 1000})
+
+	assert.Nil(t, bid)
+	assert.ErrorIs(t, err, ErrInvoiceNotOpen)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckAuctionRules(t *testing.T) {
+	terms := invoiceTerms{faceValue: 1000, price: 1000, reservePrice: 800, minBidIncrement: 10}
+	best := &bestBid{amount: 850}
+	tests := []struct {
+		name   string
+		amount float32
+		terms  invoiceTerms
+		best   *bestBid
+		rule   string
+	}{
+		{"first bid", 800, terms, nil, ""},
+		{"below the reserve", 799.99, terms, nil, BidRuleBelowReserve},
+		{"at the increment", 860, terms, best, ""},
+		{"under the increment", 859.99, terms, best, BidRuleIncrementTooSmall},
+		{"at the price", 1000, terms, best, ""},
+		{"over the price", 1000.01, terms, best, BidRuleAboveMaximum},
+		{"over the maximum bid", 960, invoiceTerms{price: 1000, maxBid: 950}, nil, BidRuleAboveMaximum},
+		{"without rules", 1, invoiceTerms{price: 1000}, &bestBid{amount: 0.5}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckAuctionRules(&pb.Bid{InvoiceId: "invoice-id", Amount: tt.amount}, tt.terms, tt.best)
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var ruleErr *AuctionRuleError
+			assert.True(t, errors.As(err, &ruleErr))
+			assert.Equal(t, tt.rule, ruleErr.Rule)
+			st := status.Convert(err)
+			assert.Equal(t, codes.FailedPrecondition, st.Code())
+			assert.Equal(t, "invoice:invoice-id", st.Details()[0].(*errdetails.PreconditionFailure).Violations[0].Subject)
+		})
+	}
+}
+
+func TestCheckAuctionRulesHidesReserve(t *testing.T) {
+	err := CheckAuctionRules(&pb.Bid{Amount: 700}, invoiceTerms{price: 1000, reservePrice: 812.5}, nil)
+
+	assert.ErrorIs(t, err, ErrBidRejected)
+	assert.NotContains(t, err.Error(), "812")
+}
+
+func TestAuctionExtension(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	endsIn := func(d time.Duration) sql.NullTime { return sql.NullTime{Time: now.Add(d), Valid: true} }
+
+	// A bid 20 seconds before the end of a 60 second window moves the end to a minute after the bid
+	endsAt, extend := auctionExtension(invoiceTerms{auctionEndsAt: endsIn(20 * time.Second), antiSnipingSeconds: 60}, now)
+	assert.True(t, extend)
+	assert.Equal(t, now.Add(time.Minute), endsAt)
+
+	_, extend = auctionExtension(invoiceTerms{auctionEndsAt: endsIn(2 * time.Minute), antiSnipingSeconds: 60}, now)
+	assert.False(t, extend)
+	_, extend = auctionExtension(invoiceTerms{auctionEndsAt: endsIn(20 * time.Second)}, now)
+	assert.False(t, extend)
+	_, extend = auctionExtension(invoiceTerms{antiSnipingSeconds: 60}, now)
+	assert.False(t, extend)
+}
+
+func TestExtendAuction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	endsAt := time.Date(2024, 3, 1, 12, 1, 0, 0, time.UTC)
+	bid := &pb.Bid{Id: "bid-id", InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 900}
+
+	mock.ExpectExec("UPDATE invoice SET auction_ends_at = \\$2 WHERE id = \\$1 AND auction_ends_at < \\$2").WithArgs("invoice-id", endsAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO bid_event").
+		WithArgs("bid-id", "invoice-id", "investor-id", BidEventAuctionExtended, float32(900), "auction extended to 2024-03-01T12:01:00Z").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, ExtendAuction(context.Background(), db, bid, endsAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceBidBelowReserve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, dayCount: DayCountACT360}
+
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{faceValue: 1000, price: 1000, reservePrice: 950})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycVerified)
+	mock.ExpectQuery("SELECT amount, effective_yield FROM bid WHERE invoice_id = \\$1 AND status = 'pending'").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"amount", "effective_yield"}))
+	mock.ExpectRollback()
+
+	bid, err := s.PlaceBid(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 900})
+
+	// Nothing is reserved for a rejected bid
+	assert.Nil(t, bid)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.ErrorIs(t, err, ErrBidRejected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,196 @@
+package pkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RoleAdmin is the role of the operators managing the platform, the other callers act as an issuer or an investor
+const RoleAdmin = "admin"
+
+// adminMethods are the RPCs only admins may call
+var adminMethods = map[string]bool{
+	pb.InvoiceService_SetInvestorTier_FullMethodName:    true,
+	pb.InvoiceService_ListInvestorTiers_FullMethodName:  true,
+	pb.InvoiceService_AssignInvestorTier_FullMethodName: true,
+	pb.InvoiceService_VerifyKyc_FullMethodName:          true,
+	pb.InvoiceService_ReviewKyc_FullMethodName:          true,
+}
+
+// Principal is the caller a bearer token authenticates: an admin, or the issuer or investor PartyID. The PartyID of
+// an admin names the operator, e.g. in the KYC audit trail.
+type Principal struct {
+	Role    string `json:"role"`
+	PartyID string `json:"party_id"`
+}
+
+// authToken is a token of the token file, only its SHA-256 digest is stored
+type authToken struct {
+	SHA256 string `json:"sha256"`
+	Principal
+}
+
+// Authenticator maps the SHA-256 digests of the bearer tokens to the principals they authenticate
+type Authenticator struct {
+	tokens map[string]Principal
+}
+
+// LoadAuthTokens reads the bearer tokens from a JSON file. Without a file every token is rejected, so only the RPCs
+// open to anonymous callers can be used.
+func LoadAuthTokens(path string) (*Authenticator, error) {
+	auth := &Authenticator{tokens: map[string]Principal{}}
+	if path == "" {
+		return auth, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth token file: %w", err)
+	}
+	var file struct {
+		Tokens []authToken `json:"tokens"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse auth token file %s: %w", path, err)
+	}
+	var errs []error
+	for i, token := range file.Tokens {
+		digest := strings.ToLower(token.SHA256)
+		if b, err := hex.DecodeString(digest); err != nil || len(b) != sha256.Size {
+			errs = append(errs, fmt.Errorf("token %d: sha256 must be a hex encoded SHA-256 digest", i+1))
+		}
+		switch token.Role {
+		case RoleAdmin:
+		case PartyIssuer, PartyInvestor:
+			if token.PartyID == "" {
+				errs = append(errs, fmt.Errorf("token %d: party_id is required for role %s", i+1, token.Role))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("token %d: role %q must be admin, issuer or investor", i+1, token.Role))
+		}
+		auth.tokens[digest] = token.Principal
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid auth tokens: %w", errors.Join(errs...))
+	}
+	return auth, nil
+}
+
+// authenticate returns the principal of the bearer token in the authorization metadata, nil for anonymous callers
+func (a *Authenticator) authenticate(ctx context.Context) (*Principal, error) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return nil, nil
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+	digest := sha256.Sum256([]byte(token))
+	principal, ok := a.tokens[hex.EncodeToString(digest[:])]
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unknown bearer token")
+	}
+	return &principal, nil
+}
+
+// authorize authenticates the caller of method and returns ctx carrying its principal
+func (a *Authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	principal, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if adminMethods[method] {
+		if principal == nil {
+			return nil, status.Error(codes.Unauthenticated, "a bearer token is required")
+		}
+		if principal.Role != RoleAdmin {
+			return nil, status.Errorf(codes.PermissionDenied, "%s may only be called by admins", method)
+		}
+	}
+	if principal == nil {
+		return ctx, nil
+	}
+	return withPrincipal(ctx, principal), nil
+}
+
+type principalKey struct{}
+
+// withPrincipal returns ctx carrying the authenticated caller
+func withPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller of the RPC, nil for anonymous callers
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// InvestorCaller returns the investor calling an RPC on their own bids or rules, as authenticated by their bearer
+// token
+func InvestorCaller(ctx context.Context) (*Principal, error) {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, status.Error(codes.Unauthenticated, "a bearer token of an investor is required")
+	}
+	if principal.Role != PartyInvestor {
+		return nil, status.Errorf(codes.PermissionDenied, "only investors act on their bids and auto-bid rules, not %s", principal.Role)
+	}
+	return principal, nil
+}
+
+// PartyCaller returns the caller of an RPC acting for the party partyType partyID, as authenticated by their bearer
+// token: the party itself, or an admin when admins is set
+func PartyCaller(ctx context.Context, partyType, partyID string, admins bool) (*Principal, error) {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, status.Error(codes.Unauthenticated, "a bearer token is required")
+	}
+	if admins && principal.Role == RoleAdmin {
+		return principal, nil
+	}
+	if principal.Role != partyType || principal.PartyID != partyID {
+		return nil, status.Errorf(codes.PermissionDenied, "%s %s may not act for %s %s", principal.Role, principal.PartyID, partyType, partyID)
+	}
+	return principal, nil
+}
+
+// unaryInterceptor rejects unauthenticated and unauthorized unary calls
+func (a *Authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor rejects unauthenticated and unauthorized streaming calls
+func (a *Authenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// authServerStream is a server stream whose context carries the caller's principal
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
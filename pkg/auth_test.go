@@ -0,0 +1,124 @@
+package pkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func tokenDigest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// writeAuthTokens writes a token file with an admin token, an issuer token for issuer-id and an investor token for
+// investor-id and returns its path
+func writeAuthTokens(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"tokens": [
+		{"sha256": "`+tokenDigest("admin-token")+`", "role": "admin"},
+		{"sha256": "`+tokenDigest("issuer-token")+`", "role": "issuer", "party_id": "issuer-id"},
+		{"sha256": "`+tokenDigest("investor-token")+`", "role": "investor", "party_id": "investor-id"}
+	]}`), 0o600))
+	return path
+}
+
+// withToken returns a context sending the bearer token
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// asParty returns the context of an RPC authenticated as the party
+func asParty(partyType, partyID string) context.Context {
+	return withPrincipal(context.Background(), &Principal{Role: partyType, PartyID: partyID})
+}
+
+func TestPartyCaller(t *testing.T) {
+	_, err := PartyCaller(context.Background(), PartyInvestor, "investor-id", true)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = PartyCaller(asParty(PartyInvestor, "other-investor-id"), PartyInvestor, "investor-id", true)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = PartyCaller(asParty(PartyIssuer, "investor-id"), PartyInvestor, "investor-id", true)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = PartyCaller(asParty(RoleAdmin, ""), PartyInvestor, "investor-id", false)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	principal, err := PartyCaller(asParty(RoleAdmin, ""), PartyInvestor, "investor-id", true)
+	assert.NoError(t, err)
+	assert.Equal(t, RoleAdmin, principal.Role)
+	principal, err = PartyCaller(asParty(PartyInvestor, "investor-id"), PartyInvestor, "investor-id", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "investor-id", principal.PartyID)
+}
+
+func TestLoadAuthTokensValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"tokens": [
+		{"sha256": "admin-token", "role": "admin"},
+		{"sha256": "`+tokenDigest("investor-token")+`", "role": "investor"},
+		{"sha256": "`+tokenDigest("root-token")+`", "role": "root"}
+	]}`), 0o600))
+
+	_, err := LoadAuthTokens(path)
+
+	assert.ErrorContains(t, err, "token 1: sha256 must be a hex encoded SHA-256 digest")
+	assert.ErrorContains(t, err, "token 2: party_id is required for role investor")
+	assert.ErrorContains(t, err, `token 3: role "root" must be admin, issuer or investor`)
+}
+
+func TestAdminRPCsRequireAdmin(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s, _, _, err := SetupServer(db, &cfg.Config{AuthTokenFile: writeAuthTokens(t)})
+	assert.NoError(t, err)
+	go s.Serve(lis)
+	defer s.Stop()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewInvoiceServiceClient(conn)
+	tier := &pb.InvestorTier{Name: "retail", MaxIssuerConcentration: 25, MaxInvoiceAmount: 500}
+
+	// Investors can't raise their own limits, and nothing reaches the database
+	_, err = client.SetInvestorTier(withToken("investor-token"), tier)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.AssignInvestorTier(withToken("issuer-token"), &pb.Investor{Id: "investor-id", Tier: "gold"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.SetInvestorTier(context.Background(), tier)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.SetInvestorTier(withToken("guessed-token"), tier)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	// Only admins decide the onboarding state
+	_, err = client.VerifyKyc(withToken("issuer-token"), &pb.KycParty{PartyType: PartyIssuer, PartyId: "issuer-id"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.ReviewKyc(context.Background(), &pb.KycReview{PartyType: PartyInvestor, PartyId: "investor-id", Status: KycVerified, Reason: "reason"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	mock.ExpectExec("INSERT INTO investor_tier").WithArgs("retail", float32(25), float32(500)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err = client.SetInvestorTier(withToken("admin-token"), tier)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
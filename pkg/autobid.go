@@ -0,0 +1,374 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Events that make the auto-bid rules look at an invoice
+const (
+	// AutoBidTriggerListing runs every active rule against a newly listed invoice
+	AutoBidTriggerListing = "listing"
+	// AutoBidTriggerOutbid runs the active rules of the investors whose bids were just beaten
+	AutoBidTriggerOutbid = "outbid"
+)
+
+// Outcomes of an auto-bid execution
+const (
+	AutoBidPlaced  = "placed"
+	AutoBidSkipped = "skipped"
+	AutoBidFailed  = "failed"
+)
+
+// Statuses of an auto-bid rule, only active rules bid
+const (
+	AutoBidRuleActive = "active"
+	AutoBidRulePaused = "paused"
+)
+
+// autoBidQueueSize is the number of triggers waiting for the auto-bidder before new ones are dropped
+const autoBidQueueSize = 1024
+
+// defaultAutoBidExecutionLimit is the number of executions listed when the request sets no limit
+const defaultAutoBidExecutionLimit = 100
+
+// autoBidRuleColumns are scanned by scanAutoBidRule. committed sums the bids of the rule that still hold its
+// budget: pending bids and won bids until their invoice is repaid.
+const autoBidRuleColumns = `auto_bid_rule.id, auto_bid_rule.investor_id, auto_bid_rule.issuer_grades, auto_bid_rule.max_tenor_days,
+	auto_bid_rule.min_yield, auto_bid_rule.max_amount_per_invoice, auto_bid_rule.total_budget, auto_bid_rule.status,
+	COALESCE((SELECT SUM(bid.amount) FROM auto_bid_execution execution
+		JOIN bid ON bid.id = execution.bid_id JOIN invoice ON invoice.id = bid.invoice_id
+		WHERE execution.rule_id = auto_bid_rule.id
+		AND (bid.status = 'pending' OR (bid.status = 'approved' AND invoice.status IN ('closed', 'overdue', 'defaulted')))), 0),
+	to_char(auto_bid_rule.created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+// AutoBidTrigger asks the auto-bidder to run the rules against an invoice. Outbid triggers only run the
+// rules of InvestorIDs.
+type AutoBidTrigger struct {
+	InvoiceID   string
+	Trigger     string
+	InvestorIDs []string
+}
+
+// AutoBidder places the bids of the auto-bid rules in the background, through the same checks as PlaceBid.
+// Triggers are queued by the RPCs and handled one at a time by Run.
+type AutoBidder struct {
+	s     *server
+	queue chan AutoBidTrigger
+}
+
+func newAutoBidder(s *server) *AutoBidder {
+	return &AutoBidder{s: s, queue: make(chan AutoBidTrigger, autoBidQueueSize)}
+}
+
+// Enqueue queues the trigger without blocking the caller. When the queue is full the trigger is dropped, which is
+// counted and logged as those rules won't look at the invoice.
+func (a *AutoBidder) Enqueue(t AutoBidTrigger) {
+	if a == nil || (t.Trigger == AutoBidTriggerOutbid && len(t.InvestorIDs) == 0) {
+		return
+	}
+	select {
+	case a.queue <- t:
+	default:
+		autoBidTriggersDropped.WithLabelValues(t.Trigger).Inc()
+		slog.Warn("Auto-bid queue is full, dropping trigger", "trigger", t.Trigger, "invoice_id", t.InvoiceID, "investor_ids", t.InvestorIDs)
+	}
+}
+
+// Run handles the queued triggers until ctx is cancelled
+func (a *AutoBidder) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-a.queue:
+			if err := a.execute(ctx, t); err != nil {
+				slog.Error("Error running auto-bid rules", "invoice_id", t.InvoiceID, "err", err)
+			}
+		}
+	}
+}
+
+// execute runs the matching rules against the invoice in the order they were created, recording every
+// execution. It stops once a bid wins the invoice.
+func (a *AutoBidder) execute(ctx context.Context, t AutoBidTrigger) error {
+	db := a.s.db
+	invoice, err := GetAutoBidInvoice(ctx, db, t.InvoiceID)
+	if err != nil {
+		return err
+	}
+	if invoice.status != "open" || auctionEnded(invoice.auctionEndsAt, time.Now().UTC()) {
+		return nil
+	}
+	rules, err := GetActiveAutoBidRules(ctx, db, t.InvestorIDs, t.Trigger == AutoBidTriggerOutbid)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	issuer := &pb.Issuer{Id: invoice.issuerID}
+	if err := a.s.risk.AssessIssuer(ctx, db, issuer); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		execution := &pb.AutoBidExecution{RuleId: rule.GetId(), InvoiceId: t.InvoiceID, Trigger: t.Trigger}
+		amount, reason := planAutoBid(rule, invoice, issuer.GetRiskGrade(), time.Now().UTC(), a.s.dayCount)
+		won := false
+		if reason != "" {
+			execution.Outcome = AutoBidSkipped
+			execution.Message = reason
+		} else {
+			bid := &pb.Bid{InvestorId: rule.GetInvestorId(), InvoiceId: t.InvoiceID, Amount: float32(amount)}
+			execution.Amount = bid.GetAmount()
+			outbid, err := a.s.placeBid(ctx, bid)
+			switch {
+			case errors.Is(err, ErrBidNotBetter), errors.Is(err, ErrBidRejected):
+				execution.Outcome = AutoBidSkipped
+				execution.Message = err.Error()
+			case err != nil:
+				execution.Outcome = AutoBidFailed
+				execution.Message = err.Error()
+			default:
+				execution.Outcome = AutoBidPlaced
+				execution.BidId = bid.GetId()
+				invoice.leadingInvestorID = rule.GetInvestorId()
+				won = bid.GetStatus() == "approved"
+				a.Enqueue(AutoBidTrigger{InvoiceID: t.InvoiceID, Trigger: AutoBidTriggerOutbid, InvestorIDs: outbid})
+			}
+		}
+		if err := InsertAutoBidExecution(ctx, db, execution); err != nil {
+			return err
+		}
+		autoBidsExecuted.WithLabelValues(execution.GetOutcome()).Inc()
+		if won {
+			break
+		}
+	}
+	return nil
+}
+
+// autoBidInvoice is the part of an invoice the auto-bid rules look at
+type autoBidInvoice struct {
+	status        string
+	issuerID      string
+	price         float64
+	maxBid        float64
+	faceValue     float64
+	dueDate       string
+	auctionEndsAt sql.NullTime
+	// leadingInvestorID is the investor of the best pending bid, empty without bids
+	leadingInvestorID string
+}
+
+// planAutoBid returns the amount the rule bids on the invoice, or why it skips it. The rule bids the asking
+// price when its limits allow it, otherwise the most it can: the advance earning its minimum yield, its cap per
+// invoice, what is left of its budget or the invoice's maximum bid.
+func planAutoBid(rule *pb.AutoBidRule, invoice autoBidInvoice, grade string, today time.Time, dayCount string) (amount float64, reason string) {
+	if len(rule.GetIssuerGrades()) > 0 && !containsString(rule.GetIssuerGrades(), grade) {
+		return 0, fmt.Sprintf("issuer grade %s isn't accepted", grade)
+	}
+	if invoice.leadingInvestorID != "" && invoice.leadingInvestorID == rule.GetInvestorId() {
+		return 0, "investor already holds the best bid"
+	}
+	budget := float64(rule.GetTotalBudget() - rule.GetCommitted())
+	if budget < 0.01 {
+		return 0, fmt.Sprintf("budget of %.2f is used up", rule.GetTotalBudget())
+	}
+
+	limit := math.Min(float64(rule.GetMaxAmountPerInvoice()), budget)
+	if invoice.dueDate == "" {
+		if rule.GetMaxTenorDays() > 0 || rule.GetMinYield() > 0 {
+			return 0, "invoice has no due date"
+		}
+	} else {
+		due, err := time.Parse(dueDateLayout, invoice.dueDate)
+		if err != nil {
+			return 0, fmt.Sprintf("invalid invoice due date: %v", err)
+		}
+		today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+		if !due.After(today) {
+			return 0, "invoice is already due"
+		}
+		if days := actualDays(today, due); rule.GetMaxTenorDays() > 0 && days > float64(rule.GetMaxTenorDays()) {
+			return 0, fmt.Sprintf("tenor of %.0f days is over %d", days, rule.GetMaxTenorDays())
+		}
+		if rule.GetMinYield() > 0 {
+			t, err := YearFraction(dayCount, today, due)
+			if err != nil {
+				return 0, err.Error()
+			}
+			limit = math.Min(limit, invoice.faceValue/(1+float64(rule.GetMinYield())/100*t))
+		}
+	}
+
+	if invoice.maxBid > 0 && invoice.maxBid < invoice.price {
+		limit = math.Min(limit, invoice.maxBid)
+	}
+	if limit >= invoice.price {
+		return invoice.price, ""
+	}
+	// Rounding down keeps the bid within every limit
+	return math.Floor(limit*100) / 100, ""
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAutoBidRule checks a rule before it is stored, its grades have to be grades of the risk model
+func ValidateAutoBidRule(in *pb.AutoBidRule, risk *RiskModel) error {
+	if in.GetInvestorId() == "" {
+		return status.Error(codes.InvalidArgument, "investor id is required")
+	}
+	for _, grade := range in.GetIssuerGrades() {
+		known := false
+		for _, g := range risk.Grades {
+			known = known || g.Grade == grade
+		}
+		if !known {
+			return status.Errorf(codes.InvalidArgument, "unknown issuer grade %q", grade)
+		}
+	}
+	if in.GetMaxTenorDays() < 0 {
+		return status.Error(codes.InvalidArgument, "max tenor days must not be negative")
+	}
+	if in.GetMinYield() < 0 {
+		return status.Error(codes.InvalidArgument, "min yield must not be negative")
+	}
+	if in.GetMaxAmountPerInvoice() <= 0 {
+		return status.Error(codes.InvalidArgument, "max amount per invoice must be greater than 0")
+	}
+	if in.GetTotalBudget() <= 0 {
+		return status.Error(codes.InvalidArgument, "total budget must be greater than 0")
+	}
+	return nil
+}
+
+// scanAutoBidRule reads a row of autoBidRuleColumns
+func scanAutoBidRule(row interface{ Scan(...any) error }) (*pb.AutoBidRule, error) {
+	rule := &pb.AutoBidRule{}
+	err := row.Scan(&rule.Id, &rule.InvestorId, pq.Array(&rule.IssuerGrades), &rule.MaxTenorDays, &rule.MinYield,
+		&rule.MaxAmountPerInvoice, &rule.TotalBudget, &rule.Status, &rule.Committed, &rule.CreatedAt)
+	return rule, err
+}
+
+// InsertAutoBidRule stores a new active rule and sets its id, status and creation time
+func InsertAutoBidRule(ctx context.Context, db dbtx, in *pb.AutoBidRule) (err error) {
+	ctx, span := startSpan(ctx, "InsertAutoBidRule", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `INSERT INTO auto_bid_rule (investor_id, issuer_grades, max_tenor_days, min_yield, max_amount_per_invoice, total_budget)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, status, to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`,
+		in.GetInvestorId(), pq.Array(in.GetIssuerGrades()), in.GetMaxTenorDays(), in.GetMinYield(), in.GetMaxAmountPerInvoice(), in.GetTotalBudget()).
+		Scan(&in.Id, &in.Status, &in.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert auto-bid rule: %w", err)
+	}
+	return nil
+}
+
+// UpdateAutoBidRule replaces the criteria of a rule of the investor and returns the stored rule
+func UpdateAutoBidRule(ctx context.Context, db dbtx, in *pb.AutoBidRule) (rule *pb.AutoBidRule, err error) {
+	ctx, span := startSpan(ctx, "UpdateAutoBidRule", nil)
+	defer func() { endSpan(span, err) }()
+	rule, err = scanAutoBidRule(db.QueryRowContext(ctx, `UPDATE auto_bid_rule SET issuer_grades = $3, max_tenor_days = $4, min_yield = $5,
+		max_amount_per_invoice = $6, total_budget = $7
+		WHERE id = $1 AND investor_id = $2 RETURNING `+autoBidRuleColumns,
+		in.GetId(), in.GetInvestorId(), pq.Array(in.GetIssuerGrades()), in.GetMaxTenorDays(), in.GetMinYield(), in.GetMaxAmountPerInvoice(),
+		in.GetTotalBudget()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "auto-bid rule %s of investor %s not found", in.GetId(), in.GetInvestorId())
+		}
+		return nil, fmt.Errorf("failed to update auto-bid rule: %w", err)
+	}
+	return rule, nil
+}
+
+// SetAutoBidRuleStatus pauses or resumes a rule of the investor and returns the stored rule
+func SetAutoBidRuleStatus(ctx context.Context, db dbtx, in *pb.AutoBidRuleStatus) (rule *pb.AutoBidRule, err error) {
+	ctx, span := startSpan(ctx, "SetAutoBidRuleStatus", nil)
+	defer func() { endSpan(span, err) }()
+	rule, err = scanAutoBidRule(db.QueryRowContext(ctx, `UPDATE auto_bid_rule SET status = $3 WHERE id = $1 AND investor_id = $2 RETURNING `+autoBidRuleColumns,
+		in.GetId(), in.GetInvestorId(), in.GetStatus()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "auto-bid rule %s of investor %s not found", in.GetId(), in.GetInvestorId())
+		}
+		return nil, fmt.Errorf("failed to set auto-bid rule status: %w", err)
+	}
+	return rule, nil
+}
+
+// GetActiveAutoBidRules returns the active rules, oldest first. With onlyInvestors set only the rules of
+// investorIDs are returned.
+func GetActiveAutoBidRules(ctx context.Context, db dbtx, investorIDs []string, onlyInvestors bool) (rules []*pb.AutoBidRule, err error) {
+	ctx, span := startSpan(ctx, "GetActiveAutoBidRules", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT `+autoBidRuleColumns+` FROM auto_bid_rule
+		WHERE status = 'active' AND (NOT $1 OR investor_id::text = ANY($2))
+		ORDER BY created_at, id`, onlyInvestors, pq.Array(investorIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query auto-bid rules: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rule, err := scanAutoBidRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auto-bid rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read auto-bid rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetAutoBidInvoice returns the invoice with the investor of its best pending bid
+func GetAutoBidInvoice(ctx context.Context, db dbtx, invoiceID string) (invoice autoBidInvoice, err error) {
+	ctx, span := startSpan(ctx, "GetAutoBidInvoice", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `SELECT status, issuer_id, price, max_bid, face_value, COALESCE(to_char(due_date, 'YYYY-MM-DD'), ''), auction_ends_at,
+		COALESCE((SELECT investor_id::text FROM bid WHERE bid.invoice_id = invoice.id AND bid.status = 'pending' `+bestBidOrder+` LIMIT 1), '')
+		FROM invoice WHERE id = $1`, invoiceID).
+		Scan(&invoice.status, &invoice.issuerID, &invoice.price, &invoice.maxBid, &invoice.faceValue, &invoice.dueDate, &invoice.auctionEndsAt,
+			&invoice.leadingInvestorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return invoice, fmt.Errorf("invoice not found: %w", err)
+		}
+		return invoice, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// InsertAutoBidExecution adds an execution to the log of its rule
+func InsertAutoBidExecution(ctx context.Context, db dbtx, in *pb.AutoBidExecution) (err error) {
+	ctx, span := startSpan(ctx, "InsertAutoBidExecution", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `INSERT INTO auto_bid_execution (rule_id, invoice_id, trigger, outcome, bid_id, amount, message)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6, $7) RETURNING id, to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`,
+		in.GetRuleId(), in.GetInvoiceId(), in.GetTrigger(), in.GetOutcome(), in.GetBidId(), in.GetAmount(), in.GetMessage()).
+		Scan(&in.Id, &in.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record auto-bid execution: %w", err)
+	}
+	return nil
+}
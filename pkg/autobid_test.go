@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var autoBidRuleRow = []string{"id", "investor_id", "issuer_grades", "max_tenor_days", "min_yield", "max_amount_per_invoice", "total_budget",
+	"status", "committed", "created_at"}
+
+func TestPlanAutoBid(t *testing.T) {
+	today := date("2024-01-01")
+	invoice := autoBidInvoice{status: "open", price: 1000, faceValue: 1000, dueDate: "2024-03-31"}
+	tests := []struct {
+		name    string
+		rule    *pb.AutoBidRule
+		invoice autoBidInvoice
+		grade   string
+		amount  float64
+		reason  string
+	}{
+		{"asking price", &pb.AutoBidRule{MaxAmountPerInvoice: 5000, TotalBudget: 10000}, invoice, "B", 1000, ""},
+		// 1000 / (1 + 0.08 * 90 / 360) = 980.39
+		{"min yield", &pb.AutoBidRule{MinYield: 8, MaxAmountPerInvoice: 5000, TotalBudget: 10000}, invoice, "B", 980.39, ""},
+		{"cap per invoice", &pb.AutoBidRule{MaxAmountPerInvoice: 500, TotalBudget: 10000}, invoice, "B", 500, ""},
+		{"maximum bid", &pb.AutoBidRule{MaxAmountPerInvoice: 5000, TotalBudget: 10000},
+			autoBidInvoice{status: "open", price: 1000, maxBid: 950, faceValue: 1000, dueDate: "2024-03-31"}, "B", 950, ""},
+		{"rest of the budget", &pb.AutoBidRule{MaxAmountPerInvoice: 5000, TotalBudget: 10000, Committed: 9700.5}, invoice, "B", 299.5, ""},
+		{"budget used", &pb.AutoBidRule{MaxAmountPerInvoice: 5000, TotalBudget: 10000, Committed: 10000}, invoice, "B", 0, "budget of 10000.00 is used up"},
+		{"accepted grade", &pb.AutoBidRule{IssuerGrades: []string{"A", "B"}, MaxAmountPerInvoice: 5000, TotalBudget: 10000}, invoice, "B", 1000, ""},
+		{"other grade", &pb.AutoBidRule{IssuerGrades: []string{"A"}, MaxAmountPerInvoice: 5000, TotalBudget: 10000}, invoice, "B", 0, "issuer grade B isn't accepted"},
+		{"tenor", &pb.AutoBidRule{MaxTenorDays: 60, MaxAmountPerInvoice: 5000, TotalBudget: 10000}, invoice, "B", 0, "tenor of 90 days is over 60"},
+		{"no due date", &pb.AutoBidRule{MinYield: 8, MaxAmountPerInvoice: 5000, TotalBudget: 10000},
+			autoBidInvoice{status: "open", price: 1000, faceValue: 1000}, "B", 0, "invoice has no due date"},
+		{"already due", &pb.AutoBidRule{MaxAmountPerInvoice: 5000, TotalBudget: 10000},
+			autoBidInvoice{status: "open", price: 1000, faceValue: 1000, dueDate: "2024-01-01"}, "B", 0, "invoice is already due"},
+		{"already leading", &pb.AutoBidRule{InvestorId: "investor-id", MaxAmountPerInvoice: 5000, TotalBudget: 10000},
+			autoBidInvoice{status: "open", price: 1000, faceValue: 1000, leadingInvestorID: "investor-id"}, "B", 0, "investor already holds the best bid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, reason := planAutoBid(tt.rule, tt.invoice, tt.grade, today, DayCountACT360)
+			assert.Equal(t, tt.reason, reason)
+			assert.InDelta(t, tt.amount, amount, 0.001)
+		})
+	}
+}
+
+func TestCreateAutoBidRule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	mock.ExpectQuery("INSERT INTO auto_bid_rule").
+		WithArgs("investor-id", sqlmock.AnyArg(), int32(90), float32(8), float32(5000), float32(20000)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow("rule-id", AutoBidRuleActive, "2024-01-01T00:00:00Z"))
+
+	// The rule spends the balance of the investor of the token, not the one in the request
+	rule, err := s.CreateAutoBidRule(asParty(PartyInvestor, "investor-id"), &pb.AutoBidRule{InvestorId: "other-id", IssuerGrades: []string{"A", "B"},
+		MaxTenorDays: 90, MinYield: 8, MaxAmountPerInvoice: 5000, TotalBudget: 20000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "rule-id", rule.Id)
+	assert.Equal(t, "investor-id", rule.InvestorId)
+	assert.Equal(t, AutoBidRuleActive, rule.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Grades have to exist in the risk model
+	_, err = s.CreateAutoBidRule(asParty(PartyInvestor, "investor-id"), &pb.AutoBidRule{IssuerGrades: []string{"Z"},
+		MaxAmountPerInvoice: 5000, TotalBudget: 20000})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.CreateAutoBidRule(asParty(PartyInvestor, "investor-id"), &pb.AutoBidRule{MaxAmountPerInvoice: 5000})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Only investors have rules
+	_, err = s.CreateAutoBidRule(context.Background(), &pb.AutoBidRule{InvestorId: "investor-id", MaxAmountPerInvoice: 5000, TotalBudget: 20000})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.CreateAutoBidRule(asParty(PartyIssuer, "issuer-id"), &pb.AutoBidRule{InvestorId: "investor-id", MaxAmountPerInvoice: 5000, TotalBudget: 20000})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestSetAutoBidRuleStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectQuery("UPDATE auto_bid_rule SET status = \\$3 WHERE id = \\$1 AND investor_id = \\$2").
+		WithArgs("rule-id", "investor-id", AutoBidRulePaused).
+		WillReturnRows(sqlmock.NewRows(autoBidRuleRow).
+			AddRow("rule-id", "investor-id", "{A}", 0, 0.0, 5000.0, 20000.0, AutoBidRulePaused, 4000.0, "2024-01-01T00:00:00Z"))
+	mock.ExpectQuery("UPDATE auto_bid_rule SET status").WithArgs("rule-id", "other-id", AutoBidRulePaused).WillReturnError(sql.ErrNoRows)
+
+	rule, err := s.SetAutoBidRuleStatus(asParty(PartyInvestor, "investor-id"), &pb.AutoBidRuleStatus{Id: "rule-id", Status: AutoBidRulePaused})
+	assert.NoError(t, err)
+	assert.Equal(t, AutoBidRulePaused, rule.Status)
+	assert.Equal(t, []string{"A"}, rule.IssuerGrades)
+	assert.Equal(t, float32(4000), rule.Committed)
+
+	// Only the owner of the rule can change it, naming the owner in the request doesn't help
+	_, err = s.SetAutoBidRuleStatus(asParty(PartyInvestor, "other-id"), &pb.AutoBidRuleStatus{Id: "rule-id", InvestorId: "investor-id", Status: AutoBidRulePaused})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	_, err = s.SetAutoBidRuleStatus(context.Background(), &pb.AutoBidRuleStatus{Id: "rule-id", InvestorId: "investor-id", Status: AutoBidRulePaused})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = s.SetAutoBidRuleStatus(asParty(PartyInvestor, "investor-id"), &pb.AutoBidRuleStatus{Id: "rule-id", Status: "deleted"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAutoBidderExecute(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel(), dayCount: DayCountACT360}
+	s.autoBids = newAutoBidder(s)
+
+	due := time.Now().UTC().AddDate(0, 3, 0).Format(dueDateLayout)
+	mock.ExpectQuery("SELECT status, issuer_id, (.+) FROM invoice WHERE id = \\$1").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "issuer_id", "price", "max_bid", "face_value", "due_date", "auction_ends_at", "leading"}).
+			AddRow("open", "issuer-id", 1000.0, 0.0, 1000.0, due, nil, "other-id"))
+	// The outbid trigger only runs the rules of the outbid investors
+	mock.ExpectQuery("FROM auto_bid_rule WHERE status = 'active'").WithArgs(true, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(autoBidRuleRow).
+			AddRow("grade-rule", "investor-id", "{A}", 0, 0.0, 5000.0, 20000.0, AutoBidRuleActive, 0.0, "2024-01-01T00:00:00Z").
+			AddRow("yield-rule", "investor-id", "{}", 0, 8.0, 5000.0, 20000.0, AutoBidRuleActive, 0.0, "2024-01-02T00:00:00Z"))
+	expectIssuerRisk(mock, "issuer-id", RiskInputs{})
+	mock.ExpectQuery("INSERT INTO auto_bid_execution").
+		WithArgs("grade-rule", "invoice-id", AutoBidTriggerOutbid, AutoBidSkipped, "", float32(0), "issuer grade B isn't accepted").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("execution-1", "2024-01-03T00:00:00Z"))
+	// The yield rule bids through PlaceBid's checks, the best bid already asks for less
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{faceValue: 1000, dueDate: due, price: 1000})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycVerified)
+	mock.ExpectQuery("SELECT amount, effective_yield FROM bid WHERE invoice_id = \\$1 AND status = 'pending'").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"amount", "effective_yield"}).AddRow(995.0, 2.0))
+	mock.ExpectRollback()
+	mock.ExpectQuery("INSERT INTO auto_bid_execution").
+		WithArgs("yield-rule", "invoice-id", AutoBidTriggerOutbid, AutoBidSkipped, "", sqlmock.AnyArg(), ErrBidNotBetter.Error()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("execution-2", "2024-01-03T00:00:00Z"))
+
+	err = s.autoBids.execute(context.Background(), AutoBidTrigger{InvoiceID: "invoice-id", Trigger: AutoBidTriggerOutbid, InvestorIDs: []string{"investor-id"}})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAutoBidderExecuteClosedInvoice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+	s.autoBids = newAutoBidder(s)
+
+	mock.ExpectQuery("SELECT status, issuer_id, (.+) FROM invoice WHERE id = \\$1").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "issuer_id", "price", "max_bid", "face_value", "due_date", "auction_ends_at", "leading"}).
+			AddRow("closed", "issuer-id", 1000.0, 0.0, 1000.0, "", nil, ""))
+
+	err = s.autoBids.execute(context.Background(), AutoBidTrigger{InvoiceID: "invoice-id", Trigger: AutoBidTriggerListing})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAutoBidderEnqueue(t *testing.T) {
+	// Servers without an auto-bidder ignore triggers
+	var none *AutoBidder
+	none.Enqueue(AutoBidTrigger{InvoiceID: "invoice-id", Trigger: AutoBidTriggerListing})
+
+	a := &AutoBidder{queue: make(chan AutoBidTrigger, 1)}
+	// Nobody was outbid
+	a.Enqueue(AutoBidTrigger{InvoiceID: "invoice-id", Trigger: AutoBidTriggerOutbid})
+	assert.Len(t, a.queue, 0)
+	a.Enqueue(AutoBidTrigger{InvoiceID: "invoice-id", Trigger: AutoBidTriggerListing})
+	// A full queue drops the trigger instead of blocking, and counts it
+	dropped := testutil.ToFloat64(autoBidTriggersDropped.WithLabelValues(AutoBidTriggerListing))
+	a.Enqueue(AutoBidTrigger{InvoiceID: "other-id", Trigger: AutoBidTriggerListing})
+	assert.Len(t, a.queue, 1)
+	assert.Equal(t, dropped+1, testutil.ToFloat64(autoBidTriggersDropped.WithLabelValues(AutoBidTriggerListing)))
+	assert.Equal(t, "invoice-id", (<-a.queue).InvoiceID)
+}
@@ -0,0 +1,219 @@
+package pkg
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/lib/pq"
+)
+
+// csvImportFields are the invoice fields a CSV import reads, only the price is required
+var csvImportFields = []string{"invoice_number", "price", "face_value", "due_date", "issue_date", "currency", "debtor_name", "debtor_reference"}
+
+// maxImportRows bounds a CSV import, its rows are kept in memory and inserted in one transaction
+const maxImportRows = 10000
+
+// ValidateCsvImport checks the metadata of the first chunk of a CSV import
+func ValidateCsvImport(in *pb.InvoiceCsvChunk) error {
+	if in.GetIssuerId() == "" {
+		return errors.New("issuer id is required")
+	}
+	for field := range in.GetColumns() {
+		if !slices.Contains(csvImportFields, field) {
+			return fmt.Errorf("unknown invoice field %q in the column mapping, one of %s", field, strings.Join(csvImportFields, ", "))
+		}
+	}
+	return nil
+}
+
+// csvRow is a valid invoice of a CSV import and the row it was read from
+type csvRow struct {
+	row     int32
+	invoice *pb.Invoice
+}
+
+// csvColumns finds the column of each mapped field in the header. Fields mapped explicitly have to be there,
+// the price too.
+func csvColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	columns := map[string]int{}
+	for _, field := range csvImportFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+		switch {
+		case ok:
+			columns[field] = i
+		case mapped || field == "price":
+			return nil, fmt.Errorf("no column %q for %s in the header", name, field)
+		}
+	}
+	return columns, nil
+}
+
+// ReadInvoiceCsv reads and prepares the invoices of the issuer, one per row. Rows are numbered like in a spreadsheet,
+// the header being row 1. Invalid rows, including numbers repeated in the file, are reported in the summary and left
+// out of the returned rows. Only an unreadable file or header is an error.
+func ReadInvoiceCsv(r io.Reader, in *pb.InvoiceCsvChunk, today time.Time) (rows []csvRow, summary *pb.InvoiceImportSummary, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	header = slices.Clone(header)
+	columns, err := csvColumns(header, in.GetColumns())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary = &pb.InvoiceImportSummary{DryRun: in.GetDryRun()}
+	numbers := map[string]int32{}
+	for row := int32(2); ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		summary.Rows++
+		if summary.Rows > maxImportRows {
+			return nil, nil, fmt.Errorf("CSV file has more than %d rows", maxImportRows)
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+		if err != nil {
+			summary.Errors = append(summary.Errors, &pb.InvoiceImportError{Row: row, Message: fmt.Sprintf("has %d columns, the header %d", len(record), len(header))})
+			continue
+		}
+
+		invoice, rowErr := csvInvoice(record, header, columns)
+		if rowErr == nil {
+			invoice.IssuerId = in.GetIssuerId()
+			if err := PrepareInvoice(invoice, today); err != nil {
+				rowErr = &pb.InvoiceImportError{Message: err.Error()}
+			}
+		}
+		if rowErr == nil && invoice.GetInvoiceNumber() != "" {
+			if first, ok := numbers[invoice.GetInvoiceNumber()]; ok {
+				rowErr = &pb.InvoiceImportError{Column: header[columns["invoice_number"]],
+					Message: fmt.Sprintf("invoice number %q is also on row %d", invoice.GetInvoiceNumber(), first)}
+			} else {
+				numbers[invoice.GetInvoiceNumber()] = row
+			}
+		}
+		if rowErr != nil {
+			rowErr.Row = row
+			summary.Errors = append(summary.Errors, rowErr)
+			continue
+		}
+		rows = append(rows, csvRow{row: row, invoice: invoice})
+	}
+	return rows, summary, nil
+}
+
+// csvInvoice maps a record to an invoice, or returns the problem with its first invalid column
+func csvInvoice(record, header []string, columns map[string]int) (*pb.Invoice, *pb.InvoiceImportError) {
+	value := func(field string) string {
+		if i, ok := columns[field]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	invoice := &pb.Invoice{
+		InvoiceNumber:   value("invoice_number"),
+		DueDate:         value("due_date"),
+		IssueDate:       value("issue_date"),
+		Currency:        value("currency"),
+		DebtorName:      value("debtor_name"),
+		DebtorReference: value("debtor_reference"),
+	}
+	for _, amount := range []struct {
+		field string
+		to    *float32
+	}{{"price", &invoice.Price}, {"face_value", &invoice.FaceValue}} {
+		if value(amount.field) == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(value(amount.field), 32)
+		if err != nil {
+			return nil, &pb.InvoiceImportError{Column: header[columns[amount.field]], Message: fmt.Sprintf("%q is not a number", value(amount.field))}
+		}
+		*amount.to = float32(n)
+	}
+	return invoice, nil
+}
+
+// CheckImportRows drops the rows whose invoice number the issuer already used, and the ones that would take the
+// issuer over its funding limit, reporting them in the summary. The remaining rows make up the valid count and
+// face value of the summary.
+func (m *RiskModel) CheckImportRows(ctx context.Context, db dbtx, issuerID string, rows []csvRow, summary *pb.InvoiceImportSummary) (valid []csvRow, err error) {
+	ctx, span := startSpan(ctx, "CheckImportRows", nil)
+	defer func() { endSpan(span, err) }()
+
+	var numbers []string
+	for _, row := range rows {
+		if row.invoice.GetInvoiceNumber() != "" {
+			numbers = append(numbers, row.invoice.GetInvoiceNumber())
+		}
+	}
+	used := map[string]bool{}
+	if len(numbers) > 0 {
+		result, err := db.QueryContext(ctx, "SELECT invoice_number FROM invoice WHERE issuer_id = $1 AND invoice_number = ANY($2)",
+			issuerID, pq.Array(numbers))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query invoice numbers: %w", err)
+		}
+		defer result.Close()
+		for result.Next() {
+			var number string
+			if err := result.Scan(&number); err != nil {
+				return nil, fmt.Errorf("failed to scan invoice number: %w", err)
+			}
+			used[number] = true
+		}
+		if err := result.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read invoice numbers: %w", err)
+		}
+	}
+
+	headroom, err := m.FundingHeadroom(ctx, db, issuerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		faceValue := float64(row.invoice.GetFaceValue())
+		switch {
+		case used[row.invoice.GetInvoiceNumber()]:
+			summary.Errors = append(summary.Errors, &pb.InvoiceImportError{Row: row.row,
+				Message: fmt.Sprintf("issuer %s already has an invoice numbered %q", issuerID, row.invoice.GetInvoiceNumber())})
+		case faceValue > headroom+balanceTolerance:
+			summary.Errors = append(summary.Errors, &pb.InvoiceImportError{Row: row.row,
+				Message: fmt.Sprintf("%v: %.2f left for this import", ErrFundingLimitExceeded, max(headroom, 0))})
+		default:
+			headroom -= faceValue
+			valid = append(valid, row)
+			summary.FaceValue += row.invoice.GetFaceValue()
+		}
+	}
+	summary.Valid = int32(len(valid))
+	// The numbers and limit are checked after the rows were read, keep the report in file order
+	slices.SortStableFunc(summary.Errors, func(a, b *pb.InvoiceImportError) int { return int(a.GetRow() - b.GetRow()) })
+	return valid, nil
+}
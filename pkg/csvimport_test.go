@@ -0,0 +1,188 @@
+package pkg
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// csvStream replays chunks to ImportInvoicesCsv and keeps its response
+type csvStream struct {
+	grpc.ServerStream
+	chunks  []*pb.InvoiceCsvChunk
+	summary *pb.InvoiceImportSummary
+}
+
+func (s *csvStream) Context() context.Context { return context.Background() }
+
+func (s *csvStream) Recv() (*pb.InvoiceCsvChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *csvStream) SendAndClose(summary *pb.InvoiceImportSummary) error {
+	s.summary = summary
+	return nil
+}
+
+const testCsv = `Number,Amount,face_value,due_date,Debtor
+INV-1,900,1000,2099-01-31,Buyer Ltd
+INV-2,abc,1000,2099-01-31,Buyer Ltd
+INV-3,900,,2099-13-01,Buyer Ltd
+INV-1,500,600,2099-01-31,Buyer Ltd
+INV-4,900,1000
+INV-5,900,1000,2099-01-31,Buyer Ltd
+INV-6,250000,300000,2099-01-31,Buyer Ltd
+INV-7,100,,,
+`
+
+// csvChunks splits the CSV in chunks of size bytes, the first one carrying the import metadata
+func csvChunks(csv string, size int, dryRun bool) []*pb.InvoiceCsvChunk {
+	chunks := []*pb.InvoiceCsvChunk{{IssuerId: "issuer-id", DryRun: dryRun,
+		Columns: map[string]string{"invoice_number": "Number", "price": "Amount", "debtor_name": "Debtor"}}}
+	for len(csv) > 0 {
+		n := min(size, len(csv))
+		chunks = append(chunks, &pb.InvoiceCsvChunk{Data: []byte(csv[:n])})
+		csv = csv[n:]
+	}
+	return chunks
+}
+
+func expectImportRowChecks(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	expectLockIssuer(mock, "issuer-id")
+	mock.ExpectQuery("SELECT invoice_number FROM invoice WHERE issuer_id = \\$1 AND invoice_number = ANY\\(\\$2\\)").
+		WithArgs("issuer-id", pq.Array([]string{"INV-1", "INV-5", "INV-6", "INV-7"})).
+		WillReturnRows(sqlmock.NewRows([]string{"invoice_number"}).AddRow("INV-5"))
+	expectIssuerRisk(mock, "issuer-id", RiskInputs{})
+}
+
+func TestReadInvoiceCsv(t *testing.T) {
+	today := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	first := csvChunks("", 0, false)[0]
+
+	rows, summary, err := ReadInvoiceCsv(strings.NewReader(testCsv), first, today)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(8), summary.Rows)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, int32(2), rows[0].row)
+	expected := &pb.Invoice{IssuerId: "issuer-id", Status: "open", InvoiceNumber: "INV-1", Price: 900, FaceValue: 1000,
+		DueDate: "2099-01-31", IssueDate: "2024-03-01", Currency: DefaultCurrency, DebtorName: "Buyer Ltd"}
+	assert.True(t, proto.Equal(expected, rows[0].invoice), "got %v", rows[0].invoice)
+	assert.Equal(t, []*pb.InvoiceImportError{
+		{Row: 3, Column: "Amount", Message: `"abc" is not a number`},
+		{Row: 4, Message: `due date "2099-13-01" must be formatted as YYYY-MM-DD`},
+		{Row: 5, Column: "Number", Message: `invoice number "INV-1" is also on row 2`},
+		{Row: 6, Message: "has 3 columns, the header 5"},
+	}, summary.Errors)
+}
+
+func TestReadInvoiceCsvRepeatedNumber(t *testing.T) {
+	csv := "Number,Amount,Debtor\nINV-1,900,Buyer Ltd\nINV-1,500,Buyer Ltd\nINV-1,600,Buyer Ltd\n"
+	first := csvChunks("", 0, false)[0]
+
+	rows, summary, err := ReadInvoiceCsv(strings.NewReader(csv), first, time.Now())
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	// Every repeat points at the row that is imported
+	assert.Equal(t, []*pb.InvoiceImportError{
+		{Row: 3, Column: "Number", Message: `invoice number "INV-1" is also on row 2`},
+		{Row: 4, Column: "Number", Message: `invoice number "INV-1" is also on row 2`},
+	}, summary.Errors)
+}
+
+func TestReadInvoiceCsvHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		columns map[string]string
+		err     string
+	}{
+		{"empty", "", nil, "CSV file is empty"},
+		{"no price", "number,amount\n", nil, `no column "price" for price in the header`},
+		{"mapped column missing", "price,due\n", map[string]string{"due_date": "Due Date"}, `no column "Due Date" for due_date in the header`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ReadInvoiceCsv(strings.NewReader(tt.csv), &pb.InvoiceCsvChunk{IssuerId: "issuer-id", Columns: tt.columns}, time.Now())
+
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+	assert.ErrorContains(t, ValidateCsvImport(&pb.InvoiceCsvChunk{IssuerId: "issuer-id", Columns: map[string]string{"status": "Status"}}),
+		`unknown invoice field "status"`)
+}
+
+func TestImportInvoicesCsv(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	expectImportRowChecks(mock)
+	mock.ExpectQuery("INSERT INTO invoice").
+		WithArgs("issuer-id", "open", "", float32(900), float32(1000), "2099-01-31", "INV-1", "Buyer Ltd", "", sqlmock.AnyArg(), "EUR", float32(0), float32(0), "", float32(0), float32(0), float32(0), int32(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invoice-1"))
+	mock.ExpectQuery("INSERT INTO invoice").
+		WithArgs("issuer-id", "open", "", float32(100), float32(100), "", "INV-7", "", "", sqlmock.AnyArg(), "EUR", float32(0), float32(0), "", float32(0), float32(0), float32(0), int32(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invoice-7"))
+	mock.ExpectCommit()
+
+	stream := &csvStream{chunks: csvChunks(testCsv, 64, false)}
+	err = s.ImportInvoicesCsv(stream)
+
+	assert.NoError(t, err)
+	summary := stream.summary
+	assert.Equal(t, int32(8), summary.Rows)
+	assert.Equal(t, int32(2), summary.Valid)
+	assert.Equal(t, int32(2), summary.Imported)
+	assert.Equal(t, float32(1100), summary.FaceValue)
+	assert.Equal(t, []string{"invoice-1", "invoice-7"}, summary.InvoiceIds)
+	var rows []int32
+	for _, rowErr := range summary.Errors {
+		rows = append(rows, rowErr.Row)
+	}
+	assert.Equal(t, []int32{3, 4, 5, 6, 7, 8}, rows)
+	assert.Equal(t, `issuer issuer-id already has an invoice numbered "INV-5"`, summary.Errors[4].Message)
+	assert.Equal(t, "issuer funding limit exceeded: 249000.00 left for this import", summary.Errors[5].Message)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportInvoicesCsvDryRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	expectImportRowChecks(mock)
+	// Nothing is written
+	mock.ExpectRollback()
+
+	stream := &csvStream{chunks: csvChunks(testCsv, 1000, true)}
+	err = s.ImportInvoicesCsv(stream)
+
+	assert.NoError(t, err)
+	assert.True(t, stream.summary.DryRun)
+	assert.Equal(t, int32(2), stream.summary.Valid)
+	assert.Equal(t, int32(0), stream.summary.Imported)
+	assert.Empty(t, stream.summary.InvoiceIds)
+	assert.Len(t, stream.summary.Errors, 6)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
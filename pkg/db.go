@@ -6,100 +6,89 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"time"
 
+	cfg "github.com/berdebotond/bankable_technical_test/config"
 	pb "github.com/berdebotond/bankable_technical_test/protos"
 	"github.com/brianvoe/gofakeit"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// SetupDatabase sets up the database connection and returns the db object
-func SetupDatabase(host string, port string, user string, password string, dbname string) *sql.DB {
-	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s "+
-		"password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+// dbtx is implemented by both *sql.DB and *sql.Tx, so the helpers below can be run inside a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
 
-	db, err := sql.Open("postgres", psqlInfo)
+// ErrNoPendingBid is returned when a trade is approved for a bid that isn't pending
+var ErrNoPendingBid = errors.New("no pending bid matches the trade")
+
+// ErrInvoiceNotOpen is returned for bids and trades on an invoice that no longer takes bids
+var ErrInvoiceNotOpen = status.Error(codes.FailedPrecondition, "invoice is not open for bids")
+
+// SetupDatabase sets up the database connection pool, migrates the schema and returns the db object
+func SetupDatabase(config *cfg.Config) (*sql.DB, error) {
+	db, err := OpenDatabase(config)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	log.Println("Connected to database")
-	err = db.Ping()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Create the messages table if it doesn't already exist
-	_, err = db.Exec(`
-	CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
-	CREATE TABLE IF NOT EXISTS invoice (
-		id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
-		issuer_id UUID,
-		status VARCHAR(255),
-		investor_id UUID,
-		price FLOAT
-	);
-	
-	CREATE TABLE IF NOT EXISTS issuer (
-		id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
-		balance FLOAT NOT NULL,
-		name VARCHAR(255)
-	);
-	
-	CREATE TABLE IF NOT EXISTS investor (
-		id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
-		balance FLOAT NOT NULL,
-		name VARCHAR(255)
-	);
-	
-	CREATE TABLE IF NOT EXISTS bid (
-		id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
-		investor_id UUID,
-		invoice_id UUID,
-		amount FLOAT NOT NULL,
-		status VARCHAR(255)
-		);
-
-	DO $$
-	BEGIN
-		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_invoice_issuer') THEN
-			ALTER TABLE invoice ADD CONSTRAINT fk_invoice_issuer FOREIGN KEY (issuer_id) REFERENCES issuer(id);
-		END IF;
-	
-		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_invoice_investor') THEN
-			ALTER TABLE invoice ADD CONSTRAINT fk_invoice_investor FOREIGN KEY (investor_id) REFERENCES investor(id);
-		END IF;
-	
-		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_bid_investor') THEN
-			ALTER TABLE bid ADD CONSTRAINT fk_bid_investor FOREIGN KEY (investor_id) REFERENCES investor(id);
-		END IF;
-	
-		IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_bid_invoice') THEN
-			ALTER TABLE bid ADD CONSTRAINT fk_bid_invoice FOREIGN KEY (invoice_id) REFERENCES invoice(id);
-		END IF;
-	END $$;
-	`)
-
-	log.Println("Created messages table")
+
+	// Bring the schema up to date
+	err = Migrate(db)
 	if err != nil {
-		log.Fatal(err)
+		db.Close()
+		return nil, err
+	}
+
+	if !config.SeedMockData {
+		return db, nil
 	}
 	err = InitializeMockData(db)
 	if err != nil {
-		log.Fatal(err)
+		db.Close()
+		return nil, fmt.Errorf("failed to insert mock data: %w", err)
 	}
 	// log all inestor and invocer
 	investors, err := GetAllInvestors(db)
 	if err != nil {
-		log.Fatal(err)
+		db.Close()
+		return nil, err
 	}
-	log.Printf("All investors: %v", investors)
+	slog.Debug("All investors", "investors", investors)
 	issuers, err := GetAllIssuers(db)
 	if err != nil {
-		log.Fatal(err)
+		db.Close()
+		return nil, err
 	}
-	log.Printf("All issuers: %v", issuers)
-	return db
+	slog.Debug("All issuers", "issuers", issuers)
+	return db, nil
+}
+
+// OpenDatabase sets up the database connection pool without touching the schema
+func OpenDatabase(config *cfg.Config) (*sql.DB, error) {
+	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s "+
+		"password=%s dbname=%s sslmode=%s",
+		config.DatabaseHost, config.DatabasePort, config.DatabaseUser, config.DatabasePassword, config.DatabaseName, config.DatabaseSSLMode)
+
+	db, err := sql.Open("postgres", psqlInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(config.DatabaseMaxOpenConns)
+	db.SetMaxIdleConns(config.DatabaseMaxIdleConns)
+	db.SetConnMaxLifetime(config.DatabaseConnMaxLife)
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	log.Println("Connected to database")
+	return db, nil
 }
 
 // Get all investors from the database only use locally
@@ -155,19 +144,20 @@ func GetAllIssuers(db *sql.DB) ([]*pb.Issuer, error) {
 func InitializeMockData(db *sql.DB) error {
 	// Seed the random number generator
 	gofakeit.Seed(0)
-	log.Println("Seeded random number generator")
+	slog.Debug("Seeded random number generator")
 	for i := 0; i < 15; i++ {
 		issuerBalance := gofakeit.Float64Range(1000.0, 5000.0)
 		issuerName := gofakeit.Name()
-		_, err := db.Exec("INSERT INTO issuer (balance, name) VALUES ($1, $2)", issuerBalance, issuerName)
-		//_, err := db.Exec("INSERT INTO issuer (balance, name) VALUES ($1, $2)", issuerBalance, issuerName)
+		_, err := db.Exec(`WITH account AS (INSERT INTO issuer (balance, name) VALUES ($1, $2) RETURNING id, balance)
+			INSERT INTO ledger (account_type, account_id, kind, amount) SELECT 'issuer', id, 'deposit', balance FROM account`, issuerBalance, issuerName)
 		if err != nil {
 			return err
 		}
 
 		investorBalance := gofakeit.Float64Range(5000.0, 10000.0)
 		investorName := gofakeit.Name()
-		_, err = db.Exec("INSERT INTO investor (balance, name) VALUES ($1, $2)", investorBalance, investorName)
+		_, err = db.Exec(`WITH account AS (INSERT INTO investor (balance, name) VALUES ($1, $2) RETURNING id, balance)
+			INSERT INTO ledger (account_type, account_id, kind, amount) SELECT 'investor', id, 'deposit', balance FROM account`, investorBalance, investorName)
 		if err != nil {
 			return err
 		}
@@ -176,30 +166,33 @@ func InitializeMockData(db *sql.DB) error {
 	return nil
 }
 
-func CheckInvestorBalance(ctx context.Context, db *sql.DB, in *pb.Bid) error {
-	log.Println("Checking investor's balance")
+// CheckInvestorBalance checks the investor can pay the bid amount and their share of its trade fee. The investor's
+// row stays locked until the bid's transaction ends, so their concurrent bids are checked against the balance and
+// tier limits one after the other.
+func CheckInvestorBalance(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "CheckInvestorBalance", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Checking investor's balance")
 	var balance float32
-	err := db.QueryRowContext(ctx, "SELECT balance FROM investor WHERE id = $1", in.InvestorId).Scan(&balance)
+	err = db.QueryRowContext(ctx, "SELECT balance FROM investor WHERE id = $1 FOR UPDATE", in.InvestorId).Scan(&balance)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("investor not found: %w", err)
 		}
 		return fmt.Errorf("failed to get investor's balance: %w", err)
 	}
-	if balance < in.Amount {
+	if balance < in.GetAmount()+in.GetFees().GetInvestorFee() {
 		return errors.New("investor doesn't have enough balance")
 	}
-	//commit changes
-	if err != nil {
-		return fmt.Errorf("failed to commit changes: %w", err)
-	}
 
 	return nil
 }
 
-func RededuceInvestorBalance(ctx context.Context, db *sql.DB, in *pb.Bid) error {
-	log.Println("Reducing investor's balance")
-	_, err := db.ExecContext(ctx, "UPDATE investor SET balance = balance - $1 WHERE id = $2", in.Amount, in.InvestorId)
+func RededuceInvestorBalance(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "RededuceInvestorBalance", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Reducing investor's balance")
+	_, err = db.ExecContext(ctx, "UPDATE investor SET balance = balance - $1 WHERE id = $2", in.Amount, in.InvestorId)
 	if err != nil {
 		return fmt.Errorf("failed to reduce investor's balance: %w", err)
 	}
@@ -209,40 +202,95 @@ func RededuceInvestorBalance(ctx context.Context, db *sql.DB, in *pb.Bid) error
 	return nil
 }
 
-func CloseBids(ctx context.Context, db *sql.DB, in *pb.Bid) error {
-	log.Println("Closing bid")
+// bidRefund is the amount to return to an investor for the bids CloseBids closed
+type bidRefund struct {
+	investorID string
+	amount     float64
+}
 
-	_, err := db.ExecContext(ctx, "UPDATE bid SET status = 'closed' WHERE invoice_id = $1", in.InvoiceId)
+// CloseBids closes the pending bids of the invoice and refunds the bids it closed. Only the rows the UPDATE
+// returns are refunded, so a bid already closed, cancelled or approved, by this or a concurrent transaction, never
+// has its funds returned twice.
+func CloseBids(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "CloseBids", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Closing bid")
+
+	rows, err := db.QueryContext(ctx, `UPDATE bid SET status = 'closed', closed_at = now() WHERE invoice_id = $1 AND status = 'pending'
+		RETURNING investor_id, amount`, in.InvoiceId)
 	if err != nil {
 		return fmt.Errorf("failed to close bid: %w", err)
 	}
-	if err != nil {
-		return fmt.Errorf("failed to commit changes: %w", err)
+	defer rows.Close()
+	// An investor can have several pending bids on the same invoice, refund them at once
+	var refunds []bidRefund
+	positions := map[string]int{}
+	for rows.Next() {
+		var refund bidRefund
+		if err := rows.Scan(&refund.investorID, &refund.amount); err != nil {
+			return fmt.Errorf("failed to scan closed bid: %w", err)
+		}
+		if i, ok := positions[refund.investorID]; ok {
+			refunds[i].amount += refund.amount
+			continue
+		}
+		positions[refund.investorID] = len(refunds)
+		refunds = append(refunds, refund)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read closed bids: %w", err)
 	}
+	rows.Close()
 
-	err = IncreasePreviousInvestorsBalance(ctx, db, in)
+	err = IncreasePreviousInvestorsBalance(ctx, db, refunds)
 	if err != nil {
 		return fmt.Errorf("failed to increase previous investors' balance: %w", err)
 	}
+	return nil
+}
 
+// IncreasePreviousInvestorsBalance returns the funds held by closed bids to their investors
+func IncreasePreviousInvestorsBalance(ctx context.Context, db dbtx, refunds []bidRefund) (err error) {
+	ctx, span := startSpan(ctx, "IncreasePreviousInvestorsBalance", nil)
+	defer func() { endSpan(span, err) }()
+	for _, refund := range refunds {
+		slog.Debug("Increasing previous investor's balance", "investor_id", refund.investorID, "amount", refund.amount)
+		_, err = db.ExecContext(ctx, "UPDATE investor SET balance = balance + $1 WHERE id = $2", refund.amount, refund.investorID)
+		if err != nil {
+			return fmt.Errorf("failed to increase previous investors' balance: %w", err)
+		}
+	}
 	return nil
 }
 
-func IncreasePreviousInvestorsBalance(ctx context.Context, db *sql.DB, in *pb.Bid) error {
-	log.Printf("Increasing previous investors' balance with id: %v", in.InvoiceId)
-	_, err := db.ExecContext(ctx, "UPDATE investor SET balance = balance + bid.amount FROM bid WHERE bid.invoice_id = $1 AND investor.id = bid.investor_id", in.InvoiceId)
+// GetOutbidInvestors returns the other investors holding a pending bid on the invoice, the ones the bid outbids
+func GetOutbidInvestors(ctx context.Context, db dbtx, in *pb.Bid) (investorIDs []string, err error) {
+	ctx, span := startSpan(ctx, "GetOutbidInvestors", in)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, "SELECT DISTINCT investor_id FROM bid WHERE invoice_id = $1 AND status = 'pending' AND investor_id <> $2",
+		in.GetInvoiceId(), in.GetInvestorId())
 	if err != nil {
-		return fmt.Errorf("failed to increase previous investors' balance: %w", err)
+		return nil, fmt.Errorf("failed to query outbid investors: %w", err)
 	}
-	if err != nil {
-		return fmt.Errorf("failed to commit changes: %w", err)
+	defer rows.Close()
+	for rows.Next() {
+		var investorID string
+		if err := rows.Scan(&investorID); err != nil {
+			return nil, fmt.Errorf("failed to scan outbid investor: %w", err)
+		}
+		investorIDs = append(investorIDs, investorID)
 	}
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outbid investors: %w", err)
+	}
+	return investorIDs, nil
 }
 
-func UpdateInvestorInInvoice(ctx context.Context, db *sql.DB, in *pb.Bid) error {
-	log.Println("Updating investor in invoice")
-	_, err := db.ExecContext(ctx, "UPDATE invoice SET investor_id = $1 WHERE id = $2", in.InvestorId, in.InvoiceId)
+func UpdateInvestorInInvoice(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "UpdateInvestorInInvoice", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Updating investor in invoice")
+	_, err = db.ExecContext(ctx, "UPDATE invoice SET investor_id = $1 WHERE id = $2", in.InvestorId, in.InvoiceId)
 	if err != nil {
 		return fmt.Errorf("failed to update investor in invoice: %w", err)
 	}
@@ -252,10 +300,12 @@ func UpdateInvestorInInvoice(ctx context.Context, db *sql.DB, in *pb.Bid) error
 	return nil
 }
 
-func DetermineBidStatus(ctx context.Context, db *sql.DB, in *pb.Bid) (string, error) {
-	log.Println("Determining bid status")
+func DetermineBidStatus(ctx context.Context, db dbtx, in *pb.Bid) (status string, err error) {
+	ctx, span := startSpan(ctx, "DetermineBidStatus", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Determining bid status")
 	var price float32
-	err := db.QueryRowContext(ctx, "SELECT price FROM invoice WHERE id = $1", in.InvoiceId).Scan(&price)
+	err = db.QueryRowContext(ctx, "SELECT price FROM invoice WHERE id = $1", in.InvoiceId).Scan(&price)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return "", fmt.Errorf("invoice not found: %w", err)
@@ -269,36 +319,79 @@ func DetermineBidStatus(ctx context.Context, db *sql.DB, in *pb.Bid) (string, er
 	return "pending", nil
 }
 
-func InsertBid(ctx context.Context, db *sql.DB, in *pb.Bid, status string) error {
-	log.Println("Inserting bid")
-	_, err := db.ExecContext(ctx, "INSERT INTO bid (investor_id, invoice_id, amount, status) VALUES ($1, $2, $3, 'pending')", in.InvestorId, in.InvoiceId, in.Amount)
+// InsertBid records the bid with its status and sets its id and status
+func InsertBid(ctx context.Context, db dbtx, in *pb.Bid, status string) (err error) {
+	ctx, span := startSpan(ctx, "InsertBid", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Inserting bid")
+	// Only bids on invoices with a due date are priced
+	priced := in.GetDayCount() != ""
+	err = db.QueryRowContext(ctx, `INSERT INTO bid (investor_id, invoice_id, amount, status, rate, rate_type, day_count, effective_yield)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		in.InvestorId, in.InvoiceId, in.Amount, status,
+		sql.NullFloat64{Float64: float64(in.GetRate()), Valid: in.GetRate() != 0},
+		sql.NullString{String: in.GetRateType(), Valid: in.GetRateType() != ""},
+		sql.NullString{String: in.GetDayCount(), Valid: priced},
+		sql.NullFloat64{Float64: float64(in.GetEffectiveYield()), Valid: priced}).Scan(&in.Id)
 	if err != nil {
 		return fmt.Errorf("failed to insert bid: %w", err)
 	}
+	in.Status = status
 	if err != nil {
 		return fmt.Errorf("failed to commit changes: %w", err)
 	}
 	return nil
 }
 
-func CloseInvoice(ctx context.Context, db *sql.DB, in *pb.Bid) error {
-	log.Printf("Approving invoice with invoice id %s", in.GetInvoiceId())
-	_, err := db.ExecContext(ctx, "UPDATE invoice SET status = 'closed', investor_id = $1 WHERE id = $2", in.GetInvestorId(), in.GetInvoiceId())
-
+// ApproveBid marks the pending bid matching in as the winning bid of its invoice
+func ApproveBid(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "ApproveBid", in)
+	defer func() { endSpan(span, err) }()
+	log.Printf("Approving bid of investor %s on invoice %s", in.GetInvestorId(), in.GetInvoiceId())
+	res, err := db.ExecContext(ctx, `UPDATE bid SET status = 'approved' WHERE id = (
+		SELECT id FROM bid WHERE invoice_id = $1 AND investor_id = $2 AND amount = $3 AND status = 'pending' LIMIT 1)`,
+		in.GetInvoiceId(), in.GetInvestorId(), in.GetAmount())
 	if err != nil {
-		log.Printf("Error updating invoice: %v", err)
-		return err
+		return fmt.Errorf("failed to approve bid: %w", err)
 	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to approve bid: %w", err)
+	}
+	if n == 0 {
+		return ErrNoPendingBid
+	}
+	return nil
+}
 
+// CloseInvoice marks the open invoice as funded by the investor of the bid. An invoice that was already closed or
+// withdrawn is left alone and ErrInvoiceNotOpen returned.
+func CloseInvoice(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "CloseInvoice", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Approving invoice", "invoice_id", in.GetInvoiceId())
+	res, err := db.ExecContext(ctx, "UPDATE invoice SET status = 'closed', investor_id = $1 WHERE id = $2 AND status = 'open'",
+		in.GetInvestorId(), in.GetInvoiceId())
 	if err != nil {
-		return fmt.Errorf("failed to commit changes: %w", err)
+		return fmt.Errorf("failed to close invoice: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to close invoice: %w", err)
+	}
+	if n == 0 {
+		return ErrInvoiceNotOpen
 	}
 	return nil
 }
 
-func UpadeIssuerBalanceByBid(ctx context.Context, db *sql.DB, in *pb.Bid) error {
-	log.Printf("Updating issuer's balance ")
-	_, err := db.ExecContext(ctx, "UPDATE issuer SET balance = balance + $1 WHERE id = (SELECT issuer_id FROM invoice WHERE id = $2)", in.GetAmount(), in.GetInvoiceId())
+// UpadeIssuerBalanceByBid credits the issuer of the invoice with the bid amount minus the issuer fee of the trade
+func UpadeIssuerBalanceByBid(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "UpadeIssuerBalanceByBid", in)
+	defer func() { endSpan(span, err) }()
+	slog.Debug("Updating issuer's balance")
+	proceeds := in.GetAmount() - in.GetFees().GetIssuerFee()
+	_, err = db.ExecContext(ctx, "UPDATE issuer SET balance = balance + $1 WHERE id = (SELECT issuer_id FROM invoice WHERE id = $2)", proceeds, in.GetInvoiceId())
 	if err != nil {
 		return fmt.Errorf("failed to update issuer's balance: %w", err)
 	}
@@ -308,14 +401,15 @@ func UpadeIssuerBalanceByBid(ctx context.Context, db *sql.DB, in *pb.Bid) error
 	return nil
 }
 
-func ListAllBids(ctx context.Context, db *sql.DB) ([]*pb.Bid, error) {
+func ListAllBids(ctx context.Context, db dbtx) (bids []*pb.Bid, err error) {
+	ctx, span := startSpan(ctx, "ListAllBids", nil)
+	defer func() { endSpan(span, err) }()
 	rows, err := db.QueryContext(ctx, "SELECT id, investor_id, invoice_id, amount, status FROM bid")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query bids: %w", err)
 	}
 	defer rows.Close()
 
-	var bids []*pb.Bid
 	for rows.Next() {
 		var bid pb.Bid
 		if err := rows.Scan(&bid.Id, &bid.InvestorId, &bid.InvoiceId, &bid.Amount, &bid.Status); err != nil {
@@ -328,3 +422,29 @@ func ListAllBids(ctx context.Context, db *sql.DB) ([]*pb.Bid, error) {
 	}
 	return bids, nil
 }
+
+// GetOutstandingEscrow returns the sum of investor funds held by pending bids
+func GetOutstandingEscrow(ctx context.Context, db dbtx) (balance float64, err error) {
+	ctx, span := startSpan(ctx, "GetOutstandingEscrow", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, "SELECT COALESCE(SUM(amount), 0) FROM bid WHERE status = 'pending'").Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get outstanding escrow: %w", err)
+	}
+	return balance, nil
+}
+
+// GetAuctionDuration returns how long the invoice has been listed
+func GetAuctionDuration(ctx context.Context, db dbtx, in *pb.Bid) (duration time.Duration, err error) {
+	ctx, span := startSpan(ctx, "GetAuctionDuration", in)
+	defer func() { endSpan(span, err) }()
+	var seconds float64
+	err = db.QueryRowContext(ctx, "SELECT EXTRACT(EPOCH FROM now() - created_at) FROM invoice WHERE id = $1", in.GetInvoiceId()).Scan(&seconds)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("invoice not found: %w", err)
+		}
+		return 0, fmt.Errorf("failed to get auction duration: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
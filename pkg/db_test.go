@@ -2,7 +2,7 @@ package pkg
 
 import (
 	"context"
-	"regexp"
+	"errors"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -31,7 +31,7 @@ func TestCloseInvoice(t *testing.T) {
 	}
 
 	// Update the regular expression to match the actual SQL query
-	mock.ExpectExec("UPDATE invoice SET status = 'closed', investor_id = \\$1 WHERE id = \\$2").
+	mock.ExpectExec("UPDATE invoice SET status = 'closed', investor_id = \\$1 WHERE id = \\$2 AND status = 'open'").
 		WithArgs(bid.GetInvestorId(), bid.GetInvoiceId()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -45,6 +45,26 @@ func TestCloseInvoice(t *testing.T) {
 	}
 }
 
+func TestCloseInvoiceNotOpen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open stub database connection: %v", err)
+	}
+	defer db.Close()
+
+	// The invoice was already funded, it must not change hands
+	mock.ExpectExec("UPDATE invoice SET status = 'closed'").WithArgs("investor-id", "invoice-id").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = CloseInvoice(context.Background(), db, &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 100})
+	if !errors.Is(err, ErrInvoiceNotOpen) {
+		t.Errorf("expected ErrInvoiceNotOpen, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
+
 func TestInsertBid(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -66,14 +86,17 @@ func TestInsertBid(t *testing.T) {
 		Status:     "approved",
 	}
 
-	mock.ExpectExec("INSERT INTO bid \\(investor_id, invoice_id, amount, status\\) VALUES \\(\\$1, \\$2, \\$3, 'pending'\\)").
-		WithArgs(bid.InvestorId, bid.InvoiceId, bid.Amount).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("INSERT INTO bid \\(investor_id, invoice_id, amount, status, rate, rate_type, day_count, effective_yield\\)").
+		WithArgs(bid.InvestorId, bid.InvoiceId, bid.Amount, "pending", nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("new-bid-id"))
 
 	err = InsertBid(ctx, db, bid, "pending")
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
+	if bid.Id != "new-bid-id" || bid.Status != "pending" {
+		t.Errorf("bid id and status not set: %v", bid)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %v", err)
@@ -171,12 +194,12 @@ func TestCloseBids(t *testing.T) {
 		Status:     "approved",
 	}
 
-	mock.ExpectExec("UPDATE bid SET status = 'closed' WHERE invoice_id = \\$1").
+	// Only the bids the UPDATE closed are refunded, an investor's bids together
+	mock.ExpectQuery("UPDATE bid SET status = 'closed', closed_at = now\\(\\) WHERE invoice_id = \\$1 AND status = 'pending'\\s+RETURNING investor_id, amount").
 		WithArgs(bid.InvoiceId).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE investor SET balance = balance + bid.amount FROM bid WHERE bid.invoice_id = $1 AND investor.id = bid.investor_id")).
-		WithArgs(bid.InvoiceId).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+		WillReturnRows(sqlmock.NewRows([]string{"investor_id", "amount"}).AddRow("investor-a", 100.0).AddRow("investor-b", 50.0).AddRow("investor-a", 25.0))
+	mock.ExpectExec("UPDATE investor SET balance = balance \\+ \\$1 WHERE id = \\$2").WithArgs(125.0, "investor-a").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE investor SET balance = balance \\+ \\$1 WHERE id = \\$2").WithArgs(50.0, "investor-b").WillReturnResult(sqlmock.NewResult(0, 1))
 
 	err = CloseBids(ctx, db, bid)
 	if err != nil {
@@ -208,11 +231,9 @@ func TestIncreasePreviousInvestorsBalance(t *testing.T) {
 		Amount:     100,
 		Status:     "approved",
 	}
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE investor SET balance = balance + bid.amount FROM bid WHERE bid.invoice_id = $1 AND investor.id = bid.investor_id")).
-		WithArgs(bid.InvoiceId).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE investor SET balance = balance \\+ \\$1 WHERE id = \\$2").WithArgs(100.0, bid.InvestorId).WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = IncreasePreviousInvestorsBalance(ctx, db, bid)
+	err = IncreasePreviousInvestorsBalance(ctx, db, []bidRefund{{investorID: bid.InvestorId, amount: 100}})
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -221,3 +242,29 @@ func TestIncreasePreviousInvestorsBalance(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %v", err)
 	}
 }
+
+func TestApproveBidWithoutPendingBid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open stub database connection: %v", err)
+	}
+	defer db.Close()
+
+	bid := &pb.Bid{
+		InvestorId: "investor-id",
+		InvoiceId:  "invoice-id",
+		Amount:     100,
+	}
+	mock.ExpectExec("UPDATE bid SET status = 'approved'").
+		WithArgs(bid.InvoiceId, bid.InvestorId, bid.Amount).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = ApproveBid(context.Background(), db, bid)
+	if !errors.Is(err, ErrNoPendingBid) {
+		t.Errorf("expected ErrNoPendingBid, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package pkg
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// documentChunkSize is the size of the chunks documents are downloaded in
+const documentChunkSize = 64 * 1024
+
+// documentContentTypes are the content types accepted for invoice documents
+var documentContentTypes = []string{"application/pdf", "image/png", "image/jpeg"}
+
+var (
+	// ErrDocumentTooLarge is returned when an upload goes over the configured maximum size
+	ErrDocumentTooLarge = errors.New("invoice document too large")
+	// ErrDocumentContentType is returned for uploads that aren't a PDF or an image
+	ErrDocumentContentType = errors.New("invoice document must be a PDF, PNG or JPEG")
+)
+
+// DocumentCaller returns the issuer or investor calling a document RPC, as authenticated by their bearer token.
+// The party sent in the request is ignored.
+func DocumentCaller(ctx context.Context) (*Principal, error) {
+	principal := PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, status.Error(codes.Unauthenticated, "a bearer token of an issuer or investor is required")
+	}
+	if principal.Role != PartyIssuer && principal.Role != PartyInvestor {
+		return nil, status.Errorf(codes.PermissionDenied, "invoice documents are only shared with issuers and investors, not %s", principal.Role)
+	}
+	return principal, nil
+}
+
+// ValidateDocumentUpload checks the metadata of the first chunk of an upload by caller
+func ValidateDocumentUpload(in *pb.InvoiceDocumentChunk, caller *Principal) error {
+	if in.GetInvoiceId() == "" {
+		return errors.New("invoice id is required")
+	}
+	if caller.Role != PartyIssuer {
+		return status.Errorf(codes.PermissionDenied, "only the issuer of the invoice uploads its documents, not %s %s", caller.Role, caller.PartyID)
+	}
+	if in.GetFileName() == "" {
+		return errors.New("file name is required")
+	}
+	return nil
+}
+
+// ValidateDocumentRequest checks a download request before anything is read from the database
+func ValidateDocumentRequest(in *pb.DocumentRequest) error {
+	if in.GetInvoiceId() == "" {
+		return errors.New("invoice id is required")
+	}
+	return nil
+}
+
+// chunkReader reads the data of the chunks of a client stream, starting with the first chunk's data. next
+// receives the data of the following chunk.
+type chunkReader struct {
+	next func() ([]byte, error)
+	data []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		data, err := r.next()
+		if err != nil {
+			// io.EOF when the client closed the stream
+			return 0, err
+		}
+		r.data = data
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// sizeLimitReader fails with ErrDocumentTooLarge once more than max bytes were read, 0 means no limit
+type sizeLimitReader struct {
+	r    io.Reader
+	max  int64
+	size int64
+}
+
+func (r *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.size += int64(n)
+	if r.max > 0 && r.size > r.max {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrDocumentTooLarge, r.max)
+	}
+	return n, err
+}
+
+// newBlobKey returns a key nothing else is stored under
+func newBlobKey(invoiceID string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate blob key: %w", err)
+	}
+	return fmt.Sprintf("invoice/%s/%s", filepath.Base(invoiceID), hex.EncodeToString(b)), nil
+}
+
+// StoreInvoiceDocument writes the content read from r to the blob store while hashing it. The content type is
+// detected from the first bytes, whatever the client claims. The returned key is where the blob was stored.
+func StoreInvoiceDocument(ctx context.Context, blobs BlobStore, in *pb.InvoiceDocumentChunk, r io.Reader, maxSize int64) (document *pb.InvoiceDocument, key string, err error) {
+	ctx, span := startSpan(ctx, "StoreInvoiceDocument", nil)
+	defer func() { endSpan(span, err) }()
+
+	limited := &sizeLimitReader{r: r, max: maxSize}
+	content := bufio.NewReaderSize(limited, 512)
+	head, err := content.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, "", fmt.Errorf("failed to read document: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, "", errors.New("document is empty")
+	}
+	contentType := http.DetectContentType(head)
+	if !slices.Contains(documentContentTypes, contentType) {
+		return nil, "", fmt.Errorf("%w, got %s", ErrDocumentContentType, contentType)
+	}
+
+	key, err = newBlobKey(in.GetInvoiceId())
+	if err != nil {
+		return nil, "", err
+	}
+	digest := sha256.New()
+	if err := blobs.Put(ctx, key, io.TeeReader(content, digest)); err != nil {
+		return nil, "", err
+	}
+	return &pb.InvoiceDocument{
+		InvoiceId:   in.GetInvoiceId(),
+		FileName:    filepath.Base(in.GetFileName()),
+		ContentType: contentType,
+		Size:        limited.size,
+		Sha256:      hex.EncodeToString(digest.Sum(nil)),
+	}, key, nil
+}
+
+// InsertInvoiceDocument records a stored document and sets its id
+func InsertInvoiceDocument(ctx context.Context, db dbtx, in *pb.InvoiceDocument, key, uploadedBy string) (err error) {
+	ctx, span := startSpan(ctx, "InsertInvoiceDocument", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `INSERT INTO invoice_document (invoice_id, file_name, content_type, size, sha256, blob_key, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		in.GetInvoiceId(), in.GetFileName(), in.GetContentType(), in.GetSize(), in.GetSha256(), key, uploadedBy).Scan(&in.Id)
+	if err != nil {
+		return fmt.Errorf("failed to insert invoice document: %w", err)
+	}
+	return nil
+}
+
+// GetInvoiceDocument returns a document of the invoice and its blob key, the latest one when no document id is given
+func GetInvoiceDocument(ctx context.Context, db dbtx, in *pb.DocumentRequest) (document *pb.InvoiceDocument, key string, err error) {
+	ctx, span := startSpan(ctx, "GetInvoiceDocument", nil)
+	defer func() { endSpan(span, err) }()
+	document = &pb.InvoiceDocument{}
+	err = db.QueryRowContext(ctx, `SELECT id, invoice_id, file_name, content_type, size, sha256, blob_key FROM invoice_document
+		WHERE invoice_id = $1 AND ($2 = '' OR id::text = $2) ORDER BY created_at DESC LIMIT 1`, in.GetInvoiceId(), in.GetDocumentId()).
+		Scan(&document.Id, &document.InvoiceId, &document.FileName, &document.ContentType, &document.Size, &document.Sha256, &key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", status.Errorf(codes.NotFound, "invoice %s has no document %q", in.GetInvoiceId(), in.GetDocumentId())
+		}
+		return nil, "", fmt.Errorf("failed to get invoice document: %w", err)
+	}
+	return document, key, nil
+}
+
+// CanAccessInvoiceDocuments tells whether the party may read the documents of the invoice. Its issuer and the
+// investors who funded it always can, other verified investors only while the invoice is open for bids.
+func CanAccessInvoiceDocuments(ctx context.Context, db dbtx, invoiceID, partyType, partyID string) (allowed bool, err error) {
+	ctx, span := startSpan(ctx, "CanAccessInvoiceDocuments", nil)
+	defer func() { endSpan(span, err) }()
+	query := "SELECT COALESCE(issuer_id::text = $2, false) FROM invoice WHERE id = $1"
+	if partyType == PartyInvestor {
+		query = `SELECT (invoice.status = 'open' AND investor.kyc_status = 'verified')
+			OR EXISTS (SELECT 1 FROM trade WHERE trade.invoice_id = invoice.id AND trade.investor_id = investor.id)
+			FROM invoice, investor WHERE invoice.id = $1 AND investor.id = $2`
+	}
+	err = db.QueryRowContext(ctx, query, invoiceID, partyID).Scan(&allowed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("invoice %s or %s %s not found", invoiceID, partyType, partyID)
+		}
+		return false, fmt.Errorf("failed to check document access: %w", err)
+	}
+	return allowed, nil
+}
+
+// SendInvoiceDocument streams the content in chunks, the first one carrying the metadata of the document. It fails
+// with DataLoss when the content doesn't match the digest recorded at upload, clients should discard what they got.
+func SendInvoiceDocument(stream pb.InvoiceService_DownloadInvoiceDocumentServer, document *pb.InvoiceDocument, r io.Reader) error {
+	digest := sha256.New()
+	chunk := &pb.InvoiceDocumentChunk{
+		InvoiceId:   document.GetInvoiceId(),
+		DocumentId:  document.GetId(),
+		FileName:    document.GetFileName(),
+		ContentType: document.GetContentType(),
+		Sha256:      document.GetSha256(),
+	}
+	buf := make([]byte, documentChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			digest.Write(buf[:n])
+			chunk.Data = buf[:n]
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+			chunk = &pb.InvoiceDocumentChunk{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read document: %w", err)
+		}
+	}
+	if hex.EncodeToString(digest.Sum(nil)) != document.GetSha256() {
+		return status.Errorf(codes.DataLoss, "document %s does not match its sha256 digest", document.GetId())
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var testPDF = append([]byte("%PDF-1.7\n"), bytes.Repeat([]byte("invoice content\n"), 10000)...)
+
+// uploadStream replays chunks to UploadInvoiceDocument and keeps its response
+type uploadStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	chunks   []*pb.InvoiceDocumentChunk
+	response *pb.InvoiceDocument
+}
+
+func (s *uploadStream) Context() context.Context { return s.ctx }
+
+func (s *uploadStream) Recv() (*pb.InvoiceDocumentChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *uploadStream) SendAndClose(document *pb.InvoiceDocument) error {
+	s.response = document
+	return nil
+}
+
+// downloadStream collects the chunks sent by DownloadInvoiceDocument
+type downloadStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*pb.InvoiceDocumentChunk
+}
+
+func (s *downloadStream) Context() context.Context { return s.ctx }
+
+func (s *downloadStream) Send(chunk *pb.InvoiceDocumentChunk) error {
+	// Like grpc, don't hold on to the message after Send returns
+	s.chunks = append(s.chunks, proto.Clone(chunk).(*pb.InvoiceDocumentChunk))
+	return nil
+}
+
+// chunked splits data into chunks of size bytes, the first one carrying the upload metadata
+func chunked(data []byte, size int) []*pb.InvoiceDocumentChunk {
+	chunks := []*pb.InvoiceDocumentChunk{{InvoiceId: "invoice-id", FileName: "../invoice.pdf"}}
+	for len(data) > 0 {
+		n := min(size, len(data))
+		chunks = append(chunks, &pb.InvoiceDocumentChunk{Data: data[:n]})
+		data = data[n:]
+	}
+	return chunks
+}
+
+func expectDocumentAccess(mock sqlmock.Sqlmock, partyType, partyID string, allowed bool) {
+	query := "SELECT COALESCE\\(issuer_id::text = \\$2, false\\) FROM invoice WHERE id = \\$1"
+	if partyType == PartyInvestor {
+		query = "SELECT \\(invoice.status = 'open' AND investor.kyc_status = 'verified'\\)"
+	}
+	mock.ExpectQuery(query).WithArgs("invoice-id", partyID).WillReturnRows(sqlmock.NewRows([]string{"allowed"}).AddRow(allowed))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUploadInvoiceDocument(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	blobs := NewMemoryBlobStore()
+	s := &server{db: db, blobs: blobs}
+
+	expectDocumentAccess(mock, PartyIssuer, "issuer-id", true)
+	mock.ExpectQuery("INSERT INTO invoice_document").
+		WithArgs("invoice-id", "invoice.pdf", "application/pdf", int64(len(testPDF)), sha256Hex(testPDF), sqlmock.AnyArg(), "issuer-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("document-id"))
+
+	stream := &uploadStream{ctx: asParty(PartyIssuer, "issuer-id"), chunks: chunked(testPDF, 1000)}
+	err = s.UploadInvoiceDocument(stream)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "document-id", stream.response.Id)
+	assert.Equal(t, sha256Hex(testPDF), stream.response.Sha256)
+	assert.Len(t, blobs.blobs, 1)
+	for _, stored := range blobs.blobs {
+		assert.Equal(t, testPDF, stored)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUploadInvoiceDocumentRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		maxSize  int64
+		insertOK bool
+		err      error
+	}{
+		{"not a document", []byte("#!/bin/sh\nrm -rf /\n"), 0, true, ErrDocumentContentType},
+		{"too large", testPDF, 4096, true, ErrDocumentTooLarge},
+		{"insert fails", testPDF, 0, false, sqlmock.ErrCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			blobs := NewMemoryBlobStore()
+			s := &server{db: db, blobs: blobs, maxDocumentSize: tt.maxSize}
+
+			expectDocumentAccess(mock, PartyIssuer, "issuer-id", true)
+			if !tt.insertOK {
+				mock.ExpectQuery("INSERT INTO invoice_document").WillReturnError(sqlmock.ErrCancelled)
+			}
+
+			err = s.UploadInvoiceDocument(&uploadStream{ctx: asParty(PartyIssuer, "issuer-id"), chunks: chunked(tt.data, 1000)})
+
+			assert.ErrorIs(t, err, tt.err)
+			// Nothing is left in the store
+			assert.Empty(t, blobs.blobs)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUploadInvoiceDocumentNotIssuer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, blobs: NewMemoryBlobStore()}
+
+	// The party claimed in the request is ignored, the access is checked for the caller of the token
+	expectDocumentAccess(mock, PartyIssuer, "other-issuer-id", false)
+	chunks := chunked(testPDF, 1000)
+	chunks[0].PartyType, chunks[0].PartyId = PartyIssuer, "issuer-id"
+
+	err = s.UploadInvoiceDocument(&uploadStream{ctx: asParty(PartyIssuer, "other-issuer-id"), chunks: chunks})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Investors and anonymous callers don't get as far as the database
+	err = s.UploadInvoiceDocument(&uploadStream{ctx: asParty(PartyInvestor, "investor-id"), chunks: chunked(testPDF, 1000)})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	err = s.UploadInvoiceDocument(&uploadStream{ctx: context.Background(), chunks: chunked(testPDF, 1000)})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestDownloadInvoiceDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		stored  []byte
+		allowed bool
+		code    codes.Code
+	}{
+		{"allowed", testPDF, true, codes.OK},
+		{"not allowed", testPDF, false, codes.PermissionDenied},
+		{"corrupted", append(testPDF[:len(testPDF)-1:len(testPDF)-1], '!'), true, codes.DataLoss},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			blobs := NewMemoryBlobStore()
+			assert.NoError(t, blobs.Put(context.Background(), "invoice/invoice-id/key", bytes.NewReader(tt.stored)))
+			s := &server{db: db, blobs: blobs}
+
+			expectDocumentAccess(mock, PartyInvestor, "investor-id", tt.allowed)
+			if tt.allowed {
+				mock.ExpectQuery("SELECT id, invoice_id, file_name, content_type, size, sha256, blob_key FROM invoice_document").
+					WithArgs("invoice-id", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "invoice_id", "file_name", "content_type", "size", "sha256", "blob_key"}).
+						AddRow("document-id", "invoice-id", "invoice.pdf", "application/pdf", len(testPDF), sha256Hex(testPDF), "invoice/invoice-id/key"))
+			}
+
+			stream := &downloadStream{ctx: asParty(PartyInvestor, "investor-id")}
+			err = s.DownloadInvoiceDocument(&pb.DocumentRequest{InvoiceId: "invoice-id"}, stream)
+
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.NoError(t, mock.ExpectationsWereMet())
+			if tt.code != codes.OK {
+				return
+			}
+			var content []byte
+			for _, chunk := range stream.chunks {
+				content = append(content, chunk.Data...)
+			}
+			assert.Equal(t, testPDF, content)
+			assert.Len(t, stream.chunks, 3)
+			assert.Equal(t, "invoice.pdf", stream.chunks[0].FileName)
+			assert.Equal(t, sha256Hex(testPDF), stream.chunks[0].Sha256)
+		})
+	}
+}
+
+func TestDownloadInvoiceDocumentRequiresToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, blobs: NewMemoryBlobStore()}
+
+	// Naming a party in the request doesn't stand in for its token
+	request := &pb.DocumentRequest{InvoiceId: "invoice-id", PartyType: PartyIssuer, PartyId: "issuer-id"}
+	err = s.DownloadInvoiceDocument(request, &downloadStream{ctx: context.Background()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	err = s.DownloadInvoiceDocument(request, &downloadStream{ctx: withPrincipal(context.Background(), &Principal{Role: RoleAdmin})})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "invoice/invoice-id/key", bytes.NewReader(testPDF)))
+	blob, err := store.Get(ctx, "invoice/invoice-id/key")
+	assert.NoError(t, err)
+	content, err := io.ReadAll(blob)
+	blob.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, testPDF, content)
+
+	assert.NoError(t, store.Delete(ctx, "invoice/invoice-id/key"))
+	_, err = store.Get(ctx, "invoice/invoice-id/key")
+	assert.True(t, errors.Is(err, ErrBlobNotFound))
+
+	// Keys can't escape the directory
+	assert.Error(t, store.Put(ctx, "../outside", bytes.NewReader(testPDF)))
+}
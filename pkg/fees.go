@@ -0,0 +1,208 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"slices"
+	"sort"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+)
+
+// Who pays the fee of a trade
+const (
+	FeePayerIssuer   = "issuer"
+	FeePayerInvestor = "investor"
+	// FeePayerBoth splits the fee evenly between issuer and investor
+	FeePayerBoth = "both"
+)
+
+// ErrInsufficientBalanceForFee is returned when the investor can't pay their share of the trade fee
+var ErrInsufficientBalanceForFee = errors.New("investor doesn't have enough balance to pay the trade fee")
+
+// ErrFeeExceedsAmount is returned when the issuer's share of the trade fee would take all of the trade amount
+var ErrFeeExceedsAmount = errors.New("issuer fee is not below the trade amount")
+
+// FeeTier replaces the percentage of a schedule once the issuer's settled volume reaches MinVolume
+type FeeTier struct {
+	MinVolume  float64 `json:"min_volume"`
+	Percentage float64 `json:"percentage"`
+}
+
+// FeeSchedule describes the fee of a trade: Flat plus Percentage of the trade amount
+type FeeSchedule struct {
+	Payer      string    `json:"payer"`
+	Percentage float64   `json:"percentage"`
+	Flat       float64   `json:"flat"`
+	Tiers      []FeeTier `json:"tiers"`
+}
+
+// FeeSchedules holds the default schedule and per issuer overrides, keyed by issuer id
+type FeeSchedules struct {
+	Default FeeSchedule            `json:"default"`
+	Issuers map[string]FeeSchedule `json:"issuers"`
+}
+
+// LoadFeeSchedules reads the fee schedules from a JSON file. Without a file no fees are charged.
+func LoadFeeSchedules(path string) (*FeeSchedules, error) {
+	schedules := &FeeSchedules{}
+	if path == "" {
+		return schedules, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fee schedule file: %w", err)
+	}
+	if err := json.Unmarshal(b, schedules); err != nil {
+		return nil, fmt.Errorf("failed to parse fee schedule file %s: %w", path, err)
+	}
+	if err := schedules.Validate(); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Validate reports every invalid schedule at once and sorts the tiers by volume
+func (f *FeeSchedules) Validate() error {
+	var errs []error
+	errs = append(errs, f.Default.validate("default")...)
+	for issuerID, schedule := range f.Issuers {
+		// The copy shares its tiers with the map entry, so they are sorted in place
+		errs = append(errs, schedule.validate("issuer "+issuerID)...)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid fee schedules: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func (s *FeeSchedule) validate(name string) []error {
+	var errs []error
+	if s.Payer != "" && s.Payer != FeePayerIssuer && s.Payer != FeePayerInvestor && s.Payer != FeePayerBoth {
+		errs = append(errs, fmt.Errorf("%s: payer %q must be one of issuer, investor, both", name, s.Payer))
+	}
+	if s.Percentage < 0 || s.Percentage > 100 {
+		errs = append(errs, fmt.Errorf("%s: percentage must be between 0 and 100", name))
+	}
+	if s.Flat < 0 {
+		errs = append(errs, fmt.Errorf("%s: flat fee must not be negative", name))
+	}
+	for _, tier := range s.Tiers {
+		if tier.Percentage < 0 || tier.Percentage > 100 || tier.MinVolume < 0 {
+			errs = append(errs, fmt.Errorf("%s: tier percentage must be between 0 and 100 and min_volume must not be negative", name))
+		}
+	}
+	// An issuer paying the whole fee would get nothing for any trade
+	if s.Payer == "" || s.Payer == FeePayerIssuer {
+		if s.Percentage == 100 || slices.ContainsFunc(s.Tiers, func(tier FeeTier) bool { return tier.Percentage == 100 }) {
+			errs = append(errs, fmt.Errorf("%s: percentage must be below 100 when the issuer pays the fee", name))
+		}
+	}
+	sort.Slice(s.Tiers, func(i, j int) bool { return s.Tiers[i].MinVolume < s.Tiers[j].MinVolume })
+	return errs
+}
+
+// For returns the schedule of the issuer, falling back to the default one
+func (f *FeeSchedules) For(issuerID string) FeeSchedule {
+	if schedule, ok := f.Issuers[issuerID]; ok {
+		return schedule
+	}
+	return f.Default
+}
+
+// Compute returns the fees of a trade of amount, volume being what the issuer settled before it.
+// A trade whose issuer fee would take the whole amount, e.g. a flat fee larger than it, is rejected.
+func (s FeeSchedule) Compute(amount float32, volume float64) (*pb.FeeBreakdown, error) {
+	percentage := s.Percentage
+	for _, tier := range s.Tiers {
+		if volume >= tier.MinVolume {
+			percentage = tier.Percentage
+		}
+	}
+	total := roundCents(s.Flat + float64(amount)*percentage/100)
+
+	fees := &pb.FeeBreakdown{TotalFee: float32(total)}
+	switch s.Payer {
+	case FeePayerInvestor:
+		fees.InvestorFee = float32(total)
+	case FeePayerBoth:
+		fees.IssuerFee = float32(roundCents(total / 2))
+		fees.InvestorFee = float32(total) - fees.IssuerFee
+	default:
+		fees.IssuerFee = float32(total)
+	}
+	if fees.IssuerFee > 0 && fees.IssuerFee >= amount {
+		return nil, fmt.Errorf("%w: fee %.2f, amount %.2f", ErrFeeExceedsAmount, fees.IssuerFee, amount)
+	}
+	fees.IssuerProceeds = amount - fees.IssuerFee
+	return fees, nil
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// GetIssuerSettledVolume returns the issuer of the invoice and the volume of the trades it settled so far
+func GetIssuerSettledVolume(ctx context.Context, db dbtx, in *pb.Bid) (issuerID string, volume float64, err error) {
+	ctx, span := startSpan(ctx, "GetIssuerSettledVolume", in)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `SELECT invoice.issuer_id, COALESCE((SELECT SUM(amount) FROM trade WHERE trade.issuer_id = invoice.issuer_id), 0)
+		FROM invoice WHERE invoice.id = $1`, in.GetInvoiceId()).Scan(&issuerID, &volume)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get issuer's settled volume: %w", err)
+	}
+	return issuerID, volume, nil
+}
+
+// ChargeInvestorFee takes the investor's share of the trade fee from their balance
+func ChargeInvestorFee(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "ChargeInvestorFee", in)
+	defer func() { endSpan(span, err) }()
+	fee := in.GetFees().GetInvestorFee()
+	if fee == 0 {
+		return nil
+	}
+	slog.Debug("Charging investor fee", "investor_id", in.GetInvestorId(), "fee", fee)
+	res, err := db.ExecContext(ctx, "UPDATE investor SET balance = balance - $1 WHERE id = $2 AND balance >= $1", fee, in.GetInvestorId())
+	if err != nil {
+		return fmt.Errorf("failed to charge investor's fee: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to charge investor's fee: %w", err)
+	}
+	if n == 0 {
+		return ErrInsufficientBalanceForFee
+	}
+	return nil
+}
+
+// CreditPlatformRevenue adds the fees of a trade to the platform revenue account
+func CreditPlatformRevenue(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "CreditPlatformRevenue", in)
+	defer func() { endSpan(span, err) }()
+	_, err = db.ExecContext(ctx, "UPDATE platform_account SET balance = balance + $1 WHERE id = 'revenue'", in.GetFees().GetTotalFee())
+	if err != nil {
+		return fmt.Errorf("failed to credit platform revenue: %w", err)
+	}
+	return nil
+}
+
+// InsertTrade records a settled trade with its fee breakdown
+func InsertTrade(ctx context.Context, db dbtx, in *pb.Bid, issuerID string) (err error) {
+	ctx, span := startSpan(ctx, "InsertTrade", in)
+	defer func() { endSpan(span, err) }()
+	fees := in.GetFees()
+	_, err = db.ExecContext(ctx, `INSERT INTO trade (invoice_id, investor_id, issuer_id, amount, issuer_fee, investor_fee)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		in.GetInvoiceId(), in.GetInvestorId(), issuerID, in.GetAmount(), fees.GetIssuerFee(), fees.GetInvestorFee())
+	if err != nil {
+		return fmt.Errorf("failed to insert trade: %w", err)
+	}
+	return nil
+}
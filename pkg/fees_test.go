@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeScheduleCompute(t *testing.T) {
+	tiered := FeeSchedule{Percentage: 3, Tiers: []FeeTier{{MinVolume: 1000, Percentage: 2}, {MinVolume: 10000, Percentage: 1}}}
+
+	tests := []struct {
+		name           string
+		schedule       FeeSchedule
+		volume         float64
+		issuerFee      float32
+		investorFee    float32
+		issuerProceeds float32
+	}{
+		{"no fees", FeeSchedule{}, 0, 0, 0, 200},
+		{"percentage charged to issuer", FeeSchedule{Percentage: 1.5}, 0, 3, 0, 197},
+		{"flat charged to investor", FeeSchedule{Payer: FeePayerInvestor, Flat: 5}, 0, 0, 5, 200},
+		{"split between both", FeeSchedule{Payer: FeePayerBoth, Percentage: 1, Flat: 1}, 0, 1.5, 1.5, 198.5},
+		{"below first tier", tiered, 500, 6, 0, 194},
+		{"first tier", tiered, 1000, 4, 0, 196},
+		{"highest tier", tiered, 50000, 2, 0, 198},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees, err := tt.schedule.Compute(200, tt.volume)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.issuerFee, fees.IssuerFee)
+			assert.Equal(t, tt.investorFee, fees.InvestorFee)
+			assert.Equal(t, tt.issuerFee+tt.investorFee, fees.TotalFee)
+			assert.Equal(t, tt.issuerProceeds, fees.IssuerProceeds)
+		})
+	}
+}
+
+func TestFeeScheduleComputeFeeOverAmount(t *testing.T) {
+	// A flat fee at or above the amount would leave the issuer with nothing or less
+	for _, amount := range []float32{20, 25} {
+		_, err := FeeSchedule{Flat: 25}.Compute(amount, 0)
+		assert.ErrorIs(t, err, ErrFeeExceedsAmount)
+	}
+
+	// The investor's share is paid on top of the amount
+	fees, err := FeeSchedule{Payer: FeePayerInvestor, Flat: 25}.Compute(20, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(20), fees.IssuerProceeds)
+}
+
+func TestLoadFeeSchedules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fees.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{
+		"default": {"percentage": 2, "tiers": [{"min_volume": 10000, "percentage": 1}, {"min_volume": 1000, "percentage": 1.5}]},
+		"issuers": {"issuer-id": {"payer": "investor", "flat": 10}}
+	}`), 0o600))
+
+	schedules, err := LoadFeeSchedules(path)
+
+	assert.NoError(t, err)
+	// Tiers are sorted by volume
+	assert.Equal(t, []FeeTier{{MinVolume: 1000, Percentage: 1.5}, {MinVolume: 10000, Percentage: 1}}, schedules.For("other-issuer").Tiers)
+	assert.Equal(t, FeeSchedule{Payer: FeePayerInvestor, Flat: 10}, schedules.For("issuer-id"))
+}
+
+func TestLoadFeeSchedulesValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fees.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{
+		"default": {"payer": "debtor", "percentage": 150},
+		"issuers": {"issuer-id": {"flat": -1, "tiers": [{"min_volume": 1000, "percentage": 100}]}}
+	}`), 0o600))
+
+	_, err := LoadFeeSchedules(path)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `payer "debtor"`)
+	assert.Contains(t, err.Error(), "percentage must be between 0 and 100")
+	assert.Contains(t, err.Error(), "issuer issuer-id: flat fee must not be negative")
+	assert.Contains(t, err.Error(), "issuer issuer-id: percentage must be below 100 when the issuer pays the fee")
+}
@@ -0,0 +1,309 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// NewGateway returns the REST/JSON front of the InvoiceService. Every route is forwarded through conn,
+// so requests go through the same interceptors as native gRPC calls and gRPC status codes are mapped
+// to HTTP status codes. Bodies are encoded with protojson.
+func NewGateway(conn grpc.ClientConnInterface) http.Handler {
+	mux := runtime.NewServeMux()
+	client := pb.NewInvoiceServiceClient(conn)
+
+	handleUnary(mux, "POST", "/v1/invoices", pb.InvoiceService_CreateInvoice_FullMethodName, true,
+		func() *pb.Invoice { return &pb.Invoice{} },
+		func(in *pb.Invoice, params map[string]string) {},
+		client.CreateInvoice)
+	// The document is base64 encoded in the JSON body
+	handleUnary(mux, "POST", "/v1/invoice-imports", pb.InvoiceService_ImportInvoice_FullMethodName, true,
+		func() *pb.InvoiceImport { return &pb.InvoiceImport{} },
+		func(in *pb.InvoiceImport, params map[string]string) {},
+		client.ImportInvoice)
+	handleUnary(mux, "GET", "/v1/invoices/{id}", pb.InvoiceService_GetInvoice_FullMethodName, false,
+		func() *pb.Invoice { return &pb.Invoice{} },
+		func(in *pb.Invoice, params map[string]string) { in.Id = params["id"] },
+		client.GetInvoice)
+	handleUnary(mux, "GET", "/v1/issuers/{id}", pb.InvoiceService_GetIssuer_FullMethodName, false,
+		func() *pb.Issuer { return &pb.Issuer{} },
+		func(in *pb.Issuer, params map[string]string) { in.Id = params["id"] },
+		client.GetIssuer)
+	handleUnary(mux, "GET", "/v1/issuers/{id}/summary", pb.InvoiceService_GetIssuerSummary_FullMethodName, false,
+		func() *pb.Issuer { return &pb.Issuer{} },
+		func(in *pb.Issuer, params map[string]string) { in.Id = params["id"] },
+		client.GetIssuerSummary)
+	handleUnary(mux, "POST", "/v1/invoices/{id}/bids", pb.InvoiceService_PlaceBid_FullMethodName, true,
+		func() *pb.Bid { return &pb.Bid{} },
+		func(in *pb.Bid, params map[string]string) { in.InvoiceId = params["id"] },
+		client.PlaceBid)
+	handleUnary(mux, "POST", "/v1/bids/{id}/cancellation", pb.InvoiceService_CancelBid_FullMethodName, true,
+		func() *pb.BidCancellation { return &pb.BidCancellation{} },
+		func(in *pb.BidCancellation, params map[string]string) { in.BidId = params["id"] },
+		client.CancelBid)
+	handleUnary(mux, "POST", "/v1/invoices/{id}/trades", pb.InvoiceService_ApproveTrade_FullMethodName, true,
+		func() *pb.Bid { return &pb.Bid{} },
+		func(in *pb.Bid, params map[string]string) { in.InvoiceId = params["id"] },
+		client.ApproveTrade)
+	handleUnary(mux, "POST", "/v1/invoices/{id}/repayments", pb.InvoiceService_RecordRepayment_FullMethodName, true,
+		func() *pb.Repayment { return &pb.Repayment{} },
+		func(in *pb.Repayment, params map[string]string) { in.InvoiceId = params["id"] },
+		client.RecordRepayment)
+	handleUnary(mux, "PUT", "/v1/investor-tiers/{name}", pb.InvoiceService_SetInvestorTier_FullMethodName, true,
+		func() *pb.InvestorTier { return &pb.InvestorTier{} },
+		func(in *pb.InvestorTier, params map[string]string) { in.Name = params["name"] },
+		client.SetInvestorTier)
+	handleUnary(mux, "GET", "/v1/investors/{id}/portfolio", pb.InvoiceService_GetPortfolio_FullMethodName, false,
+		func() *pb.PortfolioRequest { return &pb.PortfolioRequest{} },
+		func(in *pb.PortfolioRequest, params map[string]string) { in.InvestorId = params["id"] },
+		client.GetPortfolio)
+	handleUnary(mux, "PUT", "/v1/investors/{id}/tier", pb.InvoiceService_AssignInvestorTier_FullMethodName, true,
+		func() *pb.Investor { return &pb.Investor{} },
+		func(in *pb.Investor, params map[string]string) { in.Id = params["id"] },
+		client.AssignInvestorTier)
+	handleUnary(mux, "POST", "/v1/investors/{id}/auto-bid-rules", pb.InvoiceService_CreateAutoBidRule_FullMethodName, true,
+		func() *pb.AutoBidRule { return &pb.AutoBidRule{} },
+		func(in *pb.AutoBidRule, params map[string]string) { in.InvestorId = params["id"] },
+		client.CreateAutoBidRule)
+	handleUnary(mux, "PUT", "/v1/investors/{investor_id}/auto-bid-rules/{id}", pb.InvoiceService_UpdateAutoBidRule_FullMethodName, true,
+		func() *pb.AutoBidRule { return &pb.AutoBidRule{} },
+		func(in *pb.AutoBidRule, params map[string]string) {
+			in.InvestorId, in.Id = params["investor_id"], params["id"]
+		},
+		client.UpdateAutoBidRule)
+	handleUnary(mux, "POST", "/v1/investors/{investor_id}/auto-bid-rules/{id}/status", pb.InvoiceService_SetAutoBidRuleStatus_FullMethodName, true,
+		func() *pb.AutoBidRuleStatus { return &pb.AutoBidRuleStatus{} },
+		func(in *pb.AutoBidRuleStatus, params map[string]string) {
+			in.InvestorId, in.Id = params["investor_id"], params["id"]
+		},
+		client.SetAutoBidRuleStatus)
+	handleUnary(mux, "POST", "/v1/kyc/{party_type}/{party_id}/documents", pb.InvoiceService_SubmitKycDocument_FullMethodName, true,
+		func() *pb.KycDocument { return &pb.KycDocument{} },
+		func(in *pb.KycDocument, params map[string]string) {
+			in.PartyType, in.PartyId = params["party_type"], params["party_id"]
+		},
+		client.SubmitKycDocument)
+	handleUnary(mux, "GET", "/v1/kyc/{party_type}/{party_id}", pb.InvoiceService_GetKycStatus_FullMethodName, false,
+		func() *pb.KycParty { return &pb.KycParty{} },
+		func(in *pb.KycParty, params map[string]string) {
+			in.PartyType, in.PartyId = params["party_type"], params["party_id"]
+		},
+		client.GetKycStatus)
+	handleUnary(mux, "POST", "/v1/kyc/{party_type}/{party_id}/verify", pb.InvoiceService_VerifyKyc_FullMethodName, true,
+		func() *pb.KycParty { return &pb.KycParty{} },
+		func(in *pb.KycParty, params map[string]string) {
+			in.PartyType, in.PartyId = params["party_type"], params["party_id"]
+		},
+		client.VerifyKyc)
+	handleUnary(mux, "POST", "/v1/kyc/{party_type}/{party_id}/reviews", pb.InvoiceService_ReviewKyc_FullMethodName, true,
+		func() *pb.KycReview { return &pb.KycReview{} },
+		func(in *pb.KycReview, params map[string]string) {
+			in.PartyType, in.PartyId = params["party_type"], params["party_id"]
+		},
+		client.ReviewKyc)
+	handleUnary(mux, "POST", "/v1/webhooks/{party_type}/{party_id}/endpoints", pb.InvoiceService_RegisterWebhookEndpoint_FullMethodName, true,
+		func() *pb.WebhookEndpoint { return &pb.WebhookEndpoint{} },
+		func(in *pb.WebhookEndpoint, params map[string]string) {
+			in.PartyType, in.PartyId = params["party_type"], params["party_id"]
+		},
+		client.RegisterWebhookEndpoint)
+	handleUnary(mux, "DELETE", "/v1/webhooks/{party_type}/{party_id}/endpoints/{id}", pb.InvoiceService_DeleteWebhookEndpoint_FullMethodName, false,
+		func() *pb.WebhookEndpoint { return &pb.WebhookEndpoint{} },
+		func(in *pb.WebhookEndpoint, params map[string]string) {
+			in.PartyType, in.PartyId, in.Id = params["party_type"], params["party_id"], params["id"]
+		},
+		client.DeleteWebhookEndpoint)
+	handleUnary(mux, "POST", "/v1/webhooks/{party_type}/{party_id}/endpoints/{id}/test", pb.InvoiceService_TestWebhookEndpoint_FullMethodName, false,
+		func() *pb.WebhookEndpoint { return &pb.WebhookEndpoint{} },
+		func(in *pb.WebhookEndpoint, params map[string]string) {
+			in.PartyType, in.PartyId, in.Id = params["party_type"], params["party_id"], params["id"]
+		},
+		client.TestWebhookEndpoint)
+	handleUnary(mux, "POST", "/v1/webhooks/{party_type}/{party_id}/dead-letters/{id}/replay", pb.InvoiceService_ReplayWebhookDeadLetter_FullMethodName, false,
+		func() *pb.WebhookReplay { return &pb.WebhookReplay{} },
+		func(in *pb.WebhookReplay, params map[string]string) {
+			in.PartyType, in.PartyId, in.DeadLetterId = params["party_type"], params["party_id"], params["id"]
+		},
+		client.ReplayWebhookDeadLetter)
+
+	// Streams are written as newline delimited JSON, one {"result": ...} object per message
+	handleServerStream(mux, "GET", "/v1/investors", pb.InvoiceService_GetInvestors_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			stream, err := client.GetInvestors(ctx, &emptypb.Empty{}, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+	handleServerStream(mux, "GET", "/v1/investor-tiers", pb.InvoiceService_ListInvestorTiers_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			stream, err := client.ListInvestorTiers(ctx, &emptypb.Empty{}, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+	handleServerStream(mux, "GET", "/v1/investors/{id}/auto-bid-rules", pb.InvoiceService_ListAutoBidRules_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			stream, err := client.ListAutoBidRules(ctx, &pb.Investor{Id: params["id"]}, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+	handleServerStream(mux, "GET", "/v1/investors/{investor_id}/auto-bid-rules/{id}/executions", pb.InvoiceService_ListAutoBidExecutions_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			in := &pb.AutoBidExecutionRequest{}
+			if err := runtime.PopulateQueryParameters(in, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+			}
+			in.InvestorId, in.RuleId = params["investor_id"], params["id"]
+			stream, err := client.ListAutoBidExecutions(ctx, in, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+	handleServerStream(mux, "GET", "/v1/webhooks/{party_type}/{party_id}/endpoints", pb.InvoiceService_ListWebhookEndpoints_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			in := &pb.WebhookEndpoint{PartyType: params["party_type"], PartyId: params["party_id"]}
+			stream, err := client.ListWebhookEndpoints(ctx, in, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+	handleServerStream(mux, "GET", "/v1/webhooks/{party_type}/{party_id}/endpoints/{id}/dead-letters", pb.InvoiceService_ListWebhookDeadLetters_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			in := &pb.WebhookEndpoint{PartyType: params["party_type"], PartyId: params["party_id"], Id: params["id"]}
+			stream, err := client.ListWebhookDeadLetters(ctx, in, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+	// The document chunks carry base64 encoded data, uploads are only available over gRPC
+	handleServerStream(mux, "GET", "/v1/invoices/{id}/documents/{document_id}", pb.InvoiceService_DownloadInvoiceDocument_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			in := &pb.DocumentRequest{}
+			if err := runtime.PopulateQueryParameters(in, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+			}
+			in.InvoiceId, in.DocumentId = params["id"], params["document_id"]
+			stream, err := client.DownloadInvoiceDocument(ctx, in, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+	handleServerStream(mux, "GET", "/v1/investors/{id}/statement", pb.InvoiceService_ExportInvestorStatement_FullMethodName,
+		func(ctx context.Context, r *http.Request, params map[string]string, opts ...grpc.CallOption) (func() (proto.Message, error), error) {
+			in := &pb.StatementRequest{}
+			if err := runtime.PopulateQueryParameters(in, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+			}
+			in.InvestorId = params["id"]
+			stream, err := client.ExportInvestorStatement(ctx, in, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		})
+
+	return mux
+}
+
+// handleUnary registers a route forwarding to a unary RPC. The request message is decoded from the body when
+// body is set and from the query string otherwise, then bind copies the path parameters into it.
+func handleUnary[Req, Resp proto.Message](
+	mux *runtime.ServeMux,
+	method string,
+	pattern string,
+	rpcName string,
+	body bool,
+	newReq func() Req,
+	bind func(Req, map[string]string),
+	call func(context.Context, Req, ...grpc.CallOption) (Resp, error),
+) {
+	err := mux.HandlePath(method, pattern, func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		inbound, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(ctx, mux, r, rpcName, runtime.WithHTTPPathPattern(pattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		in := newReq()
+		if body {
+			if err := inbound.NewDecoder(r.Body).Decode(in); err != nil && !errors.Is(err, io.EOF) {
+				runtime.HTTPError(ctx, mux, outbound, w, r, status.Errorf(codes.InvalidArgument, "%v", err))
+				return
+			}
+		} else if err := runtime.PopulateQueryParameters(in, r.URL.Query(), utilities.NewDoubleArray(nil)); err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, status.Errorf(codes.InvalidArgument, "%v", err))
+			return
+		}
+		bind(in, params)
+
+		var md runtime.ServerMetadata
+		resp, err := call(ctx, in, grpc.Header(&md.HeaderMD), grpc.Trailer(&md.TrailerMD))
+		ctx = runtime.NewServerMetadataContext(ctx, md)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		runtime.ForwardResponseMessage(ctx, mux, outbound, w, r, resp)
+	})
+	if err != nil {
+		// Patterns are constants, this can only be a programming error
+		panic(err)
+	}
+}
+
+// handleServerStream registers a route forwarding to a server streaming RPC, open starts the stream from the request
+// and its path parameters and returns its receive function.
+func handleServerStream(
+	mux *runtime.ServeMux,
+	method string,
+	pattern string,
+	rpcName string,
+	open func(context.Context, *http.Request, map[string]string, ...grpc.CallOption) (func() (proto.Message, error), error),
+) {
+	err := mux.HandlePath(method, pattern, func(w http.ResponseWriter, r *http.Request, params map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx, err := runtime.AnnotateContext(ctx, mux, r, rpcName, runtime.WithHTTPPathPattern(pattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		var md runtime.ServerMetadata
+		recv, err := open(ctx, r, params, grpc.Header(&md.HeaderMD), grpc.Trailer(&md.TrailerMD))
+		ctx = runtime.NewServerMetadataContext(ctx, md)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		runtime.ForwardResponseStream(ctx, mux, outbound, w, r, recv)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
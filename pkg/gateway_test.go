@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupGateway serves the InvoiceService over an in-memory listener and returns an HTTP server for its gateway
+func setupGateway(t *testing.T) (*httptest.Server, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	lis := bufconn.Listen(1024 * 1024)
+	s, _, _, err := SetupServer(db, &cfg.Config{AuthTokenFile: writeAuthTokens(t)})
+	assert.NoError(t, err)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ts := httptest.NewServer(NewGateway(conn))
+	t.Cleanup(ts.Close)
+	return ts, mock
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
+
+func TestGatewayGetIssuer(t *testing.T) {
+	ts, mock := setupGateway(t)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).AddRow("1", "Issuer Name", 100.0)
+	mock.ExpectQuery("SELECT id, name, balance FROM issuer WHERE id = \\$1").WithArgs("1").WillReturnRows(rows)
+	expectIssuerRisk(mock, "1", RiskInputs{})
+
+	resp, err := http.Get(ts.URL + "/v1/issuers/1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"id": "1", "name": "Issuer Name", "balance": 100, "riskScore": 75, "riskGrade": "B", "fundingLimit": 250000, "outstanding": 0}`, readBody(t, resp))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGatewayCreateInvoice(t *testing.T) {
+	ts, mock := setupGateway(t)
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	expectInsertInvoice(mock, &pb.Invoice{
+		IssuerId: "issuer-id", Status: "open", Price: 10, FaceValue: 10, InvoiceNumber: "INV-1", IssueDate: "2024-03-01", Currency: DefaultCurrency,
+	}, "invoice-id")
+
+	resp, err := http.Post(ts.URL+"/v1/invoices", "application/json",
+		strings.NewReader(`{"issuerId": "issuer-id", "status": "open", "price": 10, "invoiceNumber": "INV-1", "issueDate": "2024-03-01"}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"id": "invoice-id", "issuerId": "issuer-id", "status": "open", "investorId": "", "price": 10, "faceValue": 10, "dueDate": "",
+		"repaidAmount": 0, "issuerRiskGrade": "", "invoiceNumber": "INV-1", "debtorName": "", "debtorReference": "", "issueDate": "2024-03-01",
+		"currency": "EUR", "lineItems": [], "netTotal": 0, "taxTotal": 0, "auctionEndsAt": "", "reservePrice": 0,
+		"minBidIncrement": 0, "maxBid": 0, "antiSnipingSeconds": 0}`, readBody(t, resp))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGatewayInvalidBody(t *testing.T) {
+	ts, _ := setupGateway(t)
+
+	resp, err := http.Post(ts.URL+"/v1/invoices/invoice-id/bids", "application/json", strings.NewReader(`{"amount": "lots"`))
+	assert.NoError(t, err)
+
+	// InvalidArgument maps to 400
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, readBody(t, resp), `"code":3`)
+}
+
+func TestGatewayGetInvestorsStream(t *testing.T) {
+	ts, mock := setupGateway(t)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "balance", "tier"}).
+		AddRow("1", "Investor 1", 1000.0, "standard").
+		AddRow("2", "Investor 2", 2000.0, "professional")
+	mock.ExpectQuery("SELECT id, name, balance, tier FROM Investor").WillReturnRows(rows)
+
+	resp, err := http.Get(ts.URL + "/v1/investors")
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	lines := strings.Split(strings.TrimSpace(readBody(t, resp)), "\n")
+	assert.Len(t, lines, 2)
+	assert.JSONEq(t, `{"result": {"id": "1", "name": "Investor 1", "balance": 1000, "tier": "standard"}}`, lines[0])
+	assert.JSONEq(t, `{"result": {"id": "2", "name": "Investor 2", "balance": 2000, "tier": "professional"}}`, lines[1])
+}
+
+func TestGatewayDownloadInvoiceDocumentDenied(t *testing.T) {
+	ts, mock := setupGateway(t)
+
+	expectDocumentAccess(mock, PartyInvestor, "investor-id", false)
+
+	// The gateway forwards the bearer token, the party in the query is ignored
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/invoices/invoice-id/documents/document-id?party_type=issuer&party_id=issuer-id", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer investor-token")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Contains(t, readBody(t, resp), "may not read the documents of invoice invoice-id")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
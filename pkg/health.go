@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const healthCheckInterval = 5 * time.Second
+
+// HealthChecker keeps the grpc.health.v1 status in line with the database.
+// The server is only SERVING while the database answers pings and the schema is fully migrated.
+type HealthChecker struct {
+	db     *sql.DB
+	server *health.Server
+}
+
+func newHealthChecker(db *sql.DB) *HealthChecker {
+	h := &HealthChecker{db: db, server: health.NewServer()}
+	// Nothing has been checked yet
+	h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// Check pings the database, looks for pending migrations and updates the serving status
+func (h *HealthChecker) Check(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckInterval)
+	defer cancel()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err := h.db.PingContext(ctx); err != nil {
+		slog.Error("Health check: database ping failed", "err", err)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	} else if pending, err := PendingMigrations(ctx, h.db); err != nil {
+		slog.Error("Health check: failed to check migrations", "err", err)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	} else if pending > 0 {
+		slog.Warn("Health check: migrations pending", "pending", pending)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	h.setStatus(status)
+	return status
+}
+
+// Run checks the health periodically until ctx is cancelled
+func (h *HealthChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	h.Check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Check(ctx)
+		}
+	}
+}
+
+// Shutdown reports NOT_SERVING for good, so load balancers stop sending traffic before the server stops
+func (h *HealthChecker) Shutdown() {
+	h.server.Shutdown()
+}
+
+// setStatus updates both the overall server status and the InvoiceService status
+func (h *HealthChecker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	h.server.SetServingStatus("", status)
+	h.server.SetServingStatus(pb.InvoiceService_ServiceDesc.ServiceName, status)
+}
@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func healthStatus(t *testing.T, h *HealthChecker) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := h.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "invoice.InvoiceService"})
+	assert.NoError(t, err)
+	return resp.Status
+}
+
+func TestHealthCheckServing(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectPing()
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(len(migrations)))
+
+	h := newHealthChecker(db)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h))
+
+	status := h.Check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, healthStatus(t, h))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHealthCheckDatabaseDown(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+	h := newHealthChecker(db)
+	status := h.Check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHealthCheckPendingMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectPing()
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+
+	h := newHealthChecker(db)
+	status := h.Check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHealthCheckShutdown(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectPing()
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(len(migrations)))
+
+	h := newHealthChecker(db)
+	h.Shutdown()
+	// A healthy check after shutdown must not flip the status back
+	h.Check(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h))
+}
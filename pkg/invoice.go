@@ -0,0 +1,190 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultCurrency is the currency of invoices that don't set one
+const DefaultCurrency = "EUR"
+
+// uniqueViolation is the PostgreSQL error code of unique constraint violations
+const uniqueViolation = "23505"
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// PrepareInvoice validates a new invoice and fills in what the client may leave out: the currency, the issue
+// date (today), the amounts and totals of the line items and the face value. The face value defaults to the
+// gross total of the line items, or the price when there are none, and has to match the line items when set.
+// New invoices are always listed open and without an investor, whatever the client sent.
+func PrepareInvoice(in *pb.Invoice, today time.Time) error {
+	return prepareInvoice(in, today, false)
+}
+
+// PrepareImportedInvoice prepares an invoice parsed from an e-invoice. Its net and tax totals and face value are
+// the document's, already checked against its LegalMonetaryTotal, and are kept as they are: the document may round
+// the tax once for the whole invoice rather than per line, or round the payable amount.
+func PrepareImportedInvoice(in *pb.Invoice, today time.Time) error {
+	return prepareInvoice(in, today, true)
+}
+
+func prepareInvoice(in *pb.Invoice, today time.Time, documentTotals bool) error {
+	in.Status, in.InvestorId = "open", ""
+	if in.GetPrice() <= 0 {
+		return errors.New("price must be greater than 0")
+	}
+	in.Currency = strings.ToUpper(in.GetCurrency())
+	if in.GetCurrency() == "" {
+		in.Currency = DefaultCurrency
+	}
+	if !currencyCode.MatchString(in.GetCurrency()) {
+		return fmt.Errorf("currency %q must be an ISO 4217 code", in.GetCurrency())
+	}
+	if len(in.GetInvoiceNumber()) > 64 {
+		return errors.New("invoice number must not be longer than 64 characters")
+	}
+
+	if in.GetIssueDate() == "" {
+		in.IssueDate = today.Format(dueDateLayout)
+	}
+	issued, err := time.Parse(dueDateLayout, in.GetIssueDate())
+	if err != nil {
+		return fmt.Errorf("issue date %q must be formatted as YYYY-MM-DD", in.GetIssueDate())
+	}
+	if err := ValidateDueDate(in.GetDueDate()); err != nil {
+		return err
+	}
+	if due, err := time.Parse(dueDateLayout, in.GetDueDate()); err == nil && due.Before(issued) {
+		return errors.New("due date must not be before the issue date")
+	}
+	if in.GetAuctionEndsAt() != "" {
+		ends, err := time.Parse(time.RFC3339, in.GetAuctionEndsAt())
+		if err != nil {
+			return fmt.Errorf("auction end %q must be an RFC 3339 time", in.GetAuctionEndsAt())
+		}
+		if !ends.After(today) {
+			return errors.New("auction end must be in the future")
+		}
+		in.AuctionEndsAt = ends.UTC().Format(time.RFC3339)
+	}
+	if err := ValidateAuctionRules(in); err != nil {
+		return err
+	}
+
+	var netTotal, taxTotal float64
+	for i, item := range in.GetLineItems() {
+		switch {
+		case item.GetDescription() == "":
+			return fmt.Errorf("line item %d: description is required", i+1)
+		case item.GetQuantity() <= 0:
+			return fmt.Errorf("line item %d: quantity must be greater than 0", i+1)
+		case item.GetUnitPrice() < 0:
+			return fmt.Errorf("line item %d: unit price must not be negative", i+1)
+		case item.GetTaxRate() < 0 || item.GetTaxRate() > 100:
+			return fmt.Errorf("line item %d: tax rate must be between 0 and 100", i+1)
+		}
+		net := roundCents(float64(item.GetQuantity()) * float64(item.GetUnitPrice()))
+		tax := roundCents(net * float64(item.GetTaxRate()) / 100)
+		item.NetAmount, item.TaxAmount = float32(net), float32(tax)
+		netTotal += net
+		taxTotal += tax
+	}
+	if !documentTotals {
+		in.NetTotal, in.TaxTotal = float32(netTotal), float32(taxTotal)
+		gross := roundCents(netTotal + taxTotal)
+		switch {
+		case in.GetFaceValue() == 0 && len(in.GetLineItems()) > 0:
+			in.FaceValue = float32(gross)
+		case in.GetFaceValue() == 0:
+			in.FaceValue = in.GetPrice()
+		case len(in.GetLineItems()) > 0 && math.Abs(float64(in.GetFaceValue())-gross) > balanceTolerance:
+			return fmt.Errorf("face value %.2f doesn't match the line items' total of %.2f", in.GetFaceValue(), gross)
+		}
+	}
+	if in.GetFaceValue() < in.GetPrice() {
+		return errors.New("face value must not be lower than the price")
+	}
+	return nil
+}
+
+// InsertInvoice records a prepared invoice with its line items and sets its id. Run it in a transaction, so an
+// invoice is never stored without its lines.
+func InsertInvoice(ctx context.Context, db dbtx, in *pb.Invoice) (err error) {
+	ctx, span := startSpan(ctx, "InsertInvoice", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `INSERT INTO invoice (issuer_id, status, investor_id, price, face_value, due_date, invoice_number,
+			debtor_name, debtor_reference, issue_date, currency, net_total, tax_total, auction_ends_at, reserve_price, min_bid_increment, max_bid,
+			anti_sniping_seconds)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, NULLIF($6, '')::date, NULLIF($7, ''), $8, $9, $10::date, $11, $12, $13,
+			NULLIF($14, '')::timestamptz AT TIME ZONE 'UTC', $15, $16, $17, $18) RETURNING id`,
+		in.GetIssuerId(), in.GetStatus(), in.GetInvestorId(), in.GetPrice(), in.GetFaceValue(), in.GetDueDate(), in.GetInvoiceNumber(),
+		in.GetDebtorName(), in.GetDebtorReference(), in.GetIssueDate(), in.GetCurrency(), in.GetNetTotal(), in.GetTaxTotal(),
+		in.GetAuctionEndsAt(), in.GetReservePrice(), in.GetMinBidIncrement(), in.GetMaxBid(), in.GetAntiSnipingSeconds()).Scan(&in.Id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "invoice_issuer_number" {
+			return status.Errorf(codes.AlreadyExists, "issuer %s already has an invoice numbered %q", in.GetIssuerId(), in.GetInvoiceNumber())
+		}
+		return fmt.Errorf("failed to insert invoice: %w", err)
+	}
+	for i, item := range in.GetLineItems() {
+		_, err = db.ExecContext(ctx, `INSERT INTO invoice_line_item (invoice_id, position, description, quantity, unit_price, tax_rate, net_amount, tax_amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			in.GetId(), i+1, item.GetDescription(), item.GetQuantity(), item.GetUnitPrice(), item.GetTaxRate(), item.GetNetAmount(), item.GetTaxAmount())
+		if err != nil {
+			return fmt.Errorf("failed to insert line item %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// GetInvoiceDetails returns the invoice with its line items, without the hidden reserve price
+func GetInvoiceDetails(ctx context.Context, db dbtx, id string) (invoice *pb.Invoice, err error) {
+	ctx, span := startSpan(ctx, "GetInvoiceDetails", nil)
+	defer func() { endSpan(span, err) }()
+	invoice = &pb.Invoice{}
+	err = db.QueryRowContext(ctx, `SELECT id, issuer_id, status, COALESCE(investor_id::text, ''), price, face_value,
+			COALESCE(to_char(due_date, 'YYYY-MM-DD'), ''), repaid_amount, COALESCE(invoice_number, ''), debtor_name, debtor_reference,
+			COALESCE(to_char(issue_date, 'YYYY-MM-DD'), ''), currency, net_total, tax_total,
+			COALESCE(to_char(auction_ends_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), ''), min_bid_increment, max_bid, anti_sniping_seconds
+		FROM invoice WHERE id = $1`, id).
+		Scan(&invoice.Id, &invoice.IssuerId, &invoice.Status, &invoice.InvestorId, &invoice.Price, &invoice.FaceValue,
+			&invoice.DueDate, &invoice.RepaidAmount, &invoice.InvoiceNumber, &invoice.DebtorName, &invoice.DebtorReference,
+			&invoice.IssueDate, &invoice.Currency, &invoice.NetTotal, &invoice.TaxTotal, &invoice.AuctionEndsAt, &invoice.MinBidIncrement,
+			&invoice.MaxBid, &invoice.AntiSnipingSeconds)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT description, quantity, unit_price, tax_rate, net_amount, tax_amount FROM invoice_line_item
+		WHERE invoice_id = $1 ORDER BY position`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query line items: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := &pb.LineItem{}
+		if err := rows.Scan(&item.Description, &item.Quantity, &item.UnitPrice, &item.TaxRate, &item.NetAmount, &item.TaxAmount); err != nil {
+			return nil, fmt.Errorf("failed to scan line item: %w", err)
+		}
+		invoice.LineItems = append(invoice.LineItems, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read line items: %w", err)
+	}
+	return invoice, nil
+}
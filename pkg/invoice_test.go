@@ -0,0 +1,177 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func expectInvoiceDetails(mock sqlmock.Sqlmock, invoice *pb.Invoice) {
+	mock.ExpectQuery("SELECT id, issuer_id, status, (.|\\n)+ FROM invoice WHERE id = \\$1").WithArgs(invoice.GetId()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "issuer_id", "status", "investor_id", "price", "face_value", "due_date", "repaid_amount",
+			"invoice_number", "debtor_name", "debtor_reference", "issue_date", "currency", "net_total", "tax_total", "auction_ends_at",
+			"min_bid_increment", "max_bid", "anti_sniping_seconds"}).
+			AddRow(invoice.GetId(), invoice.GetIssuerId(), invoice.GetStatus(), invoice.GetInvestorId(), invoice.GetPrice(), invoice.GetFaceValue(),
+				invoice.GetDueDate(), invoice.GetRepaidAmount(), invoice.GetInvoiceNumber(), invoice.GetDebtorName(), invoice.GetDebtorReference(),
+				invoice.GetIssueDate(), invoice.GetCurrency(), invoice.GetNetTotal(), invoice.GetTaxTotal(), invoice.GetAuctionEndsAt(),
+				invoice.GetMinBidIncrement(), invoice.GetMaxBid(), invoice.GetAntiSnipingSeconds()))
+	items := sqlmock.NewRows([]string{"description", "quantity", "unit_price", "tax_rate", "net_amount", "tax_amount"})
+	for _, item := range invoice.GetLineItems() {
+		items.AddRow(item.GetDescription(), item.GetQuantity(), item.GetUnitPrice(), item.GetTaxRate(), item.GetNetAmount(), item.GetTaxAmount())
+	}
+	mock.ExpectQuery("SELECT description, (.+) FROM invoice_line_item").WithArgs(invoice.GetId()).WillReturnRows(items)
+}
+
+// expectInsertInvoice expects the transaction storing a prepared invoice, which gets id as its id
+func expectInsertInvoice(mock sqlmock.Sqlmock, invoice *pb.Invoice, id string) {
+	mock.ExpectBegin()
+	expectLockIssuer(mock, invoice.GetIssuerId())
+	expectIssuerRisk(mock, invoice.GetIssuerId(), RiskInputs{})
+	mock.ExpectQuery("INSERT INTO invoice \\(issuer_id, status, investor_id, price, face_value, due_date, invoice_number,").
+		WithArgs(invoice.GetIssuerId(), invoice.GetStatus(), invoice.GetInvestorId(), invoice.GetPrice(), invoice.GetFaceValue(), invoice.GetDueDate(),
+			invoice.GetInvoiceNumber(), invoice.GetDebtorName(), invoice.GetDebtorReference(), invoice.GetIssueDate(), invoice.GetCurrency(),
+			invoice.GetNetTotal(), invoice.GetTaxTotal(), invoice.GetAuctionEndsAt(), invoice.GetReservePrice(), invoice.GetMinBidIncrement(),
+			invoice.GetMaxBid(), invoice.GetAntiSnipingSeconds()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	for i, item := range invoice.GetLineItems() {
+		mock.ExpectExec("INSERT INTO invoice_line_item").
+			WithArgs(id, i+1, item.GetDescription(), item.GetQuantity(), item.GetUnitPrice(), item.GetTaxRate(), item.GetNetAmount(), item.GetTaxAmount()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+}
+
+func TestPrepareInvoice(t *testing.T) {
+	today := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	in := &pb.Invoice{IssuerId: "issuer-id", Price: 8000, Currency: "usd", DueDate: "2024-05-30", AuctionEndsAt: "2024-03-08T17:00:00+01:00", LineItems: []*pb.LineItem{
+		{Description: "Consulting", Quantity: 10, UnitPrice: 650, TaxRate: 20},
+		{Description: "Travel", Quantity: 1, UnitPrice: 333.33},
+	}}
+
+	assert.NoError(t, PrepareInvoice(in, today))
+	assert.Equal(t, "USD", in.Currency)
+	assert.Equal(t, "2024-03-01", in.IssueDate)
+	assert.Equal(t, float32(6500), in.LineItems[0].NetAmount)
+	assert.Equal(t, float32(1300), in.LineItems[0].TaxAmount)
+	assert.Equal(t, float32(6833.33), in.NetTotal)
+	assert.Equal(t, float32(1300), in.TaxTotal)
+	// The face value is the gross total of the lines
+	assert.Equal(t, float32(8133.33), in.FaceValue)
+	assert.Equal(t, "2024-03-08T16:00:00Z", in.AuctionEndsAt)
+}
+
+func TestPrepareInvoiceDefaults(t *testing.T) {
+	in := &pb.Invoice{IssuerId: "issuer-id", Price: 10}
+
+	assert.NoError(t, PrepareInvoice(in, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, DefaultCurrency, in.Currency)
+	assert.Equal(t, "2024-03-01", in.IssueDate)
+	assert.Equal(t, float32(10), in.FaceValue)
+}
+
+func TestPrepareInvoiceValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *pb.Invoice
+		err  string
+	}{
+		{"no price", &pb.Invoice{}, "price must be greater than 0"},
+		{"bad currency", &pb.Invoice{Price: 10, Currency: "euro"}, `currency "EURO" must be an ISO 4217 code`},
+		{"bad issue date", &pb.Invoice{Price: 10, IssueDate: "01/03/2024"}, `issue date "01/03/2024" must be formatted as YYYY-MM-DD`},
+		{"due before issue", &pb.Invoice{Price: 10, IssueDate: "2024-03-01", DueDate: "2024-02-01"}, "due date must not be before the issue date"},
+		{"bad auction end", &pb.Invoice{Price: 10, AuctionEndsAt: "2024-03-08"}, `auction end "2024-03-08" must be an RFC 3339 time`},
+		{"auction ended", &pb.Invoice{Price: 10, AuctionEndsAt: "2020-01-01T00:00:00Z"}, "auction end must be in the future"},
+		{"reserve over the price", &pb.Invoice{Price: 10, ReservePrice: 11}, "reserve price must be between 0 and the price"},
+		{"negative increment", &pb.Invoice{Price: 10, MinBidIncrement: -1}, "minimum bid increment must not be negative"},
+		{"maximum bid over the price", &pb.Invoice{Price: 10, MaxBid: 11}, "maximum bid must be between 0 and the price"},
+		{"maximum bid under the reserve", &pb.Invoice{Price: 10, ReservePrice: 8, MaxBid: 7}, "maximum bid must not be lower than the reserve price"},
+		{"negative anti-sniping window", &pb.Invoice{Price: 10, AntiSnipingSeconds: -1}, "anti-sniping seconds must not be negative"},
+		{"line without description", &pb.Invoice{Price: 10, LineItems: []*pb.LineItem{{Quantity: 1, UnitPrice: 10}}}, "line item 1: description is required"},
+		{"line without quantity", &pb.Invoice{Price: 10, LineItems: []*pb.LineItem{{Description: "a", UnitPrice: 10}}}, "line item 1: quantity must be greater than 0"},
+		{"tax rate", &pb.Invoice{Price: 10, LineItems: []*pb.LineItem{{Description: "a", Quantity: 1, UnitPrice: 10, TaxRate: 120}}}, "line item 1: tax rate must be between 0 and 100"},
+		{"face value mismatch", &pb.Invoice{Price: 10, FaceValue: 50, LineItems: []*pb.LineItem{{Description: "a", Quantity: 2, UnitPrice: 10}}},
+			"face value 50.00 doesn't match the line items' total of 20.00"},
+		{"price above line items", &pb.Invoice{Price: 30, LineItems: []*pb.LineItem{{Description: "a", Quantity: 2, UnitPrice: 10}}},
+			"face value must not be lower than the price"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, PrepareInvoice(tt.in, time.Now()), tt.err)
+		})
+	}
+}
+
+func TestPrepareInvoiceListsOpen(t *testing.T) {
+	// A client can't list an invoice as already funded, or pick its investor
+	in := &pb.Invoice{IssuerId: "issuer-id", Status: "closed", InvestorId: "investor-id", Price: 10}
+
+	assert.NoError(t, PrepareInvoice(in, time.Now()))
+	assert.Equal(t, "open", in.Status)
+	assert.Empty(t, in.InvestorId)
+}
+
+func TestCreateAndGetInvoice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	in := &pb.Invoice{
+		IssuerId: "issuer-id", Status: "open", Price: 1100, InvoiceNumber: "INV-2024-001", DebtorName: "Buyer Ltd", DebtorReference: "GB123456",
+		IssueDate: "2024-03-01", DueDate: "2099-05-30", Currency: "GBP",
+		LineItems: []*pb.LineItem{{Description: "Widgets", Quantity: 100, UnitPrice: 10, TaxRate: 20}},
+	}
+	expected := proto.Clone(in).(*pb.Invoice)
+	assert.NoError(t, PrepareInvoice(expected, time.Now()))
+	expected.Id = "invoice-id"
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	expectInsertInvoice(mock, expected, "invoice-id")
+
+	created, err := s.CreateInvoice(context.Background(), in)
+
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(expected, created), "created %v", created)
+
+	expectInvoiceDetails(mock, expected)
+	expectIssuerRisk(mock, "issuer-id", RiskInputs{})
+
+	invoice, err := s.GetInvoice(context.Background(), &pb.Invoice{Id: "invoice-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "B", invoice.IssuerRiskGrade)
+	invoice.IssuerRiskGrade = ""
+	assert.True(t, proto.Equal(expected, invoice), "got %v", invoice)
+	assert.Equal(t, float32(1200), invoice.FaceValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateInvoiceDuplicateNumber(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	mock.ExpectBegin()
+	expectLockIssuer(mock, "issuer-id")
+	expectIssuerRisk(mock, "issuer-id", RiskInputs{})
+	mock.ExpectQuery("INSERT INTO invoice").WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "invoice_issuer_number"})
+	mock.ExpectRollback()
+
+	invoice, err := s.CreateInvoice(context.Background(), &pb.Invoice{IssuerId: "issuer-id", Status: "open", Price: 10, InvoiceNumber: "INV-1"})
+
+	assert.Nil(t, invoice)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Contains(t, err.Error(), `already has an invoice numbered "INV-1"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+)
+
+// owedStatuses are the statuses of funded invoices the issuer hasn't repaid yet, like the outstanding amount of
+// the issuer risk
+const owedStatuses = "('closed', 'overdue', 'defaulted')"
+
+// GetIssuerFunding returns the issuer with what investors paid for its funded invoices and the average discount
+// they were bought at
+func GetIssuerFunding(ctx context.Context, db dbtx, issuerID string) (summary *pb.IssuerSummary, err error) {
+	ctx, span := startSpan(ctx, "GetIssuerFunding", nil)
+	defer func() { endSpan(span, err) }()
+	summary = &pb.IssuerSummary{IssuerId: issuerID}
+	var raised, fees, faceValue float64
+	// An invoice can be funded by several trades, its face value is counted once
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(issuer.name, ''), issuer.balance, COUNT(funded.invoice_id),
+			COALESCE(SUM(funded.amount), 0), COALESCE(SUM(funded.fees), 0), COALESCE(SUM(funded.face_value), 0)
+		FROM issuer LEFT JOIN (
+			SELECT trade.invoice_id, invoice.face_value, SUM(trade.amount) AS amount, SUM(trade.issuer_fee) AS fees
+			FROM trade JOIN invoice ON invoice.id = trade.invoice_id
+			WHERE trade.issuer_id = $1 GROUP BY trade.invoice_id, invoice.face_value
+		) funded ON true
+		WHERE issuer.id = $1
+		GROUP BY issuer.id`, issuerID).
+		Scan(&summary.Name, &summary.Balance, &summary.FundedInvoices, &raised, &fees, &faceValue)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("issuer not found")
+		}
+		return nil, fmt.Errorf("failed to get issuer funding: %w", err)
+	}
+	summary.FinancingRaised = float32(roundCents(raised))
+	summary.FeesPaid = float32(roundCents(fees))
+	if faceValue > 0 {
+		summary.AverageDiscount = float32((faceValue - raised) / faceValue * 100)
+	}
+	return summary, nil
+}
+
+// GetInvoiceStatusTotals adds the number and amounts of the issuer's invoices in each status to the summary
+func GetInvoiceStatusTotals(ctx context.Context, db dbtx, summary *pb.IssuerSummary) (err error) {
+	ctx, span := startSpan(ctx, "GetInvoiceStatusTotals", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT status, COUNT(*), SUM(price), SUM(face_value),
+			COALESCE(SUM(face_value - repaid_amount) FILTER (WHERE status IN `+owedStatuses+`), 0)
+		FROM invoice WHERE issuer_id = $1 GROUP BY status ORDER BY status`, summary.GetIssuerId())
+	if err != nil {
+		return fmt.Errorf("failed to query invoice status totals: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var price, faceValue, outstanding float64
+		total := &pb.InvoiceStatusTotal{}
+		if err := rows.Scan(&total.Status, &total.Invoices, &price, &faceValue, &outstanding); err != nil {
+			return fmt.Errorf("failed to scan invoice status total: %w", err)
+		}
+		total.Price, total.FaceValue = float32(roundCents(price)), float32(roundCents(faceValue))
+		total.Outstanding = float32(roundCents(outstanding))
+		summary.ByStatus = append(summary.ByStatus, total)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read invoice status totals: %w", err)
+	}
+	return nil
+}
+
+// GetListedInvoices adds the open invoices of the issuer with their best pending bid to the summary
+func GetListedInvoices(ctx context.Context, db dbtx, summary *pb.IssuerSummary) (err error) {
+	ctx, span := startSpan(ctx, "GetListedInvoices", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT invoice.id, COALESCE(invoice.invoice_number, ''), invoice.price, invoice.face_value,
+			COALESCE(to_char(invoice.due_date, 'YYYY-MM-DD'), ''), bids.count, COALESCE(best.amount, 0), COALESCE(best.effective_yield, 0)
+		FROM invoice
+		CROSS JOIN LATERAL (SELECT COUNT(*) AS count FROM bid WHERE bid.invoice_id = invoice.id AND bid.status = 'pending') bids
+		LEFT JOIN LATERAL (
+			SELECT amount, effective_yield FROM bid WHERE bid.invoice_id = invoice.id AND bid.status = 'pending' `+bestBidOrder+` LIMIT 1
+		) best ON true
+		WHERE invoice.issuer_id = $1 AND invoice.status = 'open'
+		ORDER BY invoice.created_at, invoice.id`, summary.GetIssuerId())
+	if err != nil {
+		return fmt.Errorf("failed to query listed invoices: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		invoice := &pb.ListedInvoice{}
+		err := rows.Scan(&invoice.InvoiceId, &invoice.InvoiceNumber, &invoice.Price, &invoice.FaceValue, &invoice.DueDate,
+			&invoice.Bids, &invoice.BestBid, &invoice.BestBidYield)
+		if err != nil {
+			return fmt.Errorf("failed to scan listed invoice: %w", err)
+		}
+		summary.ListedInvoices = append(summary.ListedInvoices, invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read listed invoices: %w", err)
+	}
+	return nil
+}
+
+// GetRepaymentObligations adds the funded invoices the issuer still owes to the summary, the earliest due first
+func GetRepaymentObligations(ctx context.Context, db dbtx, summary *pb.IssuerSummary, today time.Time) (err error) {
+	ctx, span := startSpan(ctx, "GetRepaymentObligations", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT id, COALESCE(invoice_number, ''), status, COALESCE(to_char(due_date, 'YYYY-MM-DD'), ''),
+			face_value, repaid_amount
+		FROM invoice WHERE issuer_id = $1 AND status IN `+owedStatuses+`
+		ORDER BY due_date NULLS LAST, id`, summary.GetIssuerId())
+	if err != nil {
+		return fmt.Errorf("failed to query repayment obligations: %w", err)
+	}
+	defer rows.Close()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	var outstanding float64
+	for rows.Next() {
+		var faceValue, repaid float64
+		obligation := &pb.RepaymentObligation{}
+		if err := rows.Scan(&obligation.InvoiceId, &obligation.InvoiceNumber, &obligation.Status, &obligation.DueDate, &faceValue, &repaid); err != nil {
+			return fmt.Errorf("failed to scan repayment obligation: %w", err)
+		}
+		if due, err := time.Parse(dueDateLayout, obligation.GetDueDate()); err == nil {
+			obligation.DaysUntilDue = int32(actualDays(today, due))
+		}
+		obligation.FaceValue, obligation.RepaidAmount = float32(roundCents(faceValue)), float32(roundCents(repaid))
+		obligation.Outstanding = float32(roundCents(faceValue - repaid))
+		outstanding += faceValue - repaid
+		summary.RepaymentObligations = append(summary.RepaymentObligations, obligation)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read repayment obligations: %w", err)
+	}
+	summary.Outstanding = float32(roundCents(outstanding))
+	return nil
+}
@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetIssuerSummary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT COALESCE\\(issuer.name, ''\\), issuer.balance, COUNT\\(funded.invoice_id\\)").
+		WithArgs("issuer-id").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "balance", "funded", "raised", "fees", "face_value"}).
+			AddRow("Issuer Ltd", 2500.0, 2, 2850.0, 28.5, 3000.0))
+	mock.ExpectQuery("FROM invoice WHERE issuer_id = \\$1 GROUP BY status ORDER BY status").
+		WithArgs("issuer-id").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "invoices", "price", "face_value", "outstanding"}).
+			AddRow("closed", 1, 1800.0, 2000.0, 1500.0).
+			AddRow("open", 2, 450.0, 500.0, 0.0).
+			AddRow("repaid", 1, 900.0, 1000.0, 0.0))
+	mock.ExpectQuery("FROM invoice CROSS JOIN LATERAL (.+) ORDER BY effective_yield ASC NULLS LAST, amount DESC LIMIT 1 (.+) invoice.status = 'open'").
+		WithArgs("issuer-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "invoice_number", "price", "face_value", "due_date", "bids", "best_bid", "best_bid_yield"}).
+			AddRow("invoice-3", "INV-3", 250.0, 300.0, "2099-01-31", 2, 280.0, 7.5).
+			AddRow("invoice-4", "", 200.0, 200.0, "", 0, 0.0, 0.0))
+	mock.ExpectQuery("FROM invoice WHERE issuer_id = \\$1 AND status IN \\('closed', 'overdue', 'defaulted'\\)").
+		WithArgs("issuer-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "invoice_number", "status", "due_date", "face_value", "repaid_amount"}).
+			AddRow("invoice-1", "INV-1", "overdue", "2000-01-31", 2000.0, 500.0).
+			AddRow("invoice-2", "", "closed", "", 100.0, 0.0))
+	mock.ExpectCommit()
+
+	summary, err := s.GetIssuerSummary(context.Background(), &pb.Issuer{Id: "issuer-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Issuer Ltd", summary.Name)
+	assert.Equal(t, int32(2), summary.FundedInvoices)
+	assert.Equal(t, float32(2850), summary.FinancingRaised)
+	assert.Equal(t, float32(28.5), summary.FeesPaid)
+	assert.Equal(t, float32(5), summary.AverageDiscount)
+	assert.Len(t, summary.ByStatus, 3)
+	assert.True(t, proto.Equal(&pb.InvoiceStatusTotal{Status: "closed", Invoices: 1, Price: 1800, FaceValue: 2000, Outstanding: 1500}, summary.ByStatus[0]))
+	assert.True(t, proto.Equal(&pb.ListedInvoice{InvoiceId: "invoice-3", InvoiceNumber: "INV-3", Price: 250, FaceValue: 300, DueDate: "2099-01-31",
+		Bids: 2, BestBid: 280, BestBidYield: 7.5}, summary.ListedInvoices[0]))
+	assert.Equal(t, float32(1600), summary.Outstanding)
+	assert.Len(t, summary.RepaymentObligations, 2)
+	assert.Less(t, summary.RepaymentObligations[0].DaysUntilDue, int32(0))
+	assert.Equal(t, float32(1500), summary.RepaymentObligations[0].Outstanding)
+	// Invoices without a due date have no days until due
+	assert.Equal(t, int32(0), summary.RepaymentObligations[1].DaysUntilDue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetIssuerSummaryNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM issuer LEFT JOIN").WithArgs("issuer-id").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "balance", "funded", "raised", "fees", "face_value"}))
+	mock.ExpectRollback()
+
+	_, err = s.GetIssuerSummary(context.Background(), &pb.Issuer{Id: "issuer-id"})
+
+	assert.EqualError(t, err, "issuer not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRepaymentObligationsDaysUntilDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM invoice WHERE issuer_id = \\$1 AND status IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "invoice_number", "status", "due_date", "face_value", "repaid_amount"}).
+			AddRow("invoice-1", "", "overdue", "2024-03-10", 100.0, 0.0).
+			AddRow("invoice-2", "", "closed", "2024-04-19", 100.0, 0.0))
+
+	summary := &pb.IssuerSummary{IssuerId: "issuer-id"}
+	err = GetRepaymentObligations(context.Background(), db, summary, day("2024-03-20").Add(15*time.Hour))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(-10), summary.RepaymentObligations[0].DaysUntilDue)
+	assert.Equal(t, int32(30), summary.RepaymentObligations[1].DaysUntilDue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
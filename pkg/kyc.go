@@ -0,0 +1,287 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"google.golang.org/grpc/status"
+)
+
+// Onboarding states of issuers and investors, only verified parties can trade
+const (
+	KycPending   = "pending"
+	KycVerified  = "verified"
+	KycRejected  = "rejected"
+	KycSuspended = "suspended"
+)
+
+// Parties going through onboarding, also the names of their tables
+const (
+	PartyIssuer   = "issuer"
+	PartyInvestor = "investor"
+)
+
+// States of a checklist document
+const (
+	DocumentMissing   = "missing"
+	DocumentSubmitted = "submitted"
+	DocumentAccepted  = "accepted"
+	DocumentRejected  = "rejected"
+)
+
+// kycChecklist lists the documents a party has to provide before it can be verified
+var kycChecklist = map[string][]string{
+	PartyIssuer:   {"certificate_of_incorporation", "proof_of_address", "beneficial_owners"},
+	PartyInvestor: {"identity_document", "proof_of_address", "source_of_funds"},
+}
+
+// kycTransitions lists the states each state can move to
+var kycTransitions = map[string][]string{
+	KycPending:   {KycVerified, KycRejected},
+	KycVerified:  {KycSuspended},
+	KycSuspended: {KycVerified, KycRejected},
+	KycRejected:  {KycPending},
+}
+
+var (
+	// ErrKycNotVerified is wrapped by every KycError
+	ErrKycNotVerified = errors.New("party is not verified")
+	// ErrKycTransition is returned for status changes the onboarding flow doesn't allow
+	ErrKycTransition = errors.New("kyc status change not allowed")
+	// ErrKycStatusChanged is returned when the status of the party changed during a review
+	ErrKycStatusChanged = errors.New("kyc status changed concurrently")
+)
+
+// KycError is returned when a party that isn't verified tries to trade. gRPC reports it as FailedPrecondition.
+type KycError struct {
+	PartyType string
+	PartyID   string
+	Status    string
+}
+
+func (e *KycError) Error() string {
+	return fmt.Sprintf("%v: %s %s is %s, only verified parties can trade", ErrKycNotVerified, e.PartyType, e.PartyID, e.Status)
+}
+
+func (e *KycError) Unwrap() error {
+	return ErrKycNotVerified
+}
+
+// GRPCStatus is used by grpc to turn the error into a status
+func (e *KycError) GRPCStatus() *status.Status {
+	return preconditionStatus(e, "KYC", e.PartyType+":"+e.PartyID, e.Status)
+}
+
+// KycProvider checks the documents of a party with an identity verification service
+type KycProvider interface {
+	// Name is recorded as the reviewer of the status changes decided by the provider
+	Name() string
+	// Review sets the status, accepted or rejected, and the reason of every document
+	Review(ctx context.Context, party *pb.KycParty, documents []*pb.KycDocument) error
+}
+
+// FakeKycProvider accepts every document, except those whose reference contains "reject". It stands in for a real
+// provider in development and tests.
+type FakeKycProvider struct{}
+
+func (FakeKycProvider) Name() string {
+	return "provider:fake"
+}
+
+func (FakeKycProvider) Review(ctx context.Context, party *pb.KycParty, documents []*pb.KycDocument) error {
+	for _, document := range documents {
+		if strings.Contains(document.GetReference(), "reject") {
+			document.Status = DocumentRejected
+			document.Reason = "document could not be verified"
+			continue
+		}
+		document.Status = DocumentAccepted
+		document.Reason = ""
+	}
+	return nil
+}
+
+// NewKycProvider returns the provider configured by name, the fake one by default
+func NewKycProvider(name string) (KycProvider, error) {
+	switch name {
+	case "", "fake":
+		return FakeKycProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown kyc provider %q", name)
+	}
+}
+
+// KycReviewer returns the reviewer recorded for a manual status change by the admin principal: admin:<party id>, or
+// admin when the token doesn't name the operator
+func KycReviewer(principal *Principal) string {
+	if principal == nil || principal.PartyID == "" {
+		return RoleAdmin
+	}
+	return RoleAdmin + ":" + principal.PartyID
+}
+
+// ValidateKycParty checks the party of a request before anything is read from the database
+func ValidateKycParty(partyType, partyID string) error {
+	if _, ok := kycChecklist[partyType]; !ok {
+		return fmt.Errorf("party type %q must be issuer or investor", partyType)
+	}
+	if partyID == "" {
+		return errors.New("party id is required")
+	}
+	return nil
+}
+
+// ValidateKycDocument checks that the document is on the checklist of its party
+func ValidateKycDocument(in *pb.KycDocument) error {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return err
+	}
+	if in.GetReference() == "" {
+		return errors.New("document reference is required")
+	}
+	for _, documentType := range kycChecklist[in.GetPartyType()] {
+		if documentType == in.GetDocumentType() {
+			return nil
+		}
+	}
+	return fmt.Errorf("document type %q must be one of %s", in.GetDocumentType(), strings.Join(kycChecklist[in.GetPartyType()], ", "))
+}
+
+// canTransition tells whether a party can move from one onboarding state to the other
+func canTransition(from, to string) bool {
+	for _, next := range kycTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// buildChecklist returns the latest document of every checklist entry, missing ones included, and whether
+// they were all accepted
+func buildChecklist(partyType string, documents []*pb.KycDocument) (checklist []*pb.KycDocument, complete bool) {
+	byType := map[string]*pb.KycDocument{}
+	for _, document := range documents {
+		byType[document.GetDocumentType()] = document
+	}
+	complete = true
+	for _, documentType := range kycChecklist[partyType] {
+		document, ok := byType[documentType]
+		if !ok {
+			document = &pb.KycDocument{DocumentType: documentType, Status: DocumentMissing}
+		}
+		if document.GetStatus() != DocumentAccepted {
+			complete = false
+		}
+		checklist = append(checklist, document)
+	}
+	return checklist, complete
+}
+
+// CheckKycVerified fails with a KycError unless the party is verified
+func CheckKycVerified(ctx context.Context, db dbtx, partyType, partyID string) (err error) {
+	ctx, span := startSpan(ctx, "CheckKycVerified", nil)
+	defer func() { endSpan(span, err) }()
+	kycStatus, err := GetPartyKycStatus(ctx, db, partyType, partyID)
+	if err != nil {
+		return err
+	}
+	if kycStatus != KycVerified {
+		return &KycError{PartyType: partyType, PartyID: partyID, Status: kycStatus}
+	}
+	return nil
+}
+
+// GetPartyKycStatus returns the onboarding state of the issuer or investor
+func GetPartyKycStatus(ctx context.Context, db dbtx, partyType, partyID string) (kycStatus string, err error) {
+	ctx, span := startSpan(ctx, "GetPartyKycStatus", nil)
+	defer func() { endSpan(span, err) }()
+	// partyType is one of the party constants, which are also table names
+	err = db.QueryRowContext(ctx, fmt.Sprintf("SELECT kyc_status FROM %s WHERE id = $1", partyType), partyID).Scan(&kycStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%s not found: %w", partyType, err)
+		}
+		return "", fmt.Errorf("failed to get %s's kyc status: %w", partyType, err)
+	}
+	return kycStatus, nil
+}
+
+// ListKycDocuments returns the latest document of every type submitted by the party
+func ListKycDocuments(ctx context.Context, db dbtx, partyType, partyID string) (documents []*pb.KycDocument, err error) {
+	ctx, span := startSpan(ctx, "ListKycDocuments", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT DISTINCT ON (document_type) id, document_type, reference, status, reason FROM kyc_document
+		WHERE party_type = $1 AND party_id = $2 ORDER BY document_type, created_at DESC`, partyType, partyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kyc documents: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		document := &pb.KycDocument{PartyType: partyType, PartyId: partyID}
+		if err := rows.Scan(&document.Id, &document.DocumentType, &document.Reference, &document.Status, &document.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan kyc document: %w", err)
+		}
+		documents = append(documents, document)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read kyc documents: %w", err)
+	}
+	return documents, nil
+}
+
+// InsertKycDocument records a submitted document, replacing earlier ones of the same type in the checklist
+func InsertKycDocument(ctx context.Context, db dbtx, in *pb.KycDocument) (err error) {
+	ctx, span := startSpan(ctx, "InsertKycDocument", nil)
+	defer func() { endSpan(span, err) }()
+	in.Status = DocumentSubmitted
+	err = db.QueryRowContext(ctx, `INSERT INTO kyc_document (party_type, party_id, document_type, reference, status)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		in.GetPartyType(), in.GetPartyId(), in.GetDocumentType(), in.GetReference(), in.GetStatus()).Scan(&in.Id)
+	if err != nil {
+		return fmt.Errorf("failed to insert kyc document: %w", err)
+	}
+	return nil
+}
+
+// UpdateKycDocument stores the status and reason the provider gave the document
+func UpdateKycDocument(ctx context.Context, db dbtx, in *pb.KycDocument) (err error) {
+	ctx, span := startSpan(ctx, "UpdateKycDocument", nil)
+	defer func() { endSpan(span, err) }()
+	_, err = db.ExecContext(ctx, "UPDATE kyc_document SET status = $1, reason = $2 WHERE id = $3", in.GetStatus(), in.GetReason(), in.GetId())
+	if err != nil {
+		return fmt.Errorf("failed to update kyc document: %w", err)
+	}
+	return nil
+}
+
+// SetKycStatus moves the party from one onboarding state to another and records who did it and why.
+// It fails with ErrKycStatusChanged when the party is no longer in the from state.
+func SetKycStatus(ctx context.Context, db dbtx, in *pb.KycReview, from string) (err error) {
+	ctx, span := startSpan(ctx, "SetKycStatus", nil)
+	defer func() { endSpan(span, err) }()
+	res, err := db.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET kyc_status = $1 WHERE id = $2 AND kyc_status = $3", in.GetPartyType()),
+		in.GetStatus(), in.GetPartyId(), from)
+	if err != nil {
+		return fmt.Errorf("failed to update kyc status: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update kyc status: %w", err)
+	}
+	if n == 0 {
+		return ErrKycStatusChanged
+	}
+	_, err = db.ExecContext(ctx, `INSERT INTO kyc_event (party_type, party_id, from_status, to_status, reviewer, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		in.GetPartyType(), in.GetPartyId(), from, in.GetStatus(), in.GetReviewer(), in.GetReason())
+	if err != nil {
+		return fmt.Errorf("failed to record kyc event: %w", err)
+	}
+	return nil
+}
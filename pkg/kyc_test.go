@@ -0,0 +1,204 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func expectKycStatus(mock sqlmock.Sqlmock, partyType, partyID, kycStatus string) {
+	mock.ExpectQuery("SELECT kyc_status FROM " + partyType + " WHERE id = \\$1").WithArgs(partyID).
+		WillReturnRows(sqlmock.NewRows([]string{"kyc_status"}).AddRow(kycStatus))
+}
+
+func expectKycDocuments(mock sqlmock.Sqlmock, partyType, partyID string, documents ...*pb.KycDocument) {
+	rows := sqlmock.NewRows([]string{"id", "document_type", "reference", "status", "reason"})
+	for _, document := range documents {
+		rows.AddRow(document.Id, document.DocumentType, document.Reference, document.Status, document.Reason)
+	}
+	mock.ExpectQuery("SELECT DISTINCT ON \\(document_type\\) (.+) FROM kyc_document").WithArgs(partyType, partyID).WillReturnRows(rows)
+}
+
+func TestPlaceBidRequiresVerifiedInvestor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{faceValue: 1000, price: 1000})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycSuspended)
+	mock.ExpectRollback()
+
+	bid, err := s.PlaceBid(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 100})
+
+	assert.Nil(t, bid)
+	assert.ErrorIs(t, err, ErrKycNotVerified)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), "investor investor-id is suspended")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateInvoiceRequiresVerifiedIssuer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycPending)
+
+	invoice, err := s.CreateInvoice(context.Background(), &pb.Invoice{IssuerId: "issuer-id", Status: "open", Price: 10})
+
+	assert.Nil(t, invoice)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmitKycDocument(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycPending)
+	mock.ExpectQuery("INSERT INTO kyc_document").
+		WithArgs(PartyIssuer, "issuer-id", "proof_of_address", "s3://kyc/bill.pdf", DocumentSubmitted).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("document-id"))
+
+	document, err := s.SubmitKycDocument(asParty(PartyIssuer, "issuer-id"), &pb.KycDocument{
+		PartyType: PartyIssuer, PartyId: "issuer-id", DocumentType: "proof_of_address", Reference: "s3://kyc/bill.pdf",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "document-id", document.Id)
+	assert.Equal(t, DocumentSubmitted, document.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Only the party submits its documents, nothing reaches the database otherwise
+	_, err = s.SubmitKycDocument(context.Background(), &pb.KycDocument{
+		PartyType: PartyIssuer, PartyId: "issuer-id", DocumentType: "proof_of_address", Reference: "s3://kyc/bill.pdf",
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.SubmitKycDocument(asParty(PartyIssuer, "other-issuer-id"), &pb.KycDocument{
+		PartyType: PartyIssuer, PartyId: "issuer-id", DocumentType: "proof_of_address", Reference: "s3://kyc/bill.pdf",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.SubmitKycDocument(asParty(PartyIssuer, "issuer-id"), &pb.KycDocument{
+		PartyType: PartyIssuer, PartyId: "issuer-id", DocumentType: "selfie", Reference: "s3://kyc/selfie.jpg",
+	})
+	assert.ErrorContains(t, err, `document type "selfie" must be one of certificate_of_incorporation, proof_of_address, beneficial_owners`)
+}
+
+func TestVerifyKycRejectsDocuments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, kyc: FakeKycProvider{}}
+
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycPending)
+	expectKycDocuments(mock, PartyInvestor, "investor-id",
+		&pb.KycDocument{Id: "1", DocumentType: "identity_document", Reference: "passport.pdf", Status: DocumentSubmitted},
+		&pb.KycDocument{Id: "2", DocumentType: "proof_of_address", Reference: "reject-bill.pdf", Status: DocumentSubmitted},
+		&pb.KycDocument{Id: "3", DocumentType: "source_of_funds", Reference: "payslip.pdf", Status: DocumentSubmitted})
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE kyc_document SET status = \\$1, reason = \\$2 WHERE id = \\$3").
+		WithArgs(DocumentAccepted, "", "1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE kyc_document SET status = \\$1, reason = \\$2 WHERE id = \\$3").
+		WithArgs(DocumentRejected, "document could not be verified", "2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE kyc_document SET status = \\$1, reason = \\$2 WHERE id = \\$3").
+		WithArgs(DocumentAccepted, "", "3").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE investor SET kyc_status = \\$1 WHERE id = \\$2 AND kyc_status = \\$3").
+		WithArgs(KycRejected, "investor-id", KycPending).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO kyc_event").
+		WithArgs(PartyInvestor, "investor-id", KycPending, KycRejected, "provider:fake", "rejected documents: proof_of_address: document could not be verified").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	kycStatus, err := s.VerifyKyc(context.Background(), &pb.KycParty{PartyType: PartyInvestor, PartyId: "investor-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, KycRejected, kycStatus.Status)
+	assert.Equal(t, DocumentRejected, kycStatus.Checklist[1].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerifyKycMissingDocuments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, kyc: FakeKycProvider{}}
+
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycPending)
+	expectKycDocuments(mock, PartyIssuer, "issuer-id",
+		&pb.KycDocument{Id: "1", DocumentType: "proof_of_address", Reference: "bill.pdf", Status: DocumentSubmitted})
+
+	_, err = s.VerifyKyc(context.Background(), &pb.KycParty{PartyType: PartyIssuer, PartyId: "issuer-id"})
+
+	assert.EqualError(t, err, "missing kyc documents: certificate_of_incorporation, beneficial_owners")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewKycSuspend(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	expectKycDocuments(mock, PartyIssuer, "issuer-id")
+	mock.ExpectExec("UPDATE issuer SET kyc_status = \\$1 WHERE id = \\$2 AND kyc_status = \\$3").
+		WithArgs(KycSuspended, "issuer-id", KycVerified).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO kyc_event").
+		WithArgs(PartyIssuer, "issuer-id", KycVerified, KycSuspended, "admin:compliance@example.com", "sanctions screening hit").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// The reviewer is the admin of the bearer token, not the one in the request
+	kycStatus, err := s.ReviewKyc(asParty(RoleAdmin, "compliance@example.com"), &pb.KycReview{
+		PartyType: PartyIssuer, PartyId: "issuer-id", Status: KycSuspended, Reviewer: "someone-else", Reason: "sanctions screening hit",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, KycSuspended, kycStatus.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewKycRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		status  string
+		err     string
+	}{
+		{"not a transition", KycPending, KycSuspended, "kyc status change not allowed: pending to \"suspended\""},
+		{"verify without accepted documents", KycPending, KycVerified, "every checklist document must be accepted first"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			s := &server{db: db}
+
+			mock.ExpectBegin()
+			expectKycStatus(mock, PartyInvestor, "investor-id", tt.current)
+			expectKycDocuments(mock, PartyInvestor, "investor-id")
+			mock.ExpectRollback()
+
+			_, err = s.ReviewKyc(asParty(RoleAdmin, ""), &pb.KycReview{
+				PartyType: PartyInvestor, PartyId: "investor-id", Status: tt.status, Reason: "reason",
+			})
+
+			assert.ErrorIs(t, err, ErrKycTransition)
+			assert.ErrorContains(t, err, tt.err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
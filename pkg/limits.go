@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"google.golang.org/grpc/status"
+)
+
+// Limits of an investor tier, used as the violation type of a rejected bid
+const (
+	LimitIssuerConcentration = "ISSUER_CONCENTRATION"
+	LimitInvoiceAmount       = "INVOICE_AMOUNT"
+)
+
+// ErrExposureLimitExceeded is wrapped by every LimitError
+var ErrExposureLimitExceeded = errors.New("investor exposure limit exceeded")
+
+// LimitError is returned for bids that break a limit of the investor's tier. gRPC reports it as
+// FailedPrecondition with the violated limit in a PreconditionFailure detail.
+type LimitError struct {
+	Limit   string
+	Subject string
+	Reason  string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrExposureLimitExceeded, e.Reason)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrExposureLimitExceeded
+}
+
+// GRPCStatus is used by grpc to turn the error into a status
+func (e *LimitError) GRPCStatus() *status.Status {
+	return preconditionStatus(e, e.Limit, e.Subject, e.Reason)
+}
+
+// investorExposure is what the limits of a bid are checked against. The portfolio is the investor's cash,
+// pending bids and funded positions, the issuer exposure leaves out the bids on the invoice being bid on
+// since the new bid replaces them.
+type investorExposure struct {
+	tier           *pb.InvestorTier
+	issuerID       string
+	portfolio      float64
+	issuerExposure float64
+}
+
+// ValidateInvestorTier checks a tier before it is stored
+func ValidateInvestorTier(in *pb.InvestorTier) error {
+	if in.GetName() == "" {
+		return errors.New("tier name is required")
+	}
+	if in.GetMaxIssuerConcentration() < 0 || in.GetMaxIssuerConcentration() > 100 {
+		return errors.New("max issuer concentration must be between 0 and 100")
+	}
+	if in.GetMaxInvoiceAmount() < 0 {
+		return errors.New("max invoice amount must not be negative")
+	}
+	return nil
+}
+
+// CheckInvestorLimits fails with a LimitError when the bid breaks the per invoice cap or the issuer
+// concentration limit of the investor's tier
+func CheckInvestorLimits(ctx context.Context, db dbtx, in *pb.Bid) (err error) {
+	ctx, span := startSpan(ctx, "CheckInvestorLimits", in)
+	defer func() { endSpan(span, err) }()
+
+	exposure, err := GetInvestorExposure(ctx, db, in)
+	if err != nil {
+		return err
+	}
+	tier := exposure.tier
+	amount := float64(in.GetAmount())
+
+	if limit := float64(tier.GetMaxInvoiceAmount()); limit > 0 && amount > limit {
+		bidsRejectedByLimit.WithLabelValues(LimitInvoiceAmount).Inc()
+		return &LimitError{
+			Limit:   LimitInvoiceAmount,
+			Subject: "invoice:" + in.GetInvoiceId(),
+			Reason:  fmt.Sprintf("bid of %.2f is above the %.2f per invoice cap of tier %s", amount, limit, tier.GetName()),
+		}
+	}
+
+	if limit := float64(tier.GetMaxIssuerConcentration()); limit > 0 && exposure.portfolio > 0 {
+		share := (exposure.issuerExposure + amount) / exposure.portfolio * 100
+		if share > limit {
+			bidsRejectedByLimit.WithLabelValues(LimitIssuerConcentration).Inc()
+			return &LimitError{
+				Limit:   LimitIssuerConcentration,
+				Subject: "issuer:" + exposure.issuerID,
+				Reason: fmt.Sprintf("issuer %s would be %.1f%% of the portfolio, tier %s allows %.1f%%",
+					exposure.issuerID, share, tier.GetName(), limit),
+			}
+		}
+	}
+	return nil
+}
+
+// GetInvestorExposure returns the tier of the investor with its portfolio and exposure to the issuer of the invoice
+func GetInvestorExposure(ctx context.Context, db dbtx, in *pb.Bid) (exposure investorExposure, err error) {
+	ctx, span := startSpan(ctx, "GetInvestorExposure", in)
+	defer func() { endSpan(span, err) }()
+	exposure.tier = &pb.InvestorTier{}
+	err = db.QueryRowContext(ctx, `SELECT investor_tier.name, investor_tier.max_issuer_concentration, investor_tier.max_invoice_amount, target.issuer_id,
+		investor.balance
+			+ COALESCE((SELECT SUM(bid.amount) FROM bid WHERE bid.investor_id = investor.id AND bid.status = 'pending'), 0)
+			+ COALESCE((SELECT SUM(trade.amount) FROM trade JOIN invoice ON invoice.id = trade.invoice_id
+				WHERE trade.investor_id = investor.id AND invoice.status IN ('closed', 'overdue', 'defaulted')), 0),
+		COALESCE((SELECT SUM(bid.amount) FROM bid JOIN invoice ON invoice.id = bid.invoice_id
+				WHERE bid.investor_id = investor.id AND bid.status = 'pending' AND bid.invoice_id <> target.id AND invoice.issuer_id = target.issuer_id), 0)
+			+ COALESCE((SELECT SUM(trade.amount) FROM trade JOIN invoice ON invoice.id = trade.invoice_id
+				WHERE trade.investor_id = investor.id AND trade.issuer_id = target.issuer_id AND invoice.status IN ('closed', 'overdue', 'defaulted')), 0)
+		FROM investor
+		JOIN investor_tier ON investor_tier.name = investor.tier
+		CROSS JOIN (SELECT id, issuer_id FROM invoice WHERE id = $2) target
+		WHERE investor.id = $1`, in.GetInvestorId(), in.GetInvoiceId()).
+		Scan(&exposure.tier.Name, &exposure.tier.MaxIssuerConcentration, &exposure.tier.MaxInvoiceAmount, &exposure.issuerID,
+			&exposure.portfolio, &exposure.issuerExposure)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return exposure, fmt.Errorf("investor or invoice not found: %w", err)
+		}
+		return exposure, fmt.Errorf("failed to get investor exposure: %w", err)
+	}
+	return exposure, nil
+}
+
+// UpsertInvestorTier creates the tier or replaces its limits
+func UpsertInvestorTier(ctx context.Context, db dbtx, in *pb.InvestorTier) (err error) {
+	ctx, span := startSpan(ctx, "UpsertInvestorTier", nil)
+	defer func() { endSpan(span, err) }()
+	_, err = db.ExecContext(ctx, `INSERT INTO investor_tier (name, max_issuer_concentration, max_invoice_amount) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET max_issuer_concentration = EXCLUDED.max_issuer_concentration, max_invoice_amount = EXCLUDED.max_invoice_amount`,
+		in.GetName(), in.GetMaxIssuerConcentration(), in.GetMaxInvoiceAmount())
+	if err != nil {
+		return fmt.Errorf("failed to save investor tier: %w", err)
+	}
+	return nil
+}
+
+// AssignInvestorTier moves the investor to an existing tier and returns the updated investor
+func AssignInvestorTier(ctx context.Context, db dbtx, in *pb.Investor) (investor *pb.Investor, err error) {
+	ctx, span := startSpan(ctx, "AssignInvestorTier", nil)
+	defer func() { endSpan(span, err) }()
+	investor = &pb.Investor{}
+	err = db.QueryRowContext(ctx, `UPDATE investor SET tier = investor_tier.name FROM investor_tier
+		WHERE investor.id = $1 AND investor_tier.name = $2
+		RETURNING investor.id, investor.name, investor.balance, investor.tier`, in.GetId(), in.GetTier()).
+		Scan(&investor.Id, &investor.Name, &investor.Balance, &investor.Tier)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("investor %q or tier %q not found", in.GetId(), in.GetTier())
+		}
+		return nil, fmt.Errorf("failed to assign investor tier: %w", err)
+	}
+	return investor, nil
+}
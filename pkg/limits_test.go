@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func expectInvestorExposure(mock sqlmock.Sqlmock, tier *pb.InvestorTier, portfolio, issuerExposure float64) {
+	mock.ExpectQuery("SELECT investor_tier.name, (.|\\n)+ WHERE investor.id = \\$1").WithArgs("investor-id", "invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "max_issuer_concentration", "max_invoice_amount", "issuer_id", "portfolio", "issuer_exposure"}).
+			AddRow(tier.GetName(), tier.GetMaxIssuerConcentration(), tier.GetMaxInvoiceAmount(), "issuer-id", portfolio, issuerExposure))
+}
+
+func TestCheckInvestorLimits(t *testing.T) {
+	tests := []struct {
+		name           string
+		tier           *pb.InvestorTier
+		issuerExposure float64
+		amount         float32
+		limit          string
+	}{
+		{"no limits", &pb.InvestorTier{Name: "standard"}, 900, 100, ""},
+		{"within invoice cap", &pb.InvestorTier{Name: "retail", MaxInvoiceAmount: 100}, 0, 100, ""},
+		{"above invoice cap", &pb.InvestorTier{Name: "retail", MaxInvoiceAmount: 100}, 0, 150, LimitInvoiceAmount},
+		{"within concentration", &pb.InvestorTier{Name: "retail", MaxIssuerConcentration: 25}, 150, 100, ""},
+		{"above concentration", &pb.InvestorTier{Name: "retail", MaxIssuerConcentration: 25}, 200, 100, LimitIssuerConcentration},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			expectInvestorExposure(mock, tt.tier, 1000, tt.issuerExposure)
+
+			err = CheckInvestorLimits(context.Background(), db, &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: tt.amount})
+
+			if tt.limit == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var limitErr *LimitError
+			assert.True(t, errors.As(err, &limitErr))
+			assert.Equal(t, tt.limit, limitErr.Limit)
+			assert.ErrorIs(t, err, ErrExposureLimitExceeded)
+		})
+	}
+}
+
+func TestLimitErrorStatus(t *testing.T) {
+	err := &LimitError{Limit: LimitIssuerConcentration, Subject: "issuer:issuer-id", Reason: "issuer issuer-id would be 30.0% of the portfolio"}
+
+	st := status.Convert(err)
+
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Contains(t, st.Message(), "would be 30.0% of the portfolio")
+	assert.Len(t, st.Details(), 1)
+	failure := st.Details()[0].(*errdetails.PreconditionFailure)
+	assert.Equal(t, LimitIssuerConcentration, failure.Violations[0].Type)
+	assert.Equal(t, "issuer:issuer-id", failure.Violations[0].Subject)
+}
+
+func TestPlaceBidOverInvoiceCap(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, dayCount: DayCountACT360}
+
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{faceValue: 1000, price: 1000})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycVerified)
+	mock.ExpectQuery("SELECT amount, effective_yield FROM bid").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"amount", "effective_yield"}))
+	mock.ExpectQuery("SELECT price FROM invoice WHERE id = \\$1").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(1000.0))
+	mock.ExpectQuery("SELECT balance FROM investor WHERE id = \\$1 FOR UPDATE").WithArgs("investor-id").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(5000.0))
+	expectInvestorExposure(mock, &pb.InvestorTier{Name: "retail", MaxInvoiceAmount: 500}, 5000, 0)
+	mock.ExpectRollback()
+
+	bid, err := s.PlaceBid(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 900})
+
+	// Nothing is reserved for a rejected bid
+	assert.Nil(t, bid)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), "above the 500.00 per invoice cap of tier retail")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetInvestorTier(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectExec("INSERT INTO investor_tier \\(name, max_issuer_concentration, max_invoice_amount\\)").
+		WithArgs("retail", float32(25), float32(500)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tier, err := s.SetInvestorTier(context.Background(), &pb.InvestorTier{Name: "retail", MaxIssuerConcentration: 25, MaxInvoiceAmount: 500})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "retail", tier.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = s.SetInvestorTier(context.Background(), &pb.InvestorTier{Name: "retail", MaxIssuerConcentration: 120})
+	assert.EqualError(t, err, "max issuer concentration must be between 0 and 100")
+}
+
+func TestAssignInvestorTierNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectQuery("UPDATE investor SET tier = investor_tier.name FROM investor_tier").WithArgs("investor-id", "gold").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "tier"}))
+
+	investor, err := s.AssignInvestorTier(context.Background(), &pb.Investor{Id: "investor-id", Tier: "gold"})
+
+	assert.Nil(t, investor)
+	assert.EqualError(t, err, `investor "investor-id" or tier "gold" not found`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
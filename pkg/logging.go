@@ -0,0 +1,18 @@
+package pkg
+
+import (
+	"log/slog"
+	"os"
+)
+
+// SetupLogging routes the standard logger through slog and drops records below level.
+// Failures are logged with slog.Error and recovered problems with slog.Warn, plain log.Printf calls are logged at
+// info level and the steps of a request with slog.Debug.
+func SetupLogging(level string) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		slog.Warn("Unknown log level, using info", "level", level)
+		l = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: l})))
+}
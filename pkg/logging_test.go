@@ -0,0 +1,26 @@
+package pkg
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupLogging(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	ctx := context.Background()
+
+	SetupLogging("warn")
+	assert.True(t, slog.Default().Enabled(ctx, slog.LevelError))
+	assert.True(t, slog.Default().Enabled(ctx, slog.LevelWarn))
+	assert.False(t, slog.Default().Enabled(ctx, slog.LevelInfo))
+
+	SetupLogging("debug")
+	assert.True(t, slog.Default().Enabled(ctx, slog.LevelDebug))
+
+	SetupLogging("verbose")
+	assert.True(t, slog.Default().Enabled(ctx, slog.LevelInfo))
+	assert.False(t, slog.Default().Enabled(ctx, slog.LevelDebug))
+}
@@ -0,0 +1,277 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC server metrics, labelled the same way as go-grpc-prometheus so existing dashboards work
+var (
+	rpcStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_started_total",
+		Help: "Total number of RPCs started on the server.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+
+	rpcHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Histogram of response latency (seconds) of gRPC that had been application-level handled by the server.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+)
+
+// Marketplace metrics
+var (
+	bidsPlaced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "bids_placed_total",
+		Help:      "Total number of bids placed, by resulting bid status.",
+	}, []string{"status"})
+
+	tradesApproved = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "trades_approved_total",
+		Help:      "Total number of approved trades.",
+	})
+
+	invoiceVolumeFunded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "funded_volume_total",
+		Help:      "Total amount paid out to issuers for funded invoices.",
+	})
+
+	platformFees = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "platform_fees_total",
+		Help:      "Total fees credited to the platform revenue account.",
+	})
+
+	repaidVolume = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "repaid_volume_total",
+		Help:      "Total amount repaid on funded invoices and distributed to investors.",
+	})
+
+	bidsRejectedByLimit = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "bids_rejected_by_limit_total",
+		Help:      "Total number of bids rejected by an investor exposure limit, by limit.",
+	}, []string{"limit"})
+
+	bidsRejectedByRule = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "bids_rejected_by_rule_total",
+		Help:      "Total number of bids rejected by an auction rule of the invoice, by rule.",
+	}, []string{"rule"})
+
+	auctionsExtended = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "auctions_extended_total",
+		Help:      "Total number of timed auctions extended by a bid in their final seconds.",
+	})
+
+	bidsCancelled = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "bids_cancelled_total",
+		Help:      "Total number of bids withdrawn by their investor and refunded.",
+	})
+
+	autoBidsExecuted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "auto_bids_total",
+		Help:      "Total number of auto-bid rule executions, by outcome.",
+	}, []string{"outcome"})
+
+	autoBidTriggersDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "auto_bid_triggers_dropped_total",
+		Help:      "Total number of auto-bid triggers dropped because the auto-bidder's queue was full, by trigger.",
+	}, []string{"trigger"})
+
+	webhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "webhook_deliveries_total",
+		Help:      "Total number of webhook delivery attempts, by outcome: delivered, retried or dead.",
+	}, []string{"outcome"})
+
+	invoicesOverdue = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "overdue_total",
+		Help:      "Total number of invoices flagged overdue.",
+	})
+
+	invoicesDefaulted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "invoice",
+		Name:      "defaulted_total",
+		Help:      "Total number of invoices flagged defaulted.",
+	})
+
+	auctionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "invoice",
+		Name:      "auction_duration_seconds",
+		Help:      "Time between an invoice being listed and its trade being approved.",
+		// 1 minute up to roughly 45 days
+		Buckets: prometheus.ExponentialBuckets(60, 3, 10),
+	})
+)
+
+// Reconciliation metrics, set by the scheduled reconciler
+var (
+	reconciliationDiscrepancies = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "invoice",
+		Name:      "reconciliation_discrepancies",
+		Help:      "Number of discrepancies found by the last reconciliation run, by check.",
+	}, []string{"check"})
+
+	reconciliationLastRun = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "invoice",
+		Name:      "reconciliation_last_run_timestamp_seconds",
+		Help:      "Unix time of the last completed reconciliation run.",
+	})
+)
+
+// recordReconciliation exports the result of a reconciliation run
+func recordReconciliation(report *ReconciliationReport) {
+	// Every check is reported, so a fixed discrepancy goes back to zero
+	counts := map[string]float64{CheckBalanceConservation: 0, CheckNegativeBalance: 0, CheckWinningBids: 0}
+	for _, d := range report.Discrepancies {
+		counts[d.Check]++
+	}
+	for check, n := range counts {
+		reconciliationDiscrepancies.WithLabelValues(check).Set(n)
+	}
+	reconciliationLastRun.Set(float64(report.FinishedAt.Unix()))
+}
+
+// escrowCollector reports the balance currently held in pending bids. It is
+// read from the database on every scrape so it can't drift from the real value.
+type escrowCollector struct {
+	db   *sql.DB
+	desc *prometheus.Desc
+}
+
+func newEscrowCollector(db *sql.DB) *escrowCollector {
+	return &escrowCollector{
+		db: db,
+		desc: prometheus.NewDesc(
+			"invoice_escrow_outstanding_balance",
+			"Sum of investor funds currently reserved by pending bids.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *escrowCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *escrowCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	balance, err := GetOutstandingEscrow(ctx, c.db)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, balance)
+}
+
+// SetupMetrics creates the registry served on /metrics with the gRPC, database pool and marketplace metrics
+func SetupMetrics(db *sql.DB) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "invoice"),
+		newEscrowCollector(db),
+		rpcStarted,
+		rpcHandled,
+		rpcDuration,
+		bidsPlaced,
+		tradesApproved,
+		invoiceVolumeFunded,
+		platformFees,
+		repaidVolume,
+		invoicesOverdue,
+		invoicesDefaulted,
+		bidsRejectedByLimit,
+		bidsRejectedByRule,
+		auctionsExtended,
+		bidsCancelled,
+		autoBidsExecuted,
+		autoBidTriggersDropped,
+		webhookDeliveries,
+		auctionDuration,
+		reconciliationDiscrepancies,
+		reconciliationLastRun,
+	)
+	return registry
+}
+
+// MetricsHandler returns the HTTP handler exposing the registry on /metrics
+func MetricsHandler(registry *prometheus.Registry) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// metricsUnaryInterceptor records count, result code and latency of every unary RPC
+func metricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	service, method := splitMethodName(info.FullMethod)
+	rpcStarted.WithLabelValues("unary", service, method).Inc()
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	rpcHandled.WithLabelValues("unary", service, method, status.Code(err).String()).Inc()
+	rpcDuration.WithLabelValues("unary", service, method).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// metricsStreamInterceptor records count, result code and latency of every streaming RPC
+func metricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	service, method := splitMethodName(info.FullMethod)
+	rpcType := streamRPCType(info)
+	rpcStarted.WithLabelValues(rpcType, service, method).Inc()
+
+	start := time.Now()
+	err := handler(srv, ss)
+
+	rpcHandled.WithLabelValues(rpcType, service, method, status.Code(err).String()).Inc()
+	rpcDuration.WithLabelValues(rpcType, service, method).Observe(time.Since(start).Seconds())
+	return err
+}
+
+func streamRPCType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+// splitMethodName turns "/invoice.InvoiceService/PlaceBid" into ("invoice.InvoiceService", "PlaceBid")
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	// Setup
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	s := &server{db: db, risk: DefaultRiskModel()}
+	registry := SetupMetrics(db)
+
+	// Mock database
+	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).AddRow("1", "Issuer Name", 100.0)
+	mock.ExpectQuery("SELECT id, name, balance FROM issuer WHERE id = \\$1").WithArgs("1").WillReturnRows(rows)
+	expectIssuerRisk(mock, "1", RiskInputs{})
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM bid WHERE status = 'pending'").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(250.5))
+
+	// Call GetIssuer through the interceptor like the gRPC server would
+	info := &grpc.UnaryServerInfo{FullMethod: "/invoice.InvoiceService/GetIssuer"}
+	_, err = metricsUnaryInterceptor(context.Background(), &pb.Issuer{Id: "1"}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.GetIssuer(ctx, req.(*pb.Issuer))
+	})
+	assert.NoError(t, err)
+
+	// Scrape
+	ts := httptest.NewServer(MetricsHandler(registry))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `grpc_server_handled_total{grpc_code="OK",grpc_method="GetIssuer",grpc_service="invoice.InvoiceService",grpc_type="unary"}`)
+	assert.Contains(t, string(body), `grpc_server_handling_seconds_count{grpc_method="GetIssuer",grpc_service="invoice.InvoiceService",grpc_type="unary"}`)
+	assert.Contains(t, string(body), "invoice_escrow_outstanding_balance 250.5")
+	assert.Contains(t, string(body), "go_sql_open_connections")
+	assert.Contains(t, string(body), "invoice_trades_approved_total 0")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSplitMethodName(t *testing.T) {
+	service, method := splitMethodName("/invoice.InvoiceService/PlaceBid")
+	assert.Equal(t, "invoice.InvoiceService", service)
+	assert.Equal(t, "PlaceBid", method)
+}
@@ -2,11 +2,26 @@ package pkg
 
 import (
 	"database/sql"
+	"time"
 
 	pb "github.com/berdebotond/bankable_technical_test/protos"
 )
 
 type server struct {
-	db *sql.DB
+	db   *sql.DB
+	fees *FeeSchedules
+	risk *RiskModel
+	kyc  KycProvider
+	// blobs stores the invoice documents, uploads larger than maxDocumentSize are rejected (0 for no limit)
+	blobs           BlobStore
+	maxDocumentSize int64
+	// dayCount is the convention used for bids that don't set one
+	dayCount string
+	// bidCancelLockout is the final period of a timed auction in which its leading bid can't be cancelled
+	bidCancelLockout time.Duration
+	// autoBids places the bids of the auto-bid rules, nil when they aren't run
+	autoBids *AutoBidder
+	// webhooks pushes events to the registered endpoints, nil when they aren't sent
+	webhooks *WebhookDispatcher
 	pb.UnimplementedInvoiceServiceServer
 }
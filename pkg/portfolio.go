@@ -0,0 +1,208 @@
+package pkg
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+)
+
+// Portfolio pages
+const (
+	defaultPortfolioPageSize = 100
+	maxPortfolioPageSize     = 1000
+)
+
+// maturityBuckets are the maturity buckets of active positions in the order they are reported
+var maturityBuckets = []string{"overdue", "0-30", "31-60", "61-90", "90+", "none"}
+
+// portfolioPositions lists the positions of the investor $1 as of the day $2, one per funded invoice. A position
+// is the investor's share of all the trades of the invoice, so its face value and repayments are weighed by it.
+// Closed and overdue invoices are the active positions, repaid and defaulted ones are settled.
+const portfolioPositions = `WITH holding AS (
+		SELECT invoice_id, SUM(amount) AS funded, SUM(investor_fee) AS fees FROM trade WHERE investor_id = $1 GROUP BY invoice_id
+	), share AS (
+		SELECT holding.invoice_id, holding.funded, holding.fees, invoice.issuer_id, COALESCE(issuer.name, '') AS issuer_name,
+			COALESCE(invoice.invoice_number, '') AS invoice_number, invoice.status, invoice.due_date,
+			invoice.status IN ('closed', 'overdue') AS active,
+			invoice.face_value * holding.funded / funding.amount AS face_value,
+			invoice.repaid_amount * holding.funded / funding.amount AS received,
+			(SELECT AVG(effective_yield) FROM bid WHERE bid.invoice_id = holding.invoice_id AND bid.investor_id = $1 AND bid.status = 'approved') AS yield
+		FROM holding
+		JOIN invoice ON invoice.id = holding.invoice_id
+		JOIN issuer ON issuer.id = invoice.issuer_id
+		CROSS JOIN LATERAL (SELECT SUM(amount) AS amount FROM trade WHERE trade.invoice_id = holding.invoice_id) funding
+	), position AS (
+		SELECT *,
+			CASE WHEN active THEN face_value - received ELSE 0 END AS outstanding,
+			face_value - funded - fees AS expected_return,
+			CASE WHEN active THEN 0 ELSE received - funded - fees END AS realized_return,
+			CASE WHEN NOT active THEN ''
+				WHEN due_date IS NULL THEN 'none'
+				WHEN status = 'overdue' OR due_date < $2::date THEN 'overdue'
+				WHEN due_date - $2::date <= 30 THEN '0-30'
+				WHEN due_date - $2::date <= 60 THEN '31-60'
+				WHEN due_date - $2::date <= 90 THEN '61-90'
+				ELSE '90+'
+			END AS maturity_bucket
+		FROM share
+	) `
+
+// ValidatePortfolioRequest checks the request and sets the default page size
+func ValidatePortfolioRequest(in *pb.PortfolioRequest) error {
+	if in.GetInvestorId() == "" {
+		return errors.New("investor id is required")
+	}
+	if in.GetPageSize() < 0 || in.GetPageSize() > maxPortfolioPageSize {
+		return fmt.Errorf("page size must be between 1 and %d", maxPortfolioPageSize)
+	}
+	if in.GetPageSize() == 0 {
+		in.PageSize = defaultPortfolioPageSize
+	}
+	return nil
+}
+
+// GetPortfolioSummary returns the balances of the investor and the totals of their positions
+func GetPortfolioSummary(ctx context.Context, db dbtx, investorID string, today time.Time) (portfolio *pb.Portfolio, err error) {
+	ctx, span := startSpan(ctx, "GetPortfolioSummary", nil)
+	defer func() { endSpan(span, err) }()
+	portfolio = &pb.Portfolio{InvestorId: investorID}
+	var funded, outstanding, expected, realized, yield float64
+	err = db.QueryRowContext(ctx, portfolioPositions+`SELECT investor.balance,
+			COALESCE((SELECT SUM(amount) FROM bid WHERE bid.investor_id = investor.id AND bid.status = 'pending'), 0),
+			COUNT(position.invoice_id),
+			COALESCE(SUM(position.funded) FILTER (WHERE position.active), 0),
+			COALESCE(SUM(position.outstanding) FILTER (WHERE position.active), 0),
+			COALESCE(SUM(position.expected_return) FILTER (WHERE position.active), 0),
+			COALESCE(SUM(position.realized_return) FILTER (WHERE NOT position.active), 0),
+			COALESCE(SUM(position.yield * position.funded) FILTER (WHERE position.active AND position.yield IS NOT NULL)
+				/ NULLIF(SUM(position.funded) FILTER (WHERE position.active AND position.yield IS NOT NULL), 0), 0)
+		FROM investor LEFT JOIN position ON true
+		WHERE investor.id = $1
+		GROUP BY investor.id`, investorID, today).
+		Scan(&portfolio.CashBalance, &portfolio.ReservedBalance, &portfolio.TotalPositions, &funded, &outstanding, &expected, &realized, &yield)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("investor not found")
+		}
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	portfolio.Funded = float32(roundCents(funded))
+	portfolio.Outstanding = float32(roundCents(outstanding))
+	portfolio.ExpectedReturn = float32(roundCents(expected))
+	portfolio.RealizedReturn = float32(roundCents(realized))
+	portfolio.WeightedAverageYield = float32(yield)
+	return portfolio, nil
+}
+
+// GetPortfolioBreakdowns sums up the active positions of the portfolio by issuer, the largest outstanding first,
+// and by maturity bucket
+func GetPortfolioBreakdowns(ctx context.Context, db dbtx, portfolio *pb.Portfolio, today time.Time) (err error) {
+	ctx, span := startSpan(ctx, "GetPortfolioBreakdowns", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, portfolioPositions+`
+		SELECT 'issuer', issuer_id::text, issuer_name, COUNT(*), SUM(funded), SUM(outstanding) FROM position WHERE active GROUP BY issuer_id, issuer_name
+		UNION ALL
+		SELECT 'maturity', maturity_bucket, '', COUNT(*), SUM(funded), SUM(outstanding) FROM position WHERE active GROUP BY maturity_bucket`,
+		portfolio.GetInvestorId(), today)
+	if err != nil {
+		return fmt.Errorf("failed to query portfolio breakdowns: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var dimension string
+		var funded, outstanding float64
+		breakdown := &pb.PortfolioBreakdown{}
+		if err := rows.Scan(&dimension, &breakdown.Key, &breakdown.Name, &breakdown.Positions, &funded, &outstanding); err != nil {
+			return fmt.Errorf("failed to scan portfolio breakdown: %w", err)
+		}
+		breakdown.Funded, breakdown.Outstanding = float32(roundCents(funded)), float32(roundCents(outstanding))
+		if dimension == "issuer" {
+			portfolio.ByIssuer = append(portfolio.ByIssuer, breakdown)
+		} else {
+			portfolio.ByMaturity = append(portfolio.ByMaturity, breakdown)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read portfolio breakdowns: %w", err)
+	}
+
+	slices.SortFunc(portfolio.ByIssuer, func(a, b *pb.PortfolioBreakdown) int {
+		if a.GetOutstanding() != b.GetOutstanding() {
+			return cmp.Compare(b.GetOutstanding(), a.GetOutstanding())
+		}
+		return strings.Compare(a.GetKey(), b.GetKey())
+	})
+	slices.SortFunc(portfolio.ByMaturity, func(a, b *pb.PortfolioBreakdown) int {
+		return slices.Index(maturityBuckets, a.GetKey()) - slices.Index(maturityBuckets, b.GetKey())
+	})
+	return nil
+}
+
+// GetPortfolioPositions adds a page of positions to the portfolio, the ones after the page token ordered by invoice id
+func GetPortfolioPositions(ctx context.Context, db dbtx, portfolio *pb.Portfolio, in *pb.PortfolioRequest, today time.Time) (err error) {
+	ctx, span := startSpan(ctx, "GetPortfolioPositions", nil)
+	defer func() { endSpan(span, err) }()
+	// One more than the page tells whether there is a next one
+	rows, err := db.QueryContext(ctx, portfolioPositions+`
+		SELECT invoice_id::text, issuer_id::text, issuer_name, invoice_number, status, COALESCE(to_char(due_date, 'YYYY-MM-DD'), ''),
+			funded, fees, face_value, received, outstanding, expected_return, realized_return, COALESCE(yield, 0), maturity_bucket
+		FROM position WHERE invoice_id::text > $3 ORDER BY invoice_id::text LIMIT $4`,
+		in.GetInvestorId(), today, in.GetPageToken(), in.GetPageSize()+1)
+	if err != nil {
+		return fmt.Errorf("failed to query portfolio positions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var funded, fees, faceValue, received, outstanding, expected, realized float64
+		position := &pb.Position{}
+		err := rows.Scan(&position.InvoiceId, &position.IssuerId, &position.IssuerName, &position.InvoiceNumber, &position.Status, &position.DueDate,
+			&funded, &fees, &faceValue, &received, &outstanding, &expected, &realized, &position.Yield, &position.MaturityBucket)
+		if err != nil {
+			return fmt.Errorf("failed to scan portfolio position: %w", err)
+		}
+		position.Funded, position.Fees = float32(roundCents(funded)), float32(roundCents(fees))
+		position.FaceValue, position.Received = float32(roundCents(faceValue)), float32(roundCents(received))
+		position.Outstanding = float32(roundCents(outstanding))
+		position.ExpectedReturn, position.RealizedReturn = float32(roundCents(expected)), float32(roundCents(realized))
+		portfolio.Positions = append(portfolio.Positions, position)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read portfolio positions: %w", err)
+	}
+	if len(portfolio.Positions) > int(in.GetPageSize()) {
+		portfolio.Positions = portfolio.Positions[:in.GetPageSize()]
+		portfolio.NextPageToken = portfolio.Positions[len(portfolio.Positions)-1].GetInvoiceId()
+	}
+	return nil
+}
+
+// GetActiveBids adds the pending bids of the investor to the portfolio
+func GetActiveBids(ctx context.Context, db dbtx, portfolio *pb.Portfolio) (err error) {
+	ctx, span := startSpan(ctx, "GetActiveBids", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT id, invoice_id, amount, status, COALESCE(rate, 0), COALESCE(rate_type, ''), COALESCE(day_count, ''),
+			COALESCE(effective_yield, 0)
+		FROM bid WHERE investor_id = $1 AND status = 'pending' ORDER BY invoice_id, id`, portfolio.GetInvestorId())
+	if err != nil {
+		return fmt.Errorf("failed to query active bids: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		bid := &pb.Bid{InvestorId: portfolio.GetInvestorId()}
+		if err := rows.Scan(&bid.Id, &bid.InvoiceId, &bid.Amount, &bid.Status, &bid.Rate, &bid.RateType, &bid.DayCount, &bid.EffectiveYield); err != nil {
+			return fmt.Errorf("failed to scan active bid: %w", err)
+		}
+		portfolio.ActiveBids = append(portfolio.ActiveBids, bid)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read active bids: %w", err)
+	}
+	return nil
+}
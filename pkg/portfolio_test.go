@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func expectPortfolioSummary(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("WITH holding AS (.+) SELECT investor.balance, (.+) FROM investor LEFT JOIN position ON true").
+		WithArgs("investor-id", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "reserved", "positions", "funded", "outstanding", "expected", "realized", "yield"}).
+			AddRow(500.0, 150.0, 3, 1800.0, 2000.004, 180.0, 25.5, 9.25))
+}
+
+func expectPortfolioBreakdowns(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("WITH holding AS (.+) SELECT 'issuer', (.+) UNION ALL SELECT 'maturity'").
+		WithArgs("investor-id", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"dimension", "key", "name", "positions", "funded", "outstanding"}).
+			AddRow("issuer", "issuer-2", "Small Ltd", 1, 450.0, 500.0).
+			AddRow("issuer", "issuer-1", "Big Ltd", 1, 1350.0, 1500.0).
+			AddRow("maturity", "none", "", 1, 450.0, 500.0).
+			AddRow("maturity", "0-30", "", 1, 1350.0, 1500.0))
+}
+
+func expectActiveBids(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("FROM bid WHERE investor_id = \\$1 AND status = 'pending' ORDER BY invoice_id, id").
+		WithArgs("investor-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "invoice_id", "amount", "status", "rate", "rate_type", "day_count", "effective_yield"}).
+			AddRow("bid-1", "invoice-9", 150.0, "pending", 8.5, RateTypeDiscount, DayCountACT360, 8.7))
+}
+
+func positionRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"invoice_id", "issuer_id", "issuer_name", "invoice_number", "status", "due_date",
+		"funded", "fees", "face_value", "received", "outstanding", "expected_return", "realized_return", "yield", "maturity_bucket"}).
+		AddRow("invoice-1", "issuer-1", "Big Ltd", "INV-1", "closed", "2024-04-15", 1350.0, 13.5, 1500.0, 0.0, 1500.0, 136.5, 0.0, 9.5, "0-30").
+		AddRow("invoice-2", "issuer-2", "Small Ltd", "", "closed", "", 450.0, 0.0, 500.0, 0.0, 500.0, 50.0, 0.0, 0.0, "none").
+		AddRow("invoice-3", "issuer-1", "Big Ltd", "INV-0", "repaid", "2024-02-01", 900.0, 9.0, 1000.0, 1000.0, 0.0, 91.0, 91.0, 8.0, "")
+}
+
+func TestGetPortfolio(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	expectPortfolioSummary(mock)
+	expectPortfolioBreakdowns(mock)
+	expectActiveBids(mock)
+	mock.ExpectQuery("WITH holding AS (.+) FROM position WHERE invoice_id::text > \\$3 ORDER BY invoice_id::text LIMIT \\$4").
+		WithArgs("investor-id", sqlmock.AnyArg(), "", int32(3)).
+		WillReturnRows(positionRows())
+	mock.ExpectCommit()
+
+	portfolio, err := s.GetPortfolio(asParty(PartyInvestor, "investor-id"), &pb.PortfolioRequest{InvestorId: "investor-id", PageSize: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, float32(500), portfolio.CashBalance)
+	assert.Equal(t, float32(150), portfolio.ReservedBalance)
+	assert.Equal(t, int32(3), portfolio.TotalPositions)
+	assert.Equal(t, float32(2000), portfolio.Outstanding)
+	assert.Equal(t, float32(9.25), portfolio.WeightedAverageYield)
+	// Issuers by outstanding, maturity buckets in their order
+	assert.Equal(t, "issuer-1", portfolio.ByIssuer[0].Key)
+	assert.Equal(t, "issuer-2", portfolio.ByIssuer[1].Key)
+	assert.Equal(t, []string{"0-30", "none"}, []string{portfolio.ByMaturity[0].Key, portfolio.ByMaturity[1].Key})
+	assert.Len(t, portfolio.ActiveBids, 1)
+	assert.Equal(t, "investor-id", portfolio.ActiveBids[0].InvestorId)
+	// The third row only tells there is another page
+	assert.Len(t, portfolio.Positions, 2)
+	assert.Equal(t, "invoice-2", portfolio.NextPageToken)
+	expected := &pb.Position{InvoiceId: "invoice-1", IssuerId: "issuer-1", IssuerName: "Big Ltd", InvoiceNumber: "INV-1", Status: "closed",
+		DueDate: "2024-04-15", Funded: 1350, Fees: 13.5, FaceValue: 1500, Outstanding: 1500, ExpectedReturn: 136.5, Yield: 9.5, MaturityBucket: "0-30"}
+	assert.True(t, proto.Equal(expected, portfolio.Positions[0]), "got %v", portfolio.Positions[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPortfolioLastPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM position WHERE invoice_id::text > \\$3").
+		WithArgs("investor-id", sqlmock.AnyArg(), "invoice-2", int32(defaultPortfolioPageSize+1)).
+		WillReturnRows(positionRows())
+
+	in := &pb.PortfolioRequest{InvestorId: "investor-id", PageToken: "invoice-2"}
+	assert.NoError(t, ValidatePortfolioRequest(in))
+	portfolio := &pb.Portfolio{InvestorId: "investor-id"}
+	err = GetPortfolioPositions(context.Background(), db, portfolio, in, day("2024-03-20"))
+
+	assert.NoError(t, err)
+	assert.Len(t, portfolio.Positions, 3)
+	assert.Empty(t, portfolio.NextPageToken)
+	assert.Equal(t, float32(91), portfolio.Positions[2].RealizedReturn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPortfolioInvestorNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM investor LEFT JOIN position ON true").
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "reserved", "positions", "funded", "outstanding", "expected", "realized", "yield"}))
+	mock.ExpectRollback()
+
+	_, err = s.GetPortfolio(asParty(RoleAdmin, ""), &pb.PortfolioRequest{InvestorId: "investor-id"})
+
+	assert.EqualError(t, err, "investor not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPortfolioRequiresInvestor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	// Nothing is read for anonymous callers or other investors
+	_, err = s.GetPortfolio(context.Background(), &pb.PortfolioRequest{InvestorId: "investor-id"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.GetPortfolio(asParty(PartyInvestor, "other-investor-id"), &pb.PortfolioRequest{InvestorId: "investor-id"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidatePortfolioRequest(t *testing.T) {
+	assert.EqualError(t, ValidatePortfolioRequest(&pb.PortfolioRequest{}), "investor id is required")
+	assert.EqualError(t, ValidatePortfolioRequest(&pb.PortfolioRequest{InvestorId: "investor-id", PageSize: 1001}), "page size must be between 1 and 1000")
+	assert.EqualError(t, ValidatePortfolioRequest(&pb.PortfolioRequest{InvestorId: "investor-id", PageSize: -1}), "page size must be between 1 and 1000")
+}
@@ -0,0 +1,20 @@
+package pkg
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// preconditionStatus is the FailedPrecondition status of err, with a google.rpc.PreconditionFailure detail naming
+// the violated check, its subject and why it failed
+func preconditionStatus(err error, violation, subject, description string) *status.Status {
+	st := status.New(codes.FailedPrecondition, err.Error())
+	detailed, derr := st.WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{Type: violation, Subject: subject, Description: description}},
+	})
+	if derr != nil {
+		return st
+	}
+	return detailed
+}
@@ -0,0 +1,189 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+)
+
+// Day count conventions used to turn the days until the due date into a year fraction
+const (
+	DayCountACT360 = "ACT/360"
+	DayCountACT365 = "ACT/365"
+	DayCount30360  = "30/360"
+)
+
+// How Bid.rate is applied to the face value
+const (
+	// RateTypeDiscount is a bank discount rate: advance = face value * (1 - rate * t)
+	RateTypeDiscount = "discount"
+	// RateTypeAPR is a simple annual interest rate earned on the advance: advance = face value / (1 + rate * t)
+	RateTypeAPR = "apr"
+)
+
+// ErrBidNotBetter is returned for bids that don't beat the best pending bid of the invoice
+var ErrBidNotBetter = errors.New("bid doesn't beat the best bid")
+
+// invoiceTerms is the part of an invoice bids are priced against, with the end of its auction if it is timed and
+// the rules of the auction
+type invoiceTerms struct {
+	status             string
+	faceValue          float64
+	dueDate            string
+	auctionEndsAt      sql.NullTime
+	price              float64
+	reservePrice       float64
+	minBidIncrement    float64
+	maxBid             float64
+	antiSnipingSeconds int32
+}
+
+// bestBid is the leading pending bid of an invoice. effectiveYield is only valid for bids on invoices with a due date.
+type bestBid struct {
+	amount         float64
+	effectiveYield sql.NullFloat64
+}
+
+// YearFraction returns the length of the period from start to end in years under the convention
+func YearFraction(convention string, start, end time.Time) (float64, error) {
+	switch convention {
+	case DayCountACT360:
+		return actualDays(start, end) / 360, nil
+	case DayCountACT365:
+		return actualDays(start, end) / 365, nil
+	case DayCount30360:
+		// US 30/360: months count as 30 days
+		d1, d2 := start.Day(), end.Day()
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 && d1 == 30 {
+			d2 = 30
+		}
+		days := 360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1
+		return float64(days) / 360, nil
+	default:
+		return 0, fmt.Errorf("unknown day count convention %q, use ACT/360, ACT/365 or 30/360", convention)
+	}
+}
+
+func actualDays(start, end time.Time) float64 {
+	return end.Sub(start).Hours() / 24
+}
+
+// PriceBid works out the advance of a rate bid and the expected return and effective yield of any bid on an
+// invoice with a due date. Amount bids on invoices without a due date are left as they are.
+// The advance follows the bid's day count, the effective yield always uses defaultDayCount so that bids made under
+// different conventions rank on the same basis.
+func PriceBid(in *pb.Bid, terms invoiceTerms, today time.Time, defaultDayCount string) error {
+	if in.GetRate() != 0 && in.GetAmount() != 0 {
+		return errors.New("set either amount or rate, not both")
+	}
+	if in.GetRate() < 0 {
+		return errors.New("rate must not be negative")
+	}
+	if terms.dueDate == "" {
+		if in.GetRate() != 0 {
+			return errors.New("rate bids need an invoice with a due date")
+		}
+		return nil
+	}
+
+	due, err := time.Parse(dueDateLayout, terms.dueDate)
+	if err != nil {
+		return fmt.Errorf("invalid invoice due date: %w", err)
+	}
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if !due.After(today) {
+		return errors.New("invoice is already due")
+	}
+	if in.GetDayCount() == "" {
+		in.DayCount = defaultDayCount
+	}
+	t, err := YearFraction(in.GetDayCount(), today, due)
+	if err != nil {
+		return err
+	}
+
+	if in.GetRate() != 0 {
+		if in.GetRateType() == "" {
+			in.RateType = RateTypeDiscount
+		}
+		rate := float64(in.GetRate()) / 100
+		var advance float64
+		switch in.GetRateType() {
+		case RateTypeDiscount:
+			advance = terms.faceValue * (1 - rate*t)
+		case RateTypeAPR:
+			advance = terms.faceValue / (1 + rate*t)
+		default:
+			return fmt.Errorf("rate type %q must be discount or apr", in.GetRateType())
+		}
+		if advance <= 0 {
+			return errors.New("rate is too high, the advance would not be positive")
+		}
+		in.Amount = float32(roundCents(advance))
+	}
+
+	in.ExpectedReturn = float32(roundCents(terms.faceValue - float64(in.GetAmount())))
+	if in.GetAmount() > 0 {
+		rankT, err := YearFraction(defaultDayCount, today, due)
+		if err != nil {
+			return err
+		}
+		in.EffectiveYield = float32((terms.faceValue/float64(in.GetAmount()) - 1) / rankT * 100)
+	}
+	return nil
+}
+
+// beatenBy tells whether in ranks above the current best bid: a lower effective yield costs the issuer less,
+// without a due date the higher amount wins
+func (b bestBid) beatenBy(in *pb.Bid) bool {
+	if b.effectiveYield.Valid && in.GetDayCount() != "" {
+		return float64(in.GetEffectiveYield()) < b.effectiveYield.Float64
+	}
+	return float64(in.GetAmount()) > b.amount
+}
+
+// GetInvoiceTerms returns the face value, due date, auction end and auction rules of the invoice a bid is placed on.
+// It locks the invoice row until the transaction ends, which serializes the bids on the invoice.
+func GetInvoiceTerms(ctx context.Context, db dbtx, in *pb.Bid) (terms invoiceTerms, err error) {
+	ctx, span := startSpan(ctx, "GetInvoiceTerms", in)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `SELECT status, face_value, COALESCE(to_char(due_date, 'YYYY-MM-DD'), ''), auction_ends_at, price, reserve_price,
+		min_bid_increment, max_bid, anti_sniping_seconds FROM invoice WHERE id = $1 FOR UPDATE`,
+		in.GetInvoiceId()).
+		Scan(&terms.status, &terms.faceValue, &terms.dueDate, &terms.auctionEndsAt, &terms.price, &terms.reservePrice, &terms.minBidIncrement, &terms.maxBid,
+			&terms.antiSnipingSeconds)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return terms, fmt.Errorf("invoice not found: %w", err)
+		}
+		return terms, fmt.Errorf("failed to get invoice terms: %w", err)
+	}
+	return terms, nil
+}
+
+// bestBidOrder ranks the bids of an invoice by effective yield then amount, the best first
+const bestBidOrder = "ORDER BY effective_yield ASC NULLS LAST, amount DESC"
+
+// GetBestBid returns the leading pending bid of the invoice, ranked by effective yield then amount
+func GetBestBid(ctx context.Context, db dbtx, in *pb.Bid) (best *bestBid, err error) {
+	ctx, span := startSpan(ctx, "GetBestBid", in)
+	defer func() { endSpan(span, err) }()
+	best = &bestBid{}
+	err = db.QueryRowContext(ctx, `SELECT amount, effective_yield FROM bid WHERE invoice_id = $1 AND status = 'pending'
+		`+bestBidOrder+` LIMIT 1`, in.GetInvoiceId()).
+		Scan(&best.amount, &best.effectiveYield)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get best bid: %w", err)
+	}
+	return best, nil
+}
@@ -0,0 +1,138 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(dueDateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestYearFraction(t *testing.T) {
+	tests := []struct {
+		convention string
+		start, end string
+		want       float64
+	}{
+		{DayCountACT360, "2024-01-01", "2024-03-31", 90.0 / 360},
+		{DayCountACT365, "2024-01-01", "2024-03-31", 90.0 / 365},
+		// 30/360 counts February as 30 days and the 31st as the 30th
+		{DayCount30360, "2024-01-31", "2024-03-31", 60.0 / 360},
+		{DayCount30360, "2024-02-15", "2025-02-15", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.convention+" "+tt.start, func(t *testing.T) {
+			got, err := YearFraction(tt.convention, date(tt.start), date(tt.end))
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+
+	_, err := YearFraction("ACT/ACT", date("2024-01-01"), date("2024-02-01"))
+	assert.Error(t, err)
+}
+
+func TestPriceBid(t *testing.T) {
+	terms := invoiceTerms{faceValue: 1000, dueDate: "2024-03-31"}
+	today := date("2024-01-01")
+
+	// 8% discount over 90/360 of a year takes 2% off the face value
+	discount := &pb.Bid{Rate: 8}
+	assert.NoError(t, PriceBid(discount, terms, today, DayCountACT360))
+	assert.Equal(t, float32(980), discount.Amount)
+	assert.Equal(t, float32(20), discount.ExpectedReturn)
+	assert.Equal(t, RateTypeDiscount, discount.RateType)
+	assert.Equal(t, DayCountACT360, discount.DayCount)
+	assert.InDelta(t, 8.163, discount.EffectiveYield, 0.001)
+
+	// 8% APR earns exactly 8% a year on the advance
+	apr := &pb.Bid{Rate: 8, RateType: RateTypeAPR, DayCount: DayCountACT365}
+	assert.NoError(t, PriceBid(apr, terms, today, DayCountACT365))
+	assert.Equal(t, float32(980.66), apr.Amount)
+	assert.InDelta(t, 8, apr.EffectiveYield, 0.01)
+
+	// Amount bids only get their return and yield
+	amount := &pb.Bid{Amount: 990}
+	assert.NoError(t, PriceBid(amount, terms, today, DayCountACT360))
+	assert.Equal(t, float32(990), amount.Amount)
+	assert.Equal(t, float32(10), amount.ExpectedReturn)
+	assert.Less(t, amount.EffectiveYield, discount.EffectiveYield)
+}
+
+func TestPriceBidRanksUnderOneConvention(t *testing.T) {
+	terms := invoiceTerms{faceValue: 1000, dueDate: "2024-03-31"}
+	today := date("2024-01-01")
+
+	// The same advance costs the issuer the same, whatever convention the investor quotes it in
+	act360 := &pb.Bid{Amount: 980, DayCount: DayCountACT360}
+	act365 := &pb.Bid{Amount: 980, DayCount: DayCountACT365}
+	assert.NoError(t, PriceBid(act360, terms, today, DayCountACT360))
+	assert.NoError(t, PriceBid(act365, terms, today, DayCountACT360))
+	assert.Equal(t, act360.EffectiveYield, act365.EffectiveYield)
+	assert.Equal(t, DayCountACT365, act365.DayCount)
+
+	best := bestBid{amount: 980, effectiveYield: sql.NullFloat64{Float64: float64(act360.EffectiveYield), Valid: true}}
+	assert.False(t, best.beatenBy(act365))
+
+	// A higher advance still wins under any convention
+	higher := &pb.Bid{Amount: 981, DayCount: DayCount30360}
+	assert.NoError(t, PriceBid(higher, terms, today, DayCountACT360))
+	assert.True(t, best.beatenBy(higher))
+}
+
+func TestPriceBidRejected(t *testing.T) {
+	today := date("2024-01-01")
+
+	assert.ErrorContains(t, PriceBid(&pb.Bid{Rate: 8, Amount: 10}, invoiceTerms{faceValue: 1000, dueDate: "2024-03-31"}, today, DayCountACT360), "either amount or rate")
+	assert.ErrorContains(t, PriceBid(&pb.Bid{Rate: 8}, invoiceTerms{faceValue: 1000}, today, DayCountACT360), "due date")
+	assert.ErrorContains(t, PriceBid(&pb.Bid{Rate: 8}, invoiceTerms{faceValue: 1000, dueDate: "2023-12-31"}, today, DayCountACT360), "already due")
+	assert.ErrorContains(t, PriceBid(&pb.Bid{Rate: 500}, invoiceTerms{faceValue: 1000, dueDate: "2024-03-31"}, today, DayCountACT360), "too high")
+	assert.ErrorContains(t, PriceBid(&pb.Bid{Rate: 8, RateType: "compound"}, invoiceTerms{faceValue: 1000, dueDate: "2024-03-31"}, today, DayCountACT360), "rate type")
+}
+
+// expectInvoiceTerms expects the lookup of the terms of invoice-id, which locks it
+func expectInvoiceTerms(mock sqlmock.Sqlmock, terms invoiceTerms) {
+	var endsAt any
+	if terms.auctionEndsAt.Valid {
+		endsAt = terms.auctionEndsAt.Time
+	}
+	if terms.status == "" {
+		terms.status = "open"
+	}
+	mock.ExpectQuery("SELECT status, face_value, (.|\\n)+ FROM invoice WHERE id = \\$1 FOR UPDATE").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "face_value", "due_date", "auction_ends_at", "price", "reserve_price", "min_bid_increment", "max_bid",
+			"anti_sniping_seconds"}).
+			AddRow(terms.status, terms.faceValue, terms.dueDate, endsAt, terms.price, terms.reservePrice, terms.minBidIncrement, terms.maxBid, terms.antiSnipingSeconds))
+}
+
+func TestPlaceBidNotBetterThanBestBid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, dayCount: DayCountACT360}
+
+	due := time.Now().UTC().AddDate(0, 3, 0).Format(dueDateLayout)
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{faceValue: 1000, dueDate: due, price: 1000})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycVerified)
+	// The best bid already asks for a 5% yield
+	mock.ExpectQuery("SELECT amount, effective_yield FROM bid WHERE invoice_id = \\$1 AND status = 'pending'").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"amount", "effective_yield"}).AddRow(990.0, 5.0))
+	mock.ExpectRollback()
+
+	_, err = s.PlaceBid(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Rate: 8})
+
+	assert.ErrorIs(t, err, ErrBidNotBetter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,210 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"time"
+)
+
+// Invariants checked by Reconcile, used as the check of every Discrepancy
+const (
+	CheckBalanceConservation = "balance_conservation"
+	CheckNegativeBalance     = "negative_balance"
+	CheckWinningBids         = "winning_bids"
+)
+
+// balanceTolerance absorbs the rounding of the FLOAT balance columns
+const balanceTolerance = 0.01
+
+// Discrepancy is one broken invariant
+type Discrepancy struct {
+	Check    string  `json:"check"`
+	Entity   string  `json:"entity,omitempty"`
+	EntityID string  `json:"entity_id,omitempty"`
+	Expected float64 `json:"expected"`
+	Actual   float64 `json:"actual"`
+	Message  string  `json:"message"`
+}
+
+// ReconciliationTotals are the sums the conservation check compares
+type ReconciliationTotals struct {
+	InvestorBalances float64 `json:"investor_balances"`
+	IssuerBalances   float64 `json:"issuer_balances"`
+	PlatformBalances float64 `json:"platform_balances"`
+	Escrow           float64 `json:"escrow"`
+	NetDeposits      float64 `json:"net_deposits"`
+}
+
+// ReconciliationReport is the machine readable result of a reconciliation run
+type ReconciliationReport struct {
+	StartedAt     time.Time            `json:"started_at"`
+	FinishedAt    time.Time            `json:"finished_at"`
+	OK            bool                 `json:"ok"`
+	Totals        ReconciliationTotals `json:"totals"`
+	Discrepancies []Discrepancy        `json:"discrepancies"`
+}
+
+// Reconcile checks that money is conserved and the marketplace state is consistent:
+// investor, issuer, platform and escrow balances add up to deposits minus withdrawals, no balance is negative
+// and every funded invoice has exactly one winning bid.
+func Reconcile(ctx context.Context, db *sql.DB) (report *ReconciliationReport, err error) {
+	ctx, span := startSpan(ctx, "Reconcile", nil)
+	defer func() { endSpan(span, err) }()
+
+	report = &ReconciliationReport{StartedAt: time.Now().UTC(), Discrepancies: []Discrepancy{}}
+
+	report.Totals, err = GetReconciliationTotals(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	held := report.Totals.InvestorBalances + report.Totals.IssuerBalances + report.Totals.PlatformBalances + report.Totals.Escrow
+	if math.Abs(held-report.Totals.NetDeposits) > balanceTolerance {
+		report.Discrepancies = append(report.Discrepancies, Discrepancy{
+			Check:    CheckBalanceConservation,
+			Expected: report.Totals.NetDeposits,
+			Actual:   held,
+			Message:  "investor, issuer, platform and escrow balances don't add up to deposits minus withdrawals",
+		})
+	}
+
+	negative, err := FindNegativeBalances(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	report.Discrepancies = append(report.Discrepancies, negative...)
+
+	winning, err := FindFundedInvoicesWithoutWinningBid(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	report.Discrepancies = append(report.Discrepancies, winning...)
+
+	report.OK = len(report.Discrepancies) == 0
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+// GetReconciliationTotals returns the balances held by accounts and escrow and the net deposits of the ledger.
+// Refunds only move funds from escrow back to an account, they don't change the net deposits.
+func GetReconciliationTotals(ctx context.Context, db *sql.DB) (totals ReconciliationTotals, err error) {
+	ctx, span := startSpan(ctx, "GetReconciliationTotals", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `SELECT
+		(SELECT COALESCE(SUM(balance), 0) FROM investor),
+		(SELECT COALESCE(SUM(balance), 0) FROM issuer),
+		(SELECT COALESCE(SUM(balance), 0) FROM platform_account),
+		(SELECT COALESCE(SUM(amount), 0) FROM bid WHERE status = 'pending'),
+		(SELECT COALESCE(SUM(CASE kind WHEN 'deposit' THEN amount WHEN 'withdrawal' THEN -amount ELSE 0 END), 0) FROM ledger)`).
+		Scan(&totals.InvestorBalances, &totals.IssuerBalances, &totals.PlatformBalances, &totals.Escrow, &totals.NetDeposits)
+	if err != nil {
+		return totals, fmt.Errorf("failed to get reconciliation totals: %w", err)
+	}
+	return totals, nil
+}
+
+// FindNegativeBalances reports every investor and issuer with a balance below zero
+func FindNegativeBalances(ctx context.Context, db *sql.DB) (discrepancies []Discrepancy, err error) {
+	ctx, span := startSpan(ctx, "FindNegativeBalances", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT 'investor', id, balance FROM investor WHERE balance < 0
+		UNION ALL SELECT 'issuer', id, balance FROM issuer WHERE balance < 0`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query negative balances: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := Discrepancy{Check: CheckNegativeBalance, Message: "balance is negative"}
+		if err := rows.Scan(&d.Entity, &d.EntityID, &d.Actual); err != nil {
+			return nil, fmt.Errorf("failed to scan negative balance: %w", err)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read negative balances: %w", err)
+	}
+	return discrepancies, nil
+}
+
+// FindFundedInvoicesWithoutWinningBid reports funded invoices that don't have exactly one approved bid
+func FindFundedInvoicesWithoutWinningBid(ctx context.Context, db *sql.DB) (discrepancies []Discrepancy, err error) {
+	ctx, span := startSpan(ctx, "FindFundedInvoicesWithoutWinningBid", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `SELECT invoice.id, COUNT(bid.id) FROM invoice
+		LEFT JOIN bid ON bid.invoice_id = invoice.id AND bid.status = 'approved'
+		WHERE invoice.status IN ('closed', 'repaid', 'overdue', 'defaulted')
+		GROUP BY invoice.id HAVING COUNT(bid.id) <> 1`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query winning bids: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d := Discrepancy{Check: CheckWinningBids, Entity: "invoice", Expected: 1, Message: "funded invoice must have exactly one winning bid"}
+		if err := rows.Scan(&d.EntityID, &d.Actual); err != nil {
+			return nil, fmt.Errorf("failed to scan winning bids: %w", err)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read winning bids: %w", err)
+	}
+	return discrepancies, nil
+}
+
+// WriteReport writes the report as indented JSON
+func (r *ReconciliationReport) WriteReport(path string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write next to the target and rename, so readers never see a partial report
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write reconciliation report: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write reconciliation report: %w", err)
+	}
+	return nil
+}
+
+// RunReconciler reconciles every interval until ctx is cancelled. Discrepancies are logged, exported as metrics
+// and, when reportFile is set, the latest report is written to it.
+func RunReconciler(ctx context.Context, db *sql.DB, interval time.Duration, reportFile string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reconcileOnce(ctx, db, reportFile)
+		}
+	}
+}
+
+func reconcileOnce(ctx context.Context, db *sql.DB, reportFile string) {
+	report, err := Reconcile(ctx, db)
+	if err != nil {
+		slog.Error("Reconciliation failed", "err", err)
+		return
+	}
+	recordReconciliation(report)
+
+	for _, d := range report.Discrepancies {
+		slog.Warn("Reconciliation discrepancy", "check", d.Check, "entity", d.Entity, "entity_id", d.EntityID, "expected", d.Expected, "actual", d.Actual,
+			"message", d.Message)
+	}
+	if reportFile != "" {
+		if err := report.WriteReport(reportFile); err != nil {
+			slog.Error("Error writing reconciliation report", "err", err)
+		}
+	}
+}
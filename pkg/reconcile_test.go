@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectReconciliation(mock sqlmock.Sqlmock, investors, issuers, platform, escrow, deposits float64) {
+	mock.ExpectQuery("SELECT \\(SELECT COALESCE\\(SUM\\(balance\\), 0\\) FROM investor\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"investors", "issuers", "platform", "escrow", "deposits"}).AddRow(investors, issuers, platform, escrow, deposits))
+}
+
+func TestReconcileBalanced(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	expectReconciliation(mock, 900, 1040, 10, 50, 2000)
+	mock.ExpectQuery("FROM investor WHERE balance < 0").WillReturnRows(sqlmock.NewRows([]string{"type", "id", "balance"}))
+	mock.ExpectQuery("WHERE invoice.status IN").WillReturnRows(sqlmock.NewRows([]string{"id", "count"}))
+
+	report, err := Reconcile(context.Background(), db)
+
+	assert.NoError(t, err)
+	assert.True(t, report.OK)
+	assert.Empty(t, report.Discrepancies)
+	assert.Equal(t, ReconciliationTotals{InvestorBalances: 900, IssuerBalances: 1040, PlatformBalances: 10, Escrow: 50, NetDeposits: 2000}, report.Totals)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReconcileDiscrepancies(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// A refund applied twice shows up as more money than was ever deposited
+	expectReconciliation(mock, 1100, 1050, 0, 0, 2000)
+	mock.ExpectQuery("FROM investor WHERE balance < 0").
+		WillReturnRows(sqlmock.NewRows([]string{"type", "id", "balance"}).AddRow("issuer", "issuer-id", -5.0))
+	mock.ExpectQuery("WHERE invoice.status IN").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "count"}).AddRow("invoice-id", 0))
+
+	report, err := Reconcile(context.Background(), db)
+
+	assert.NoError(t, err)
+	assert.False(t, report.OK)
+	assert.Equal(t, []Discrepancy{
+		{Check: CheckBalanceConservation, Expected: 2000, Actual: 2150, Message: "investor, issuer, platform and escrow balances don't add up to deposits minus withdrawals"},
+		{Check: CheckNegativeBalance, Entity: "issuer", EntityID: "issuer-id", Actual: -5, Message: "balance is negative"},
+		{Check: CheckWinningBids, Entity: "invoice", EntityID: "invoice-id", Expected: 1, Actual: 0, Message: "funded invoice must have exactly one winning bid"},
+	}, report.Discrepancies)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	recordReconciliation(report)
+	assert.Equal(t, 1.0, testutil.ToFloat64(reconciliationDiscrepancies.WithLabelValues(CheckNegativeBalance)))
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(t, report.WriteReport(path))
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var decoded ReconciliationReport
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Len(t, decoded.Discrepancies, 3)
+}
@@ -0,0 +1,241 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+)
+
+// Invoice states after funding. A funded invoice is closed until it is repaid in full, becomes overdue
+// once its due date has passed and defaulted when it stays overdue too long.
+const (
+	InvoiceStatusClosed    = "closed"
+	InvoiceStatusRepaid    = "repaid"
+	InvoiceStatusOverdue   = "overdue"
+	InvoiceStatusDefaulted = "defaulted"
+)
+
+// Who repays an invoice
+const (
+	RepaymentPayerIssuer = "issuer"
+	RepaymentPayerDebtor = "debtor"
+)
+
+// dueDateLayout is the format of Invoice.due_date
+const dueDateLayout = "2006-01-02"
+
+var (
+	// ErrInvoiceNotRepayable is returned for repayments on invoices that aren't funded or are already repaid
+	ErrInvoiceNotRepayable = errors.New("invoice can't be repaid in its current status")
+	// ErrRepaymentExceedsOutstanding is returned for repayments larger than what is still owed
+	ErrRepaymentExceedsOutstanding = errors.New("repayment exceeds the outstanding amount")
+	// ErrInsufficientIssuerBalance is returned when the issuer can't pay the repayment from their balance
+	ErrInsufficientIssuerBalance = errors.New("issuer doesn't have enough balance for the repayment")
+	// ErrNoFundingInvestors is returned when an invoice has no recorded trades to distribute a repayment to
+	ErrNoFundingInvestors = errors.New("invoice has no funding investors")
+)
+
+// repayableInvoice is the part of an invoice a repayment needs
+type repayableInvoice struct {
+	issuerID     string
+	status       string
+	faceValue    float64
+	repaidAmount float64
+}
+
+func (i repayableInvoice) outstanding() float64 {
+	return i.faceValue - i.repaidAmount
+}
+
+// ValidateRepayment checks the request before anything is read from the database
+func ValidateRepayment(in *pb.Repayment) error {
+	if in.GetInvoiceId() == "" {
+		return errors.New("invoice id is required")
+	}
+	if in.GetAmount() <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if in.GetPayer() != RepaymentPayerIssuer && in.GetPayer() != RepaymentPayerDebtor {
+		return fmt.Errorf("payer %q must be issuer or debtor", in.GetPayer())
+	}
+	return nil
+}
+
+// ValidateDueDate checks that a due date is empty or a YYYY-MM-DD date
+func ValidateDueDate(dueDate string) error {
+	if dueDate == "" {
+		return nil
+	}
+	if _, err := time.Parse(dueDateLayout, dueDate); err != nil {
+		return fmt.Errorf("due date %q must be formatted as YYYY-MM-DD", dueDate)
+	}
+	return nil
+}
+
+// GetInvoiceForRepayment reads and locks the invoice until the end of the transaction
+func GetInvoiceForRepayment(ctx context.Context, db dbtx, in *pb.Repayment) (invoice repayableInvoice, err error) {
+	ctx, span := startSpan(ctx, "GetInvoiceForRepayment", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, "SELECT issuer_id, status, face_value, repaid_amount FROM invoice WHERE id = $1 FOR UPDATE", in.GetInvoiceId()).
+		Scan(&invoice.issuerID, &invoice.status, &invoice.faceValue, &invoice.repaidAmount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return invoice, fmt.Errorf("invoice not found: %w", err)
+		}
+		return invoice, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return invoice, nil
+}
+
+// CollectRepayment takes the repayment from the issuer's balance, or records the debtor's payment as money
+// entering the marketplace
+func CollectRepayment(ctx context.Context, db dbtx, in *pb.Repayment, issuerID string) (err error) {
+	ctx, span := startSpan(ctx, "CollectRepayment", nil)
+	defer func() { endSpan(span, err) }()
+	if in.GetPayer() == RepaymentPayerDebtor {
+		_, err = db.ExecContext(ctx, "INSERT INTO ledger (account_type, account_id, kind, amount) VALUES ('debtor', $1, 'deposit', $2)", in.GetInvoiceId(), in.GetAmount())
+		if err != nil {
+			return fmt.Errorf("failed to record debtor payment: %w", err)
+		}
+		return nil
+	}
+
+	res, err := db.ExecContext(ctx, "UPDATE issuer SET balance = balance - $1 WHERE id = $2 AND balance >= $1", in.GetAmount(), issuerID)
+	if err != nil {
+		return fmt.Errorf("failed to debit issuer: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to debit issuer: %w", err)
+	}
+	if n == 0 {
+		return ErrInsufficientIssuerBalance
+	}
+	return nil
+}
+
+// repaymentShares weighs the investors who funded the invoice $2 by what they paid for it
+const repaymentShares = `SELECT investor_id, SUM(amount) / SUM(SUM(amount)) OVER () AS weight FROM trade WHERE invoice_id = $2 GROUP BY investor_id`
+
+// DistributeRepayment credits the investors who funded the invoice in proportion to what they paid for it
+func DistributeRepayment(ctx context.Context, db dbtx, in *pb.Repayment) (err error) {
+	ctx, span := startSpan(ctx, "DistributeRepayment", nil)
+	defer func() { endSpan(span, err) }()
+	res, err := db.ExecContext(ctx, `UPDATE investor SET balance = balance + $1 * share.weight
+		FROM (`+repaymentShares+`) share
+		WHERE investor.id = share.investor_id`, in.GetAmount(), in.GetInvoiceId())
+	if err != nil {
+		return fmt.Errorf("failed to distribute repayment: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to distribute repayment: %w", err)
+	}
+	if n == 0 {
+		return ErrNoFundingInvestors
+	}
+	return nil
+}
+
+// InsertRepayment records the repayment and updates the repaid amount and status of the invoice
+func InsertRepayment(ctx context.Context, db dbtx, in *pb.Repayment) (err error) {
+	ctx, span := startSpan(ctx, "InsertRepayment", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, "INSERT INTO repayment (invoice_id, amount, payer) VALUES ($1, $2, $3) RETURNING id",
+		in.GetInvoiceId(), in.GetAmount(), in.GetPayer()).Scan(&in.Id)
+	if err != nil {
+		return fmt.Errorf("failed to insert repayment: %w", err)
+	}
+	_, err = db.ExecContext(ctx, "UPDATE invoice SET repaid_amount = repaid_amount + $1, status = $2 WHERE id = $3",
+		in.GetAmount(), in.GetInvoiceStatus(), in.GetInvoiceId())
+	if err != nil {
+		return fmt.Errorf("failed to update invoice repayment: %w", err)
+	}
+	return nil
+}
+
+// InsertRepaymentShares records what each investor got of a recorded repayment, for their statements, and returns
+// the investors
+func InsertRepaymentShares(ctx context.Context, db dbtx, in *pb.Repayment) (investorIDs []string, err error) {
+	ctx, span := startSpan(ctx, "InsertRepaymentShares", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `INSERT INTO repayment_share (repayment_id, investor_id, amount)
+		SELECT $3, share.investor_id, $1 * share.weight FROM (`+repaymentShares+`) share RETURNING investor_id`,
+		in.GetAmount(), in.GetInvoiceId(), in.GetId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert repayment shares: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var investorID string
+		if err := rows.Scan(&investorID); err != nil {
+			return nil, fmt.Errorf("failed to scan repayment share: %w", err)
+		}
+		investorIDs = append(investorIDs, investorID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to insert repayment shares: %w", err)
+	}
+	return investorIDs, nil
+}
+
+// FlagOverdueInvoices moves funded invoices past their due date to overdue
+func FlagOverdueInvoices(ctx context.Context, db dbtx) (flagged int64, err error) {
+	ctx, span := startSpan(ctx, "FlagOverdueInvoices", nil)
+	defer func() { endSpan(span, err) }()
+	res, err := db.ExecContext(ctx, "UPDATE invoice SET status = 'overdue' WHERE status = 'closed' AND due_date < CURRENT_DATE")
+	if err != nil {
+		return 0, fmt.Errorf("failed to flag overdue invoices: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// FlagDefaultedInvoices moves invoices overdue for more than days to defaulted
+func FlagDefaultedInvoices(ctx context.Context, db dbtx, days int) (flagged int64, err error) {
+	ctx, span := startSpan(ctx, "FlagDefaultedInvoices", nil)
+	defer func() { endSpan(span, err) }()
+	res, err := db.ExecContext(ctx, "UPDATE invoice SET status = 'defaulted' WHERE status = 'overdue' AND due_date < CURRENT_DATE - $1::int", days)
+	if err != nil {
+		return 0, fmt.Errorf("failed to flag defaulted invoices: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// RunMaturityChecker flags overdue and defaulted invoices on start and then every interval until ctx is cancelled
+func RunMaturityChecker(ctx context.Context, db *sql.DB, interval time.Duration, defaultAfterDays int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkMaturities(ctx, db, defaultAfterDays)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func checkMaturities(ctx context.Context, db *sql.DB, defaultAfterDays int) {
+	overdue, err := FlagOverdueInvoices(ctx, db)
+	if err != nil {
+		slog.Error("Error flagging overdue invoices", "err", err)
+		return
+	}
+	defaulted, err := FlagDefaultedInvoices(ctx, db, defaultAfterDays)
+	if err != nil {
+		slog.Error("Error flagging defaulted invoices", "err", err)
+		return
+	}
+	if overdue > 0 || defaulted > 0 {
+		log.Printf("Flagged %d overdue and %d defaulted invoices", overdue, defaulted)
+	}
+	invoicesOverdue.Add(float64(overdue))
+	invoicesDefaulted.Add(float64(defaulted))
+}
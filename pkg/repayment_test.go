@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectRepayableInvoice(mock sqlmock.Sqlmock, status string, faceValue, repaid float64) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT issuer_id, status, face_value, repaid_amount FROM invoice WHERE id = \\$1 FOR UPDATE").
+		WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"issuer_id", "status", "face_value", "repaid_amount"}).AddRow("issuer-id", status, faceValue, repaid))
+}
+
+func TestRecordRepaymentFull(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	expectRepayableInvoice(mock, InvoiceStatusOverdue, 100, 40)
+	mock.ExpectExec("UPDATE issuer SET balance = balance - \\$1 WHERE id = \\$2 AND balance >= \\$1").
+		WithArgs(float32(60), "issuer-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE investor SET balance = balance \\+ \\$1 \\* share.weight").
+		WithArgs(float32(60), "invoice-id").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("INSERT INTO repayment").
+		WithArgs("invoice-id", float32(60), RepaymentPayerIssuer).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("repayment-id"))
+	mock.ExpectExec("UPDATE invoice SET repaid_amount = repaid_amount \\+ \\$1, status = \\$2").
+		WithArgs(float32(60), InvoiceStatusRepaid, "invoice-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO repayment_share \\(repayment_id, investor_id, amount\\)").
+		WithArgs(float32(60), "invoice-id", "repayment-id").
+		WillReturnRows(sqlmock.NewRows([]string{"investor_id"}).AddRow("investor-1").AddRow("investor-2"))
+	mock.ExpectCommit()
+
+	repayment, err := s.RecordRepayment(context.Background(), &pb.Repayment{InvoiceId: "invoice-id", Amount: 60})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "repayment-id", repayment.Id)
+	assert.Equal(t, InvoiceStatusRepaid, repayment.InvoiceStatus)
+	assert.Equal(t, float32(0), repayment.Outstanding)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordRepaymentPartialByDebtor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	// Money from the debtor enters the marketplace, so it is recorded in the ledger
+	expectRepayableInvoice(mock, InvoiceStatusClosed, 100, 0)
+	mock.ExpectExec("INSERT INTO ledger \\(account_type, account_id, kind, amount\\) VALUES \\('debtor', \\$1, 'deposit', \\$2\\)").
+		WithArgs("invoice-id", float32(25)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE investor SET balance").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO repayment").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("repayment-id"))
+	mock.ExpectExec("UPDATE invoice SET repaid_amount").
+		WithArgs(float32(25), InvoiceStatusClosed, "invoice-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO repayment_share").WillReturnRows(sqlmock.NewRows([]string{"investor_id"}).AddRow("investor-1"))
+	mock.ExpectCommit()
+
+	repayment, err := s.RecordRepayment(context.Background(), &pb.Repayment{InvoiceId: "invoice-id", Amount: 25, Payer: RepaymentPayerDebtor})
+
+	assert.NoError(t, err)
+	assert.Equal(t, InvoiceStatusClosed, repayment.InvoiceStatus)
+	assert.Equal(t, float32(75), repayment.Outstanding)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecordRepaymentRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		repaid float64
+		err    error
+	}{
+		{"open invoice", "open", 0, ErrInvoiceNotRepayable},
+		{"already repaid", InvoiceStatusRepaid, 100, ErrInvoiceNotRepayable},
+		{"more than owed", InvoiceStatusClosed, 90, ErrRepaymentExceedsOutstanding},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			s := &server{db: db}
+
+			expectRepayableInvoice(mock, tt.status, 100, tt.repaid)
+			mock.ExpectRollback()
+
+			_, err = s.RecordRepayment(context.Background(), &pb.Repayment{InvoiceId: "invoice-id", Amount: 20})
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRecordRepaymentInvalidPayer(t *testing.T) {
+	s := &server{}
+
+	_, err := s.RecordRepayment(context.Background(), &pb.Repayment{InvoiceId: "invoice-id", Amount: 20, Payer: "bank"})
+
+	assert.ErrorContains(t, err, "payer")
+}
+
+func TestCheckMaturities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE invoice SET status = 'overdue' WHERE status = 'closed' AND due_date < CURRENT_DATE").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE invoice SET status = 'defaulted' WHERE status = 'overdue' AND due_date < CURRENT_DATE - \\$1::int").
+		WithArgs(90).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	checkMaturities(context.Background(), db, 90)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,222 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrFundingLimitExceeded is returned when an invoice would take the issuer's outstanding funded amount over its limit
+var ErrFundingLimitExceeded = errors.New("issuer funding limit exceeded")
+
+// RiskGrade is the grade of issuers scoring at least MinScore. MaxOutstanding caps their outstanding funded amount.
+type RiskGrade struct {
+	Grade          string  `json:"grade"`
+	MinScore       int32   `json:"min_score"`
+	MaxOutstanding float64 `json:"max_outstanding"`
+}
+
+// RiskModel scores issuers from their repayment history, overdue ratio and outstanding exposure
+type RiskModel struct {
+	// Weights of each input in the score, they don't have to add up to one
+	RepaymentWeight float64 `json:"repayment_weight"`
+	OverdueWeight   float64 `json:"overdue_weight"`
+	ExposureWeight  float64 `json:"exposure_weight"`
+	// ExposureScale is the outstanding amount at which exposure counts as full risk
+	ExposureScale float64 `json:"exposure_scale"`
+	// NoHistoryRisk is the repayment risk, between 0 and 1, of issuers without repaid or defaulted invoices
+	NoHistoryRisk float64     `json:"no_history_risk"`
+	Grades        []RiskGrade `json:"grades"`
+	// IssuerLimits overrides the funding limit of the grade per issuer id
+	IssuerLimits map[string]float64 `json:"issuer_limits"`
+}
+
+// DefaultRiskModel is used when no risk model file is configured
+func DefaultRiskModel() *RiskModel {
+	return &RiskModel{
+		RepaymentWeight: 0.5,
+		OverdueWeight:   0.3,
+		ExposureWeight:  0.2,
+		ExposureScale:   1000000,
+		NoHistoryRisk:   0.5,
+		Grades: []RiskGrade{
+			{Grade: "A", MinScore: 80, MaxOutstanding: 1000000},
+			{Grade: "B", MinScore: 60, MaxOutstanding: 250000},
+			{Grade: "C", MinScore: 40, MaxOutstanding: 50000},
+			{Grade: "D", MinScore: 0, MaxOutstanding: 10000},
+		},
+	}
+}
+
+// LoadRiskModel reads the risk model from a JSON file, settings missing from it keep their default
+func LoadRiskModel(path string) (*RiskModel, error) {
+	model := DefaultRiskModel()
+	if path == "" {
+		return model, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk model file: %w", err)
+	}
+	if err := json.Unmarshal(b, model); err != nil {
+		return nil, fmt.Errorf("failed to parse risk model file %s: %w", path, err)
+	}
+	if err := model.Validate(); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Validate reports every invalid setting at once and sorts the grades from best to worst
+func (m *RiskModel) Validate() error {
+	var errs []error
+	if m.RepaymentWeight < 0 || m.OverdueWeight < 0 || m.ExposureWeight < 0 {
+		errs = append(errs, errors.New("weights must not be negative"))
+	}
+	if m.RepaymentWeight+m.OverdueWeight+m.ExposureWeight == 0 {
+		errs = append(errs, errors.New("at least one weight must be positive"))
+	}
+	if m.ExposureScale <= 0 {
+		errs = append(errs, errors.New("exposure_scale must be positive"))
+	}
+	if m.NoHistoryRisk < 0 || m.NoHistoryRisk > 1 {
+		errs = append(errs, errors.New("no_history_risk must be between 0 and 1"))
+	}
+	sort.Slice(m.Grades, func(i, j int) bool { return m.Grades[i].MinScore > m.Grades[j].MinScore })
+	if len(m.Grades) == 0 || m.Grades[len(m.Grades)-1].MinScore > 0 {
+		errs = append(errs, errors.New("grades must include one with min_score 0"))
+	}
+	for _, grade := range m.Grades {
+		if grade.MaxOutstanding < 0 {
+			errs = append(errs, fmt.Errorf("grade %s: max_outstanding must not be negative", grade.Grade))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid risk model: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// RiskInputs are the facts an issuer is scored on
+type RiskInputs struct {
+	Repaid      int64
+	Defaulted   int64
+	Overdue     int64
+	Funded      int64
+	Outstanding float64
+	// Listed is the face value of invoices still up for auction, they count against the limit but not the score
+	Listed float64
+}
+
+// Assess scores the issuer and returns its grade and funding limit
+func (m *RiskModel) Assess(issuerID string, inputs RiskInputs) (score int32, grade RiskGrade, limit float64) {
+	repaymentRisk := m.NoHistoryRisk
+	if matured := inputs.Repaid + inputs.Defaulted; matured > 0 {
+		repaymentRisk = float64(inputs.Defaulted) / float64(matured)
+	}
+	var overdueRatio float64
+	if inputs.Funded > 0 {
+		overdueRatio = float64(inputs.Overdue) / float64(inputs.Funded)
+	}
+	exposureRisk := math.Min(inputs.Outstanding/m.ExposureScale, 1)
+
+	weights := m.RepaymentWeight + m.OverdueWeight + m.ExposureWeight
+	risk := (m.RepaymentWeight*repaymentRisk + m.OverdueWeight*overdueRatio + m.ExposureWeight*exposureRisk) / weights
+	score = int32(math.Round(100 * (1 - risk)))
+
+	grade = m.Grades[len(m.Grades)-1]
+	for _, g := range m.Grades {
+		if score >= g.MinScore {
+			grade = g
+			break
+		}
+	}
+	limit = grade.MaxOutstanding
+	if override, ok := m.IssuerLimits[issuerID]; ok {
+		limit = override
+	}
+	return score, grade, limit
+}
+
+// AssessIssuer sets the risk score, grade, funding limit and outstanding amount of the issuer
+func (m *RiskModel) AssessIssuer(ctx context.Context, db dbtx, issuer *pb.Issuer) error {
+	inputs, err := GetIssuerRiskInputs(ctx, db, issuer.GetId())
+	if err != nil {
+		return err
+	}
+	score, grade, limit := m.Assess(issuer.GetId(), inputs)
+	issuer.RiskScore = score
+	issuer.RiskGrade = grade.Grade
+	issuer.FundingLimit = float32(limit)
+	issuer.Outstanding = float32(inputs.Outstanding)
+	return nil
+}
+
+// LockIssuer locks the issuer's row until the transaction ends. Run it before checking the funding limit in the
+// transaction that lists the invoices, so concurrent listings of the issuer are checked one after the other.
+func LockIssuer(ctx context.Context, db dbtx, issuerID string) (err error) {
+	ctx, span := startSpan(ctx, "LockIssuer", nil)
+	defer func() { endSpan(span, err) }()
+	var id string
+	err = db.QueryRowContext(ctx, "SELECT id FROM issuer WHERE id = $1 FOR UPDATE", issuerID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Errorf(codes.NotFound, "issuer %s not found", issuerID)
+		}
+		return fmt.Errorf("failed to lock issuer: %w", err)
+	}
+	return nil
+}
+
+// CheckFundingLimit fails with ErrFundingLimitExceeded when listing an invoice of faceValue could take the
+// issuer's outstanding funded amount over its limit once every listed invoice is funded
+func (m *RiskModel) CheckFundingLimit(ctx context.Context, db dbtx, issuerID string, faceValue float64) error {
+	inputs, err := GetIssuerRiskInputs(ctx, db, issuerID)
+	if err != nil {
+		return err
+	}
+	_, grade, limit := m.Assess(issuerID, inputs)
+	if inputs.Outstanding+inputs.Listed+faceValue > limit+balanceTolerance {
+		return fmt.Errorf("%w: grade %s allows %.2f, %.2f is outstanding and %.2f listed", ErrFundingLimitExceeded,
+			grade.Grade, limit, inputs.Outstanding, inputs.Listed)
+	}
+	return nil
+}
+
+// FundingHeadroom returns the face value the issuer can still list before it could go over its funding limit
+func (m *RiskModel) FundingHeadroom(ctx context.Context, db dbtx, issuerID string) (float64, error) {
+	inputs, err := GetIssuerRiskInputs(ctx, db, issuerID)
+	if err != nil {
+		return 0, err
+	}
+	_, _, limit := m.Assess(issuerID, inputs)
+	return limit - inputs.Outstanding - inputs.Listed, nil
+}
+
+// GetIssuerRiskInputs aggregates the invoices of the issuer into its risk inputs
+func GetIssuerRiskInputs(ctx context.Context, db dbtx, issuerID string) (inputs RiskInputs, err error) {
+	ctx, span := startSpan(ctx, "GetIssuerRiskInputs", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `SELECT
+		COUNT(*) FILTER (WHERE status = 'repaid'),
+		COUNT(*) FILTER (WHERE status = 'defaulted'),
+		COUNT(*) FILTER (WHERE status IN ('overdue', 'defaulted')),
+		COUNT(*) FILTER (WHERE status IN ('closed', 'repaid', 'overdue', 'defaulted')),
+		COALESCE(SUM(face_value - repaid_amount) FILTER (WHERE status IN ('closed', 'overdue', 'defaulted')), 0),
+		COALESCE(SUM(face_value) FILTER (WHERE status NOT IN ('closed', 'repaid', 'overdue', 'defaulted')), 0)
+		FROM invoice WHERE issuer_id = $1`, issuerID).
+		Scan(&inputs.Repaid, &inputs.Defaulted, &inputs.Overdue, &inputs.Funded, &inputs.Outstanding, &inputs.Listed)
+	if err != nil {
+		return inputs, fmt.Errorf("failed to get issuer's risk inputs: %w", err)
+	}
+	return inputs, nil
+}
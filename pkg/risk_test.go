@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectIssuerRisk(mock sqlmock.Sqlmock, issuerID string, inputs RiskInputs) {
+	mock.ExpectQuery("SELECT(.|\\n)+FROM invoice WHERE issuer_id = \\$1").WithArgs(issuerID).
+		WillReturnRows(sqlmock.NewRows([]string{"repaid", "defaulted", "overdue", "funded", "outstanding", "listed"}).
+			AddRow(inputs.Repaid, inputs.Defaulted, inputs.Overdue, inputs.Funded, inputs.Outstanding, inputs.Listed))
+}
+
+func expectLockIssuer(mock sqlmock.Sqlmock, issuerID string) {
+	mock.ExpectQuery("SELECT id FROM issuer WHERE id = \\$1 FOR UPDATE").WithArgs(issuerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(issuerID))
+}
+
+func TestRiskModelAssess(t *testing.T) {
+	model := DefaultRiskModel()
+	model.IssuerLimits = map[string]float64{"trusted": 5000000}
+
+	tests := []struct {
+		name     string
+		issuerID string
+		inputs   RiskInputs
+		score    int32
+		grade    string
+		limit    float64
+	}{
+		{"no history", "issuer-id", RiskInputs{}, 75, "B", 250000},
+		{"always repaid", "issuer-id", RiskInputs{Repaid: 4, Funded: 5, Outstanding: 1000}, 100, "A", 1000000},
+		{"half defaulted", "issuer-id", RiskInputs{Repaid: 1, Defaulted: 1, Overdue: 1, Funded: 2, Outstanding: 100000}, 58, "C", 50000},
+		{"everything defaulted", "issuer-id", RiskInputs{Defaulted: 2, Overdue: 2, Funded: 2, Outstanding: 2000000}, 0, "D", 10000},
+		{"issuer limit override", "trusted", RiskInputs{}, 75, "B", 5000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, grade, limit := model.Assess(tt.issuerID, tt.inputs)
+
+			assert.Equal(t, tt.score, score)
+			assert.Equal(t, tt.grade, grade.Grade)
+			assert.Equal(t, tt.limit, limit)
+		})
+	}
+}
+
+func TestLoadRiskModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "risk.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{
+		"grades": [{"grade": "low", "min_score": 0, "max_outstanding": 100}, {"grade": "high", "min_score": 50, "max_outstanding": 1000}]
+	}`), 0o600))
+
+	model, err := LoadRiskModel(path)
+
+	assert.NoError(t, err)
+	// Missing settings keep their default and grades are sorted from best to worst
+	assert.Equal(t, 0.5, model.RepaymentWeight)
+	assert.Equal(t, []RiskGrade{{Grade: "high", MinScore: 50, MaxOutstanding: 1000}, {Grade: "low", MinScore: 0, MaxOutstanding: 100}}, model.Grades)
+}
+
+func TestLoadRiskModelValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "risk.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{
+		"repayment_weight": -1, "exposure_scale": 0, "no_history_risk": 2,
+		"grades": [{"grade": "A", "min_score": 50, "max_outstanding": -5}]
+	}`), 0o600))
+
+	_, err := LoadRiskModel(path)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "weights must not be negative")
+	assert.Contains(t, err.Error(), "exposure_scale must be positive")
+	assert.Contains(t, err.Error(), "no_history_risk must be between 0 and 1")
+	assert.Contains(t, err.Error(), "grades must include one with min_score 0")
+	assert.Contains(t, err.Error(), "grade A: max_outstanding must not be negative")
+}
+
+func TestCreateInvoiceOverFundingLimit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	// Grade B allows 250000, listing 10000 more on top of 245000 goes over it
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	mock.ExpectBegin()
+	expectLockIssuer(mock, "issuer-id")
+	expectIssuerRisk(mock, "issuer-id", RiskInputs{Funded: 3, Outstanding: 200000, Listed: 45000})
+	mock.ExpectRollback()
+
+	invoice, err := s.CreateInvoice(context.Background(), &pb.Invoice{IssuerId: "issuer-id", Status: "open", Price: 9000, FaceValue: 10000})
+
+	assert.Nil(t, invoice)
+	assert.True(t, errors.Is(err, ErrFundingLimitExceeded))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInvoiceShowsIssuerRiskGrade(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	expectInvoiceDetails(mock, &pb.Invoice{Id: "invoice-id", IssuerId: "issuer-id", Status: "open", Price: 100, FaceValue: 100, Currency: DefaultCurrency})
+	expectIssuerRisk(mock, "issuer-id", RiskInputs{Repaid: 1, Defaulted: 1, Overdue: 1, Funded: 2, Outstanding: 100000})
+
+	invoice, err := s.GetInvoice(context.Background(), &pb.Invoice{Id: "invoice-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "C", invoice.IssuerRiskGrade)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
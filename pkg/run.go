@@ -0,0 +1,221 @@
+package pkg
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Run starts the whole service and blocks until ctx is cancelled, then shuts it down gracefully.
+// The database pool is the last thing to be closed.
+func Run(ctx context.Context, config *cfg.Config) error {
+	SetupLogging(config.LogLevel)
+
+	shutdownTracing, err := SetupTracing(ctx, config.TracingExporter, config.TracingEndpoint, config.TracingFile)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+
+	db, err := SetupDatabase(config)
+	if err != nil {
+		shutdownTracing(ctx)
+		return err
+	}
+
+	lis, err := net.Listen("tcp", config.ListenAddress)
+	if err != nil {
+		shutdownTracing(ctx)
+		db.Close()
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	err = Serve(ctx, db, lis, config)
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if terr := shutdownTracing(flushCtx); terr != nil {
+		slog.Error("Error flushing traces", "err", terr)
+	}
+	if cerr := db.Close(); cerr != nil {
+		slog.Error("Error closing database", "err", cerr)
+	}
+	log.Println("Server stopped")
+	return err
+}
+
+// Serve runs the gRPC server on lis, the REST gateway, the metrics server and the background workers until ctx is cancelled
+// or one of the servers fails. On shutdown it reports NOT_SERVING, stops accepting new RPCs and waits up to
+// config.ShutdownTimeout for in-flight calls and streams to finish before cancelling them.
+// The caller owns db and is expected to close it once Serve returns.
+func Serve(ctx context.Context, db *sql.DB, lis net.Listener, config *cfg.Config) error {
+	s, healthChecker, background, err := SetupServer(db, config)
+	if err != nil {
+		return err
+	}
+
+	// Background workers get their own context so they keep running while in-flight RPCs drain
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		healthChecker.Run(workerCtx)
+	}()
+	for _, worker := range background {
+		workers.Add(1)
+		go func(worker Worker) {
+			defer workers.Done()
+			worker.Run(workerCtx)
+		}(worker)
+	}
+	if config.MaturityCheckInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			RunMaturityChecker(workerCtx, db, config.MaturityCheckInterval, config.DefaultAfterDays)
+		}()
+	}
+	if config.ReconciliationInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			RunReconciler(workerCtx, db, config.ReconciliationInterval, config.ReconciliationReportFile)
+		}()
+	}
+
+	errCh := make(chan error, 3)
+
+	var metricsServer *http.Server
+	if config.MetricsAddress != "" {
+		metricsServer = &http.Server{Addr: config.MetricsAddress, Handler: MetricsHandler(SetupMetrics(db))}
+		go func() {
+			log.Printf("Metrics server started on %s", config.MetricsAddress)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("failed to serve metrics: %w", err)
+			}
+		}()
+	}
+
+	var gatewayServer *http.Server
+	if config.GatewayAddress != "" {
+		// The gateway is an ordinary client of lis, so its calls go through the same interceptors and TLS setup
+		conn, err := dialGateway(lis.Addr(), config)
+		if err != nil {
+			s.Stop()
+			stopWorkers()
+			workers.Wait()
+			return err
+		}
+		defer conn.Close()
+
+		gatewayServer = &http.Server{Addr: config.GatewayAddress, Handler: NewGateway(conn)}
+		go func() {
+			log.Printf("Gateway started on %s", config.GatewayAddress)
+			serve := gatewayServer.ListenAndServe
+			if config.TLSEnabled {
+				// The gateway serves the same certificate as the gRPC server
+				serve = func() error { return gatewayServer.ListenAndServeTLS(config.TLSCertFile, config.TLSKeyFile) }
+			}
+			if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("failed to serve gateway: %w", err)
+			}
+		}()
+	}
+
+	go func() {
+		log.Printf("Server started on %s", lis.Addr())
+		if err := s.Serve(lis); err != nil {
+			errCh <- fmt.Errorf("failed to serve: %w", err)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Println("Shutting down")
+	case err = <-errCh:
+		slog.Error("Shutting down after error", "err", err)
+	}
+
+	// Report NOT_SERVING first so load balancers drain traffic before we stop
+	healthChecker.Shutdown()
+
+	timeout := config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	// Stop the gateway first, its in-flight requests still need the gRPC server
+	if gatewayServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		if gerr := gatewayServer.Shutdown(shutdownCtx); gerr != nil {
+			slog.Error("Error stopping gateway", "err", gerr)
+		}
+		cancel()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		slog.Warn("In-flight RPCs did not finish in time, cancelling them", "timeout", timeout)
+		s.Stop()
+		<-stopped
+	}
+
+	stopWorkers()
+	workers.Wait()
+
+	if metricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if merr := metricsServer.Shutdown(shutdownCtx); merr != nil {
+			slog.Error("Error stopping metrics server", "err", merr)
+		}
+	}
+	return err
+}
+
+// dialGateway connects the gateway to the gRPC server listening on addr, over loopback when it listens on all interfaces
+func dialGateway(addr net.Addr, config *cfg.Config) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if config.TLSEnabled {
+		// The gateway dials its own process, the certificate does not have to be valid for the loopback address
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
+	}
+	conn, err := grpc.NewClient(gatewayTarget(addr), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect gateway: %w", err)
+	}
+	return conn, nil
+}
+
+// gatewayTarget turns the address the gRPC server listens on into one the gateway can dial
+func gatewayTarget(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return "passthrough:///" + net.JoinHostPort(host, port)
+}
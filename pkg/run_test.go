@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServeDrainsInFlightRPCs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.MatchExpectationsInOrder(false)
+
+	// Health checker
+	mock.ExpectQuery("SELECT COALESCE\\(MAX\\(version\\), 0\\) FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(len(migrations)))
+	// A slow RPC that is still running when shutdown starts
+	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).AddRow("1", "Issuer Name", 100.0)
+	mock.ExpectQuery("SELECT id, name, balance FROM issuer WHERE id = \\$1").WithArgs("1").
+		WillDelayFor(200 * time.Millisecond).
+		WillReturnRows(rows)
+	expectIssuerRisk(mock, "1", RiskInputs{})
+
+	lis := bufconn.Listen(1024 * 1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, db, lis, &cfg.Config{ShutdownTimeout: 5 * time.Second})
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewInvoiceServiceClient(conn)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.GetIssuer(context.Background(), &pb.Issuer{Id: "1"})
+		result <- err
+	}()
+
+	// Shut down while GetIssuer is waiting on the database
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	assert.NoError(t, <-result)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after shutdown")
+	}
+
+	// No new RPCs are accepted
+	_, err = client.GetIssuer(context.Background(), &pb.Issuer{Id: "1"})
+	assert.Error(t, err)
+}
+
+func TestGatewayTarget(t *testing.T) {
+	assert.Equal(t, "passthrough:///localhost:50051", gatewayTarget(&net.TCPAddr{IP: net.IPv6unspecified, Port: 50051}))
+	assert.Equal(t, "passthrough:///localhost:50051", gatewayTarget(&net.TCPAddr{IP: net.IPv4zero, Port: 50051}))
+	assert.Equal(t, "passthrough:///127.0.0.1:9000", gatewayTarget(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}))
+}
@@ -1,70 +1,243 @@
 package pkg
 
 import (
+	"bufio"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"strings"
+	"time"
 
+	cfg "github.com/berdebotond/bankable_technical_test/config"
 	pb "github.com/berdebotond/bankable_technical_test/protos"
 	"github.com/golang/protobuf/ptypes/empty"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
+// Worker is a background job of the server, it runs until ctx is cancelled
+type Worker interface {
+	Run(ctx context.Context)
+}
+
 // server is used to implement InvoiceServiceServer.
-func SetupServer(db *sql.DB) *grpc.Server {
-	s := grpc.NewServer()
-	pb.RegisterInvoiceServiceServer(s, &server{db: db})
-	return s
+// SetupServer also registers the grpc.health.v1 service and, if enabled, server reflection. The returned
+// HealthChecker has to be run to report the server as SERVING, and the workers to place the bids of the
+// auto-bid rules and deliver the webhooks.
+func SetupServer(db *sql.DB, config *cfg.Config) (*grpc.Server, *HealthChecker, []Worker, error) {
+	auth, err := LoadAuthTokens(config.AuthTokenFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		// Rejected calls are still counted by the metrics
+		grpc.ChainUnaryInterceptor(metricsUnaryInterceptor, auth.unaryInterceptor),
+		grpc.ChainStreamInterceptor(metricsStreamInterceptor, auth.streamInterceptor),
+	}
+	if config.TLSEnabled {
+		creds, err := credentials.NewServerTLSFromFile(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	fees, err := LoadFeeSchedules(config.FeeScheduleFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	risk, err := LoadRiskModel(config.RiskModelFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	kyc, err := NewKycProvider(config.KycProvider)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	blobs, err := NewBlobStore(config.DocumentStore, config.DocumentDir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	srv := &server{
+		db: db, fees: fees, risk: risk, kyc: kyc, blobs: blobs, maxDocumentSize: int64(config.MaxDocumentSize), dayCount: config.DayCountConvention,
+		bidCancelLockout: config.BidCancelLockout,
+	}
+	srv.autoBids = newAutoBidder(srv)
+	srv.webhooks = newWebhookDispatcher(db, config)
+	s := grpc.NewServer(opts...)
+	pb.RegisterInvoiceServiceServer(s, srv)
+
+	healthChecker := newHealthChecker(db)
+	healthpb.RegisterHealthServer(s, healthChecker.server)
+	if config.EnableReflection {
+		reflection.Register(s)
+	}
+	return s, healthChecker, []Worker{srv.autoBids, srv.webhooks}, nil
 }
 
+// PlaceBid places the bid and lets the auto-bid rules of the investors it outbids answer it
 func (s *server) PlaceBid(ctx context.Context, in *pb.Bid) (*pb.Bid, error) {
+	outbid, err := s.placeBid(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s.autoBids.Enqueue(AutoBidTrigger{InvoiceID: in.GetInvoiceId(), Trigger: AutoBidTriggerOutbid, InvestorIDs: outbid})
+	return in, nil
+}
+
+// placeBid places the bid and returns the investors it outbids. The bid is placed in a single transaction, a failed
+// check or settlement leaves the balances and bids as they were.
+func (s *server) placeBid(ctx context.Context, in *pb.Bid) (outbid []string, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the invoice first: concurrent bids on it queue here, so the best bid, the auction rules and the extension
+	// are checked against the bids committed before this one. Rate bids get their amount from its terms.
+	terms, err := GetInvoiceTerms(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only verified investors can bid
+	err = CheckKycVerified(ctx, tx, PartyInvestor, in.GetInvestorId())
+	if err != nil {
+		return nil, err
+	}
+	// Closed and withdrawn invoices don't take bids
+	if terms.status != "open" {
+		return nil, ErrInvoiceNotOpen
+	}
+	now := time.Now().UTC()
+	if auctionEnded(terms.auctionEndsAt, now) {
+		return nil, ErrAuctionEnded
+	}
+	err = PriceBid(in, terms, now, s.dayCount)
+	if err != nil {
+		return nil, err
+	}
+	if in.GetAmount() <= 0 {
+		return nil, errors.New("amount must be greater than 0")
+	}
+
+	// Only a bid that beats the current best one outbids it
+	best, err := GetBestBid(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	// The issuer's reserve, maximum bid and minimum increment
+	err = CheckAuctionRules(in, terms, best)
+	if err != nil {
+		return nil, err
+	}
+	if best != nil && !best.beatenBy(in) {
+		return nil, ErrBidNotBetter
+	}
+
+	// Determine the status of the bid, a bid at the asking price settles right away
+	status, err := DetermineBidStatus(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	in.Fees = nil
+	var issuerID string
+	if status == "approved" {
+		// The investor pays their share of the fee on top of the amount
+		issuerID, err = s.computeTradeFees(ctx, tx, in)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Check if the investor exists and has enough balance
-	err := CheckInvestorBalance(ctx, s.db, in)
+	err = CheckInvestorBalance(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the exposure limits of the investor's tier
+	err = CheckInvestorLimits(ctx, tx, in)
 	if err != nil {
 		return nil, err
 	}
 
 	// Reduce the investor's balance
-	err = RededuceInvestorBalance(ctx, s.db, in)
+	err = RededuceInvestorBalance(ctx, tx, in)
 	if err != nil {
 		return nil, err
 	}
 
 	// Close previous bids
-	err = CloseBids(ctx, s.db, in)
+	outbid, err = GetOutbidInvestors(ctx, tx, in)
 	if err != nil {
 		return nil, err
 	}
-
-	// Determine the status of the bid
-	status, err := DetermineBidStatus(ctx, s.db, in)
+	err = CloseBids(ctx, tx, in)
 	if err != nil {
 		return nil, err
 	}
 
 	// Insert the new bid
-	err = InsertBid(ctx, s.db, in, status)
+	err = InsertBid(ctx, tx, in, status)
 	if err != nil {
 		return nil, err
 	}
 
 	if status == "approved" {
 		// Update invoice status and investor id
-		err = CloseInvoice(ctx, s.db, in)
+		err = CloseInvoice(ctx, tx, in)
+		if err != nil {
+			return nil, err
+		}
+		err = s.settleTrade(ctx, tx, in, issuerID)
+		if err != nil {
+			return nil, err
+		}
+	} else if endsAt, extend := auctionExtension(terms, now); extend {
+		// A bid in the final seconds gives the other investors time to answer it
+		err = ExtendAuction(ctx, tx, in, endsAt)
 		if err != nil {
 			return nil, err
 		}
+		in.AuctionEndsAt = endsAt.Format(time.RFC3339)
+		auctionsExtended.Inc()
 	}
 	// Update the invoice
-	err = UpdateInvestorInInvoice(ctx, s.db, in)
+	err = UpdateInvestorInInvoice(ctx, tx, in)
 	if err != nil {
 		return nil, err
 	}
-	// Commit the transaction
-	if err != nil {
+
+	// Outbid investors only learn the new best offer, not who made it
+	notice := &pb.Bid{InvoiceId: in.GetInvoiceId(), Amount: in.GetAmount(), EffectiveYield: in.GetEffectiveYield(), AuctionEndsAt: in.GetAuctionEndsAt()}
+	for _, investorID := range outbid {
+		if err := s.webhooks.Publish(ctx, tx, WebhookEventOutbid, PartyInvestor, investorID, notice); err != nil {
+			return nil, err
+		}
+	}
+	if status == "approved" {
+		if err := s.publishTrade(ctx, tx, in, issuerID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	bids, err := ListAllBids(ctx, s.db)
@@ -73,147 +246,1069 @@ func (s *server) PlaceBid(ctx context.Context, in *pb.Bid) (*pb.Bid, error) {
 		return nil, err
 	}
 
-	log.Printf("Bids: %v", bids)
+	slog.Debug("Bids", "bids", bids)
+	bidsPlaced.WithLabelValues(status).Inc()
+	return outbid, nil
+}
 
-	return in, nil
+// publishTrade tells the issuer its invoice was funded and the investor the trade settled, in the trade's transaction
+func (s *server) publishTrade(ctx context.Context, db dbtx, in *pb.Bid, issuerID string) error {
+	if err := s.webhooks.Publish(ctx, db, WebhookEventFunded, PartyIssuer, issuerID, in); err != nil {
+		return err
+	}
+	return s.webhooks.Publish(ctx, db, WebhookEventSettled, PartyInvestor, in.GetInvestorId(), in)
 }
 
 // ApproveTrade approves a trade and set invoice status to closed
 func (s *server) ApproveTrade(ctx context.Context, in *pb.Bid) (*pb.Bid, error) {
 	log.Printf("Approving trade: %v", in)
 	// Update invoice status and investor id
-	log.Printf("Updating invoice: %v", in.GetInvoiceId())
+	// Settle the whole trade or nothing
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Rollback the transaction if anything goes wrong
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
-
-	err = CloseInvoice(ctx, s.db, in)
-
+	// Mark the winning bid first, so it isn't refunded with the others
+	err = ApproveBid(ctx, tx, in)
 	if err != nil {
-		log.Printf("Error updating invoice: %v", err)
+		slog.Error("Error approving bid", "err", err)
 		return nil, err
 	}
 
-	err = CloseBids(ctx, s.db, in)
-
+	log.Printf("Updating invoice: %v", in.GetInvoiceId())
+	err = CloseInvoice(ctx, tx, in)
 	if err != nil {
-		log.Printf("Error updating invoice: %v", err)
+		slog.Error("Error updating invoice", "err", err)
 		return nil, err
 	}
 
-	err = CloseInvoice(ctx, s.db, in)
+	err = CloseBids(ctx, tx, in)
 	if err != nil {
-		log.Printf("Error updating invoice: %v", err)
+		slog.Error("Error updating invoice", "err", err)
 		return nil, err
 	}
 
-	// Update issuer balance
-	log.Printf("Updating Issuer")
-
-	err = UpadeIssuerBalanceByBid(ctx, s.db, in)
+	issuerID, err := s.computeTradeFees(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	err = s.settleTrade(ctx, tx, in, issuerID)
 	if err != nil {
-		log.Printf("Error updating issuer balance: %v", err)
+		slog.Error("Error settling trade", "err", err)
+		return nil, err
+	}
+	if err := s.publishTrade(ctx, tx, in, issuerID); err != nil {
 		return nil, err
 	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	bids, err := ListAllBids(ctx, s.db)
 
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("Bids: %v", bids)
+	slog.Debug("Bids", "bids", bids)
+
+	tradesApproved.Inc()
+	invoiceVolumeFunded.Add(float64(in.GetAmount()))
+	// The trade is already settled at this point, a failed lookup only loses the sample
+	duration, err := GetAuctionDuration(ctx, s.db, in)
+	if err != nil {
+		slog.Error("Error recording auction duration", "err", err)
+	} else {
+		auctionDuration.Observe(duration.Seconds())
+	}
+
 	log.Printf("Trade approved: %v", in)
 	return in, nil
 }
 
-// CreateInvoice creates a new invoice with an existing issuer
-func (s *server) CreateInvoice(ctx context.Context, in *pb.Invoice) (*pb.Invoice, error) {
-	log.Printf("Received: %v", in.GetInvestorId())
-
-	log.Printf("Issuer ID: %v, Status: %v, Investor ID: %v", in.GetIssuerId(), in.GetStatus(), in.GetInvestorId())
+// CancelBid withdraws a pending bid of the calling investor and refunds it, following the rules of the invoice's
+// auction
+func (s *server) CancelBid(ctx context.Context, in *pb.BidCancellation) (*pb.Bid, error) {
+	log.Printf("Cancelling bid: %v", in)
+	if in.GetBidId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "bid id is required")
+	}
+	caller, err := InvestorCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	in.InvestorId = caller.PartyID
 
-	if in.GetPrice() <= 0 {
-		return nil, errors.New("price must be greater than 0")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
-	var id string
-	err := s.db.QueryRow("INSERT INTO invoice (issuer_id, status, investor_id, price) VALUES ($1, $2, $3, $4) RETURNING id", in.GetIssuerId(), in.GetStatus(), in.GetInvestorId(), in.GetPrice()).Scan(&id)
+	defer tx.Rollback()
+
+	cancellation, err := GetBidForCancellation(ctx, tx, in.GetBidId())
 	if err != nil {
 		return nil, err
 	}
-	log.Println("Inserted invoice into database")
+	if cancellation.bid.GetInvestorId() != in.GetInvestorId() {
+		return nil, status.Errorf(codes.PermissionDenied, "bid %s isn't a bid of investor %s", in.GetBidId(), in.GetInvestorId())
+	}
+	if err := CheckBidCancellation(cancellation, time.Now().UTC(), s.bidCancelLockout); err != nil {
+		return nil, err
+	}
+	if err := CancelPendingBid(ctx, tx, cancellation.bid, in.GetReason()); err != nil {
+		return nil, err
+	}
 
-	in.Id = id
-	return in, nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	bidsCancelled.Inc()
+	return cancellation.bid, nil
 }
 
-// GetIssuer returns an issuer by id
-func (s *server) GetIssuer(ctx context.Context, in *pb.Issuer) (*pb.Issuer, error) {
-	log.Printf("Received: %v", in.GetId())
+// CreateAutoBidRule registers a standing order of the calling investor, it bids on the listings and outbids that
+// follow
+func (s *server) CreateAutoBidRule(ctx context.Context, in *pb.AutoBidRule) (*pb.AutoBidRule, error) {
+	log.Printf("Creating auto-bid rule: %v", in)
+	caller, err := InvestorCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	in.InvestorId = caller.PartyID
+	if err := ValidateAutoBidRule(in, s.risk); err != nil {
+		return nil, err
+	}
+	if err := InsertAutoBidRule(ctx, s.db, in); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
 
-	row := s.db.QueryRow("SELECT id, name, balance FROM issuer WHERE id = $1", in.GetId())
+// UpdateAutoBidRule replaces the criteria of a rule, bids it already placed are kept
+func (s *server) UpdateAutoBidRule(ctx context.Context, in *pb.AutoBidRule) (*pb.AutoBidRule, error) {
+	log.Printf("Updating auto-bid rule: %v", in)
+	if in.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "rule id is required")
+	}
+	caller, err := InvestorCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	in.InvestorId = caller.PartyID
+	if err := ValidateAutoBidRule(in, s.risk); err != nil {
+		return nil, err
+	}
+	return UpdateAutoBidRule(ctx, s.db, in)
+}
 
-	issuer := &pb.Issuer{}
-	err := row.Scan(&issuer.Id, &issuer.Name, &issuer.Balance)
+// SetAutoBidRuleStatus pauses or resumes a rule, paused rules don't bid
+func (s *server) SetAutoBidRuleStatus(ctx context.Context, in *pb.AutoBidRuleStatus) (*pb.AutoBidRule, error) {
+	log.Printf("Setting auto-bid rule status: %v", in)
+	if in.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "rule id is required")
+	}
+	caller, err := InvestorCaller(ctx)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("issuer not found")
-		}
 		return nil, err
 	}
+	in.InvestorId = caller.PartyID
+	if in.GetStatus() != AutoBidRuleActive && in.GetStatus() != AutoBidRulePaused {
+		return nil, status.Errorf(codes.InvalidArgument, "status %q must be %s or %s", in.GetStatus(), AutoBidRuleActive, AutoBidRulePaused)
+	}
+	return SetAutoBidRuleStatus(ctx, s.db, in)
+}
 
-	return issuer, nil
+// ListAutoBidRules returns the rules of the calling investor with the budget their bids hold
+func (s *server) ListAutoBidRules(in *pb.Investor, stream pb.InvoiceService_ListAutoBidRulesServer) error {
+	caller, err := InvestorCaller(stream.Context())
+	if err != nil {
+		return err
+	}
+	rows, err := s.db.QueryContext(stream.Context(), "SELECT "+autoBidRuleColumns+" FROM auto_bid_rule WHERE investor_id = $1 ORDER BY created_at, id",
+		caller.PartyID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
 
+	for rows.Next() {
+		rule, err := scanAutoBidRule(rows)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(rule); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
-// GetInvestors returns all investors in stream since it could be a large number of investors
-func (s *server) GetInvestors(in *empty.Empty, stream pb.InvoiceService_GetInvestorsServer) error {
-	rows, err := s.db.Query("SELECT id, name, balance FROM Investor")
+// ListAutoBidExecutions returns the execution log of a rule of the calling investor, latest first
+func (s *server) ListAutoBidExecutions(in *pb.AutoBidExecutionRequest, stream pb.InvoiceService_ListAutoBidExecutionsServer) error {
+	if in.GetRuleId() == "" {
+		return status.Error(codes.InvalidArgument, "rule id is required")
+	}
+	caller, err := InvestorCaller(stream.Context())
+	if err != nil {
+		return err
+	}
+	in.InvestorId = caller.PartyID
+	limit := in.GetLimit()
+	if limit <= 0 {
+		limit = defaultAutoBidExecutionLimit
+	}
+	rows, err := s.db.QueryContext(stream.Context(), `SELECT execution.id, execution.rule_id, execution.invoice_id, execution.trigger, execution.outcome,
+		COALESCE(execution.bid_id::text, ''), execution.amount, execution.message, to_char(execution.created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+		FROM auto_bid_execution execution JOIN auto_bid_rule ON auto_bid_rule.id = execution.rule_id
+		WHERE execution.rule_id = $1 AND auto_bid_rule.investor_id = $2
+		ORDER BY execution.created_at DESC, execution.id LIMIT $3`, in.GetRuleId(), in.GetInvestorId(), limit)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		investor := &pb.Investor{}
-		err := rows.Scan(&investor.Id, &investor.Name, &investor.Balance)
+		execution := &pb.AutoBidExecution{}
+		err := rows.Scan(&execution.Id, &execution.RuleId, &execution.InvoiceId, &execution.Trigger, &execution.Outcome, &execution.BidId,
+			&execution.Amount, &execution.Message, &execution.CreatedAt)
 		if err != nil {
 			return err
 		}
-		if err := stream.Send(investor); err != nil {
+		if err := stream.Send(execution); err != nil {
 			return err
 		}
 	}
+	return rows.Err()
+}
 
-	if err := rows.Err(); err != nil {
+// computeTradeFees sets the fee breakdown of the trade on in and returns the issuer of the invoice
+func (s *server) computeTradeFees(ctx context.Context, db dbtx, in *pb.Bid) (issuerID string, err error) {
+	issuerID, volume, err := GetIssuerSettledVolume(ctx, db, in)
+	if err != nil {
+		return "", err
+	}
+	in.Fees, err = s.fees.For(issuerID).Compute(in.GetAmount(), volume)
+	if err != nil {
+		return "", err
+	}
+	return issuerID, nil
+}
+
+// settleTrade charges the fees computed by computeTradeFees, pays the issuer, credits the platform and records the trade
+func (s *server) settleTrade(ctx context.Context, db dbtx, in *pb.Bid, issuerID string) (err error) {
+	err = ChargeInvestorFee(ctx, db, in)
+	if err != nil {
+		return err
+	}
+
+	// Update issuer balance
+	slog.Debug("Updating issuer")
+	err = UpadeIssuerBalanceByBid(ctx, db, in)
+	if err != nil {
+		slog.Error("Error updating issuer balance", "err", err)
+		return err
+	}
+
+	err = CreditPlatformRevenue(ctx, db, in)
+	if err != nil {
 		return err
 	}
 
+	err = InsertTrade(ctx, db, in, issuerID)
+	if err != nil {
+		return err
+	}
+	platformFees.Add(float64(in.GetFees().GetTotalFee()))
 	return nil
 }
 
-// GetInvoice returns an invoice by id
-func (s *server) GetInvoice(ctx context.Context, in *pb.Invoice) (*pb.Invoice, error) {
+// CreateInvoice creates a new invoice with an existing issuer
+func (s *server) CreateInvoice(ctx context.Context, in *pb.Invoice) (*pb.Invoice, error) {
 	log.Printf("Received: %v", in.GetInvestorId())
 
-	row := s.db.QueryRow("SELECT id, issuer_id, status, investor_id FROM invoice WHERE id = $1", in.GetId())
+	log.Printf("Issuer ID: %v, Status: %v, Investor ID: %v", in.GetIssuerId(), in.GetStatus(), in.GetInvestorId())
+
+	if err := PrepareInvoice(in, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := CheckKycVerified(ctx, s.db, PartyIssuer, in.GetIssuerId()); err != nil {
+		return nil, err
+	}
 
-	invoice := &pb.Invoice{}
-	err := row.Scan(&invoice.Id, &invoice.IssuerId, &invoice.Status, &invoice.InvestorId)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("invoice not found")
-		}
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	// The issuer stays locked until the invoice is inserted, so its concurrent listings can't both pass the limit
+	if err := LockIssuer(ctx, tx, in.GetIssuerId()); err != nil {
+		return nil, err
+	}
+	if err := s.risk.CheckFundingLimit(ctx, tx, in.GetIssuerId(), float64(in.GetFaceValue())); err != nil {
+		return nil, err
+	}
+	if err := InsertInvoice(ctx, tx, in); err != nil {
 		return nil, err
 	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Println("Inserted invoice into database")
+	s.autoBids.Enqueue(AutoBidTrigger{InvoiceID: in.GetId(), Trigger: AutoBidTriggerListing})
 
-	return invoice, nil
+	return in, nil
+}
+
+// ImportInvoice lists an invoice from a UBL e-invoice, the supplier party being the issuer. A document is only
+// imported once, and like any invoice it has to be unique to the issuer by its number.
+func (s *server) ImportInvoice(ctx context.Context, in *pb.InvoiceImport) (*pb.Invoice, error) {
+	log.Printf("Importing e-invoice of %d bytes, Issuer ID: %v", len(in.GetDocument()), in.GetIssuerId())
 
+	if len(in.GetDocument()) == 0 {
+		return nil, errors.New("document is required")
+	}
+	invoice, supplier, err := ParseUBLInvoice(in.GetDocument())
+	if err != nil {
+		return nil, err
+	}
+	invoice.Price = in.GetPrice()
+	if err := PrepareImportedInvoice(invoice, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(in.GetDocument())
+	source := hex.EncodeToString(digest[:])
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	imported, err := FindImportedInvoice(ctx, tx, source)
+	if err != nil {
+		return nil, err
+	}
+	if imported != "" {
+		return nil, status.Errorf(codes.AlreadyExists, "document was already imported as invoice %s", imported)
+	}
+	if invoice.IssuerId, err = ResolveSupplierIssuer(ctx, tx, supplier, in.GetIssuerId()); err != nil {
+		return nil, err
+	}
+	if err := CheckKycVerified(ctx, tx, PartyIssuer, invoice.GetIssuerId()); err != nil {
+		return nil, err
+	}
+	if err := LockIssuer(ctx, tx, invoice.GetIssuerId()); err != nil {
+		return nil, err
+	}
+	if err := s.risk.CheckFundingLimit(ctx, tx, invoice.GetIssuerId(), float64(invoice.GetFaceValue())); err != nil {
+		return nil, err
+	}
+	if err := InsertInvoice(ctx, tx, invoice); err != nil {
+		return nil, err
+	}
+	if err := SetInvoiceSource(ctx, tx, invoice.GetId(), source); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Printf("Imported invoice %s as %s", invoice.GetInvoiceNumber(), invoice.GetId())
+	s.autoBids.Enqueue(AutoBidTrigger{InvoiceID: invoice.GetId(), Trigger: AutoBidTriggerListing})
+
+	return invoice, nil
+}
+
+// ImportInvoicesCsv lists the invoices of an issuer from a CSV file. Every row is validated, then the valid ones are
+// inserted in one transaction unless it is a dry run. The summary reports the invalid rows either way.
+func (s *server) ImportInvoicesCsv(stream pb.InvoiceService_ImportInvoicesCsvServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive CSV: %w", err)
+	}
+	log.Printf("Importing invoices of issuer %v from CSV, dry run: %v", first.GetIssuerId(), first.GetDryRun())
+	if err := ValidateCsvImport(first); err != nil {
+		return err
+	}
+	if err := CheckKycVerified(ctx, s.db, PartyIssuer, first.GetIssuerId()); err != nil {
+		return err
+	}
+
+	content := &chunkReader{data: first.GetData(), next: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		return chunk.GetData(), err
+	}}
+	rows, summary, err := ReadInvoiceCsv(&sizeLimitReader{r: content, max: s.maxDocumentSize}, first, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := LockIssuer(ctx, tx, first.GetIssuerId()); err != nil {
+		return err
+	}
+	rows, err = s.risk.CheckImportRows(ctx, tx, first.GetIssuerId(), rows, summary)
+	if err != nil {
+		return err
+	}
+	if first.GetDryRun() {
+		return stream.SendAndClose(summary)
+	}
+	for _, row := range rows {
+		if err := InsertInvoice(ctx, tx, row.invoice); err != nil {
+			return fmt.Errorf("row %d: %w", row.row, err)
+		}
+		summary.InvoiceIds = append(summary.InvoiceIds, row.invoice.GetId())
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	summary.Imported = int32(len(rows))
+	log.Printf("Imported %d of %d CSV rows", summary.GetImported(), summary.GetRows())
+	for _, id := range summary.GetInvoiceIds() {
+		s.autoBids.Enqueue(AutoBidTrigger{InvoiceID: id, Trigger: AutoBidTriggerListing})
+	}
+
+	return stream.SendAndClose(summary)
+}
+
+// GetIssuer returns an issuer by id
+func (s *server) GetIssuer(ctx context.Context, in *pb.Issuer) (*pb.Issuer, error) {
+	log.Printf("Received: %v", in.GetId())
+
+	row := s.db.QueryRow("SELECT id, name, balance FROM issuer WHERE id = $1", in.GetId())
+
+	issuer := &pb.Issuer{}
+	err := row.Scan(&issuer.Id, &issuer.Name, &issuer.Balance)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("issuer not found")
+		}
+		return nil, err
+	}
+	if err := s.risk.AssessIssuer(ctx, s.db, issuer); err != nil {
+		return nil, err
+	}
+
+	return issuer, nil
+
+}
+
+// GetInvestors returns all investors in stream since it could be a large number of investors
+func (s *server) GetInvestors(in *empty.Empty, stream pb.InvoiceService_GetInvestorsServer) error {
+	// Use the stream context so a forced shutdown cancels the query
+	rows, err := s.db.QueryContext(stream.Context(), "SELECT id, name, balance, tier FROM Investor")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		investor := &pb.Investor{}
+		err := rows.Scan(&investor.Id, &investor.Name, &investor.Balance, &investor.Tier)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(investor); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetInvestorTier creates an investor tier or replaces its limits
+func (s *server) SetInvestorTier(ctx context.Context, in *pb.InvestorTier) (*pb.InvestorTier, error) {
+	log.Printf("Setting investor tier: %v", in)
+	if err := ValidateInvestorTier(in); err != nil {
+		return nil, err
+	}
+	if err := UpsertInvestorTier(ctx, s.db, in); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// ListInvestorTiers returns all investor tiers with their limits
+func (s *server) ListInvestorTiers(in *empty.Empty, stream pb.InvoiceService_ListInvestorTiersServer) error {
+	rows, err := s.db.QueryContext(stream.Context(), "SELECT name, max_issuer_concentration, max_invoice_amount FROM investor_tier ORDER BY name")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tier := &pb.InvestorTier{}
+		if err := rows.Scan(&tier.Name, &tier.MaxIssuerConcentration, &tier.MaxInvoiceAmount); err != nil {
+			return err
+		}
+		if err := stream.Send(tier); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// AssignInvestorTier moves an investor to another tier, its limits apply to the investor's next bids
+func (s *server) AssignInvestorTier(ctx context.Context, in *pb.Investor) (*pb.Investor, error) {
+	log.Printf("Assigning investor %v to tier %v", in.GetId(), in.GetTier())
+	return AssignInvestorTier(ctx, s.db, in)
+}
+
+// SubmitKycDocument adds a document to the onboarding checklist of a pending party
+func (s *server) SubmitKycDocument(ctx context.Context, in *pb.KycDocument) (*pb.KycDocument, error) {
+	log.Printf("Submitting %v document of %v %v", in.GetDocumentType(), in.GetPartyType(), in.GetPartyId())
+	if err := ValidateKycDocument(in); err != nil {
+		return nil, err
+	}
+	if _, err := PartyCaller(ctx, in.GetPartyType(), in.GetPartyId(), false); err != nil {
+		return nil, err
+	}
+	kycStatus, err := GetPartyKycStatus(ctx, s.db, in.GetPartyType(), in.GetPartyId())
+	if err != nil {
+		return nil, err
+	}
+	if kycStatus != KycPending {
+		return nil, fmt.Errorf("%w: documents can only be submitted while pending, %s %s is %s", ErrKycTransition, in.GetPartyType(), in.GetPartyId(), kycStatus)
+	}
+	if err := InsertKycDocument(ctx, s.db, in); err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// GetKycStatus returns the onboarding state of a party with its document checklist
+func (s *server) GetKycStatus(ctx context.Context, in *pb.KycParty) (*pb.KycStatus, error) {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return nil, err
+	}
+	return s.kycStatus(ctx, s.db, in.GetPartyType(), in.GetPartyId())
+}
+
+func (s *server) kycStatus(ctx context.Context, db dbtx, partyType, partyID string) (*pb.KycStatus, error) {
+	kycStatus, err := GetPartyKycStatus(ctx, db, partyType, partyID)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := ListKycDocuments(ctx, db, partyType, partyID)
+	if err != nil {
+		return nil, err
+	}
+	checklist, _ := buildChecklist(partyType, documents)
+	return &pb.KycStatus{PartyType: partyType, PartyId: partyID, Status: kycStatus, Checklist: checklist}, nil
+}
+
+// VerifyKyc has the KYC provider review the checklist of a pending party. The party is verified when every
+// document is accepted and rejected otherwise.
+func (s *server) VerifyKyc(ctx context.Context, in *pb.KycParty) (*pb.KycStatus, error) {
+	log.Printf("Verifying %v %v", in.GetPartyType(), in.GetPartyId())
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return nil, err
+	}
+	current, err := s.kycStatus(ctx, s.db, in.GetPartyType(), in.GetPartyId())
+	if err != nil {
+		return nil, err
+	}
+	if current.GetStatus() != KycPending {
+		return nil, fmt.Errorf("%w: only pending parties are verified, %s %s is %s", ErrKycTransition, in.GetPartyType(), in.GetPartyId(), current.GetStatus())
+	}
+	var missing []string
+	for _, document := range current.GetChecklist() {
+		if document.GetStatus() == DocumentMissing {
+			missing = append(missing, document.GetDocumentType())
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing kyc documents: %s", strings.Join(missing, ", "))
+	}
+
+	// The provider is called before the transaction, so it isn't held open on an external service
+	err = s.kyc.Review(ctx, in, current.GetChecklist())
+	if err != nil {
+		return nil, fmt.Errorf("kyc provider failed: %w", err)
+	}
+	review := &pb.KycReview{PartyType: in.GetPartyType(), PartyId: in.GetPartyId(), Status: KycVerified, Reviewer: s.kyc.Name(), Reason: "all documents accepted"}
+	var rejected []string
+	for _, document := range current.GetChecklist() {
+		if document.GetStatus() != DocumentAccepted {
+			rejected = append(rejected, fmt.Sprintf("%s: %s", document.GetDocumentType(), document.GetReason()))
+		}
+	}
+	if len(rejected) > 0 {
+		review.Status = KycRejected
+		review.Reason = "rejected documents: " + strings.Join(rejected, "; ")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for _, document := range current.GetChecklist() {
+		if err := UpdateKycDocument(ctx, tx, document); err != nil {
+			return nil, err
+		}
+	}
+	if err := SetKycStatus(ctx, tx, review, KycPending); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	current.Status = review.GetStatus()
+	log.Printf("%v %v is %v: %v", in.GetPartyType(), in.GetPartyId(), review.GetStatus(), review.GetReason())
+	return current, nil
+}
+
+// ReviewKyc changes the onboarding state of a party by hand, e.g. to suspend it. The admin calling it is recorded
+// as the reviewer with the reason of the change.
+func (s *server) ReviewKyc(ctx context.Context, in *pb.KycReview) (*pb.KycStatus, error) {
+	log.Printf("Reviewing kyc: %v", in)
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return nil, err
+	}
+	if in.GetReason() == "" {
+		return nil, errors.New("reason is required")
+	}
+	in.Reviewer = KycReviewer(PrincipalFromContext(ctx))
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := s.kycStatus(ctx, tx, in.GetPartyType(), in.GetPartyId())
+	if err != nil {
+		return nil, err
+	}
+	if !canTransition(current.GetStatus(), in.GetStatus()) {
+		return nil, fmt.Errorf("%w: %s to %q", ErrKycTransition, current.GetStatus(), in.GetStatus())
+	}
+	if in.GetStatus() == KycVerified {
+		if _, complete := buildChecklist(in.GetPartyType(), current.GetChecklist()); !complete {
+			return nil, fmt.Errorf("%w: every checklist document must be accepted first", ErrKycTransition)
+		}
+	}
+	if err := SetKycStatus(ctx, tx, in, current.GetStatus()); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	current.Status = in.GetStatus()
+	return current, nil
+}
+
+// UploadInvoiceDocument stores a document of an invoice sent in chunks by its issuer. The SHA-256 digest of the
+// content is recorded so downloads can be checked against it.
+func (s *server) UploadInvoiceDocument(stream pb.InvoiceService_UploadInvoiceDocumentServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed to receive document: %w", err)
+	}
+	log.Printf("Uploading document %v of invoice %v", first.GetFileName(), first.GetInvoiceId())
+	caller, err := DocumentCaller(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ValidateDocumentUpload(first, caller); err != nil {
+		return err
+	}
+	allowed, err := CanAccessInvoiceDocuments(ctx, s.db, first.GetInvoiceId(), PartyIssuer, caller.PartyID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "issuer %s did not issue invoice %s", caller.PartyID, first.GetInvoiceId())
+	}
+
+	content := &chunkReader{data: first.GetData(), next: func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		return chunk.GetData(), err
+	}}
+	document, key, err := StoreInvoiceDocument(ctx, s.blobs, first, content, s.maxDocumentSize)
+	if err != nil {
+		return err
+	}
+	if err := InsertInvoiceDocument(ctx, s.db, document, key, caller.PartyID); err != nil {
+		// Don't keep a blob nothing refers to
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			slog.Error("Error deleting blob", "key", key, "err", err)
+		}
+		return err
+	}
+	log.Printf("Document uploaded: %v", document)
+	return stream.SendAndClose(document)
+}
+
+// DownloadInvoiceDocument streams a document of an invoice, the latest one when no document id is given, to the
+// issuer or an investor allowed to see it
+func (s *server) DownloadInvoiceDocument(in *pb.DocumentRequest, stream pb.InvoiceService_DownloadInvoiceDocumentServer) error {
+	ctx := stream.Context()
+	caller, err := DocumentCaller(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ValidateDocumentRequest(in); err != nil {
+		return err
+	}
+	allowed, err := CanAccessInvoiceDocuments(ctx, s.db, in.GetInvoiceId(), caller.Role, caller.PartyID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return status.Errorf(codes.PermissionDenied, "%s %s may not read the documents of invoice %s", caller.Role, caller.PartyID, in.GetInvoiceId())
+	}
+	document, key, err := GetInvoiceDocument(ctx, s.db, in)
+	if err != nil {
+		return err
+	}
+	blob, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	return SendInvoiceDocument(stream, document, blob)
+}
+
+// GetInvoice returns an invoice by id
+func (s *server) GetInvoice(ctx context.Context, in *pb.Invoice) (*pb.Invoice, error) {
+	log.Printf("Received: %v", in.GetInvestorId())
+
+	invoice, err := GetInvoiceDetails(ctx, s.db, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	issuer := &pb.Issuer{Id: invoice.GetIssuerId()}
+	if err := s.risk.AssessIssuer(ctx, s.db, issuer); err != nil {
+		return nil, err
+	}
+	invoice.IssuerRiskGrade = issuer.GetRiskGrade()
+
+	return invoice, nil
+
+}
+
+// RecordRepayment records a partial or full repayment of a funded invoice and distributes it to the investors
+// who funded it. The invoice becomes repaid once its face value has been paid back.
+func (s *server) RecordRepayment(ctx context.Context, in *pb.Repayment) (*pb.Repayment, error) {
+	log.Printf("Recording repayment: %v", in)
+	if in.GetPayer() == "" {
+		in.Payer = RepaymentPayerIssuer
+	}
+	if err := ValidateRepayment(in); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invoice, err := GetInvoiceForRepayment(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	// Defaulted invoices still accept late recoveries
+	if invoice.status != InvoiceStatusClosed && invoice.status != InvoiceStatusOverdue && invoice.status != InvoiceStatusDefaulted {
+		return nil, fmt.Errorf("%w: %s", ErrInvoiceNotRepayable, invoice.status)
+	}
+	outstanding := invoice.outstanding() - float64(in.GetAmount())
+	if outstanding < -balanceTolerance {
+		return nil, fmt.Errorf("%w: %.2f is still owed", ErrRepaymentExceedsOutstanding, invoice.outstanding())
+	}
+
+	in.InvoiceStatus = invoice.status
+	if outstanding <= balanceTolerance {
+		in.InvoiceStatus = InvoiceStatusRepaid
+		outstanding = 0
+	}
+	in.Outstanding = float32(outstanding)
+
+	err = CollectRepayment(ctx, tx, in, invoice.issuerID)
+	if err != nil {
+		return nil, err
+	}
+	err = DistributeRepayment(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	err = InsertRepayment(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	investorIDs, err := InsertRepaymentShares(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhooks.Publish(ctx, tx, WebhookEventRepaid, PartyIssuer, invoice.issuerID, in); err != nil {
+		return nil, err
+	}
+	for _, investorID := range investorIDs {
+		if err := s.webhooks.Publish(ctx, tx, WebhookEventRepaid, PartyInvestor, investorID, in); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	repaidVolume.Add(float64(in.GetAmount()))
+	log.Printf("Repayment recorded: %v", in)
+	return in, nil
+}
+
+// ExportInvestorStatement streams the statement of an investor for a date range to the investor or an admin
+func (s *server) ExportInvestorStatement(in *pb.StatementRequest, stream pb.InvoiceService_ExportInvestorStatementServer) error {
+	ctx := stream.Context()
+	log.Printf("Exporting investor statement: %v", in)
+	if in.GetFormat() == "" {
+		in.Format = StatementCSV
+	}
+	from, to, err := ValidateStatementRequest(in, time.Now())
+	if err != nil {
+		return err
+	}
+	if _, err := PartyCaller(ctx, PartyInvestor, in.GetInvestorId(), true); err != nil {
+		return err
+	}
+
+	// The balances are worked back from the current one, read everything from the same snapshot
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	statement, err := GetInvestorStatement(ctx, tx, in.GetInvestorId(), from, to)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	out := bufio.NewWriterSize(&statementSender{stream: stream, contentType: statementContentTypes[in.GetFormat()]}, documentChunkSize)
+	if err := WriteStatement(out, in.GetFormat(), statement); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// GetPortfolio returns the balances, returns and exposure of an investor with a page of their positions, to the
+// investor or an admin
+func (s *server) GetPortfolio(ctx context.Context, in *pb.PortfolioRequest) (*pb.Portfolio, error) {
+	log.Printf("Getting portfolio: %v", in)
+	if err := ValidatePortfolioRequest(in); err != nil {
+		return nil, err
+	}
+	if _, err := PartyCaller(ctx, PartyInvestor, in.GetInvestorId(), true); err != nil {
+		return nil, err
+	}
+	today := time.Now().UTC()
+
+	// The totals and the page are read from the same snapshot
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	portfolio, err := GetPortfolioSummary(ctx, tx, in.GetInvestorId(), today)
+	if err != nil {
+		return nil, err
+	}
+	if err := GetPortfolioBreakdowns(ctx, tx, portfolio, today); err != nil {
+		return nil, err
+	}
+	if err := GetActiveBids(ctx, tx, portfolio); err != nil {
+		return nil, err
+	}
+	if err := GetPortfolioPositions(ctx, tx, portfolio, in, today); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return portfolio, nil
+}
+
+// GetIssuerSummary returns the dashboard of an issuer
+func (s *server) GetIssuerSummary(ctx context.Context, in *pb.Issuer) (*pb.IssuerSummary, error) {
+	log.Printf("Getting issuer summary: %v", in.GetId())
+	if in.GetId() == "" {
+		return nil, errors.New("issuer id is required")
+	}
+
+	// The totals, listings and obligations are read from the same snapshot
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+	summary, err := GetIssuerFunding(ctx, tx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := GetInvoiceStatusTotals(ctx, tx, summary); err != nil {
+		return nil, err
+	}
+	if err := GetListedInvoices(ctx, tx, summary); err != nil {
+		return nil, err
+	}
+	if err := GetRepaymentObligations(ctx, tx, summary, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return summary, nil
+}
+
+// RegisterWebhookEndpoint stores an endpoint of an existing issuer or investor, registered by the party itself or
+// an admin. The response is the only one carrying the secret the payloads are signed with.
+func (s *server) RegisterWebhookEndpoint(ctx context.Context, in *pb.WebhookEndpoint) (*pb.WebhookEndpoint, error) {
+	if err := ValidateWebhookEndpoint(in); err != nil {
+		return nil, err
+	}
+	if _, err := PartyCaller(ctx, in.GetPartyType(), in.GetPartyId(), true); err != nil {
+		return nil, err
+	}
+	if _, err := GetPartyKycStatus(ctx, s.db, in.GetPartyType(), in.GetPartyId()); err != nil {
+		return nil, err
+	}
+	if in.GetSecret() == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		in.Secret = secret
+	}
+	if err := InsertWebhookEndpoint(ctx, s.db, in); err != nil {
+		return nil, err
+	}
+	log.Printf("Webhook endpoint %s registered for %s %s", in.GetId(), in.GetPartyType(), in.GetPartyId())
+	return in, nil
+}
+
+// ListWebhookEndpoints returns the endpoints of the party, oldest first
+func (s *server) ListWebhookEndpoints(in *pb.WebhookEndpoint, stream pb.InvoiceService_ListWebhookEndpointsServer) error {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return err
+	}
+	if _, err := PartyCaller(stream.Context(), in.GetPartyType(), in.GetPartyId(), true); err != nil {
+		return err
+	}
+	rows, err := s.db.QueryContext(stream.Context(), "SELECT "+webhookEndpointColumns+` FROM webhook_endpoint
+		WHERE party_type = $1 AND party_id::text = $2 ORDER BY created_at, id`, in.GetPartyType(), in.GetPartyId())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(endpoint); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DeleteWebhookEndpoint disables an endpoint of the party, its deliveries and dead letters are kept
+func (s *server) DeleteWebhookEndpoint(ctx context.Context, in *pb.WebhookEndpoint) (*pb.WebhookEndpoint, error) {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return nil, err
+	}
+	if _, err := PartyCaller(ctx, in.GetPartyType(), in.GetPartyId(), true); err != nil {
+		return nil, err
+	}
+	return DisableWebhookEndpoint(ctx, s.db, in)
+}
+
+// TestWebhookEndpoint sends a sample event to an active endpoint of the party and returns the outcome
+func (s *server) TestWebhookEndpoint(ctx context.Context, in *pb.WebhookEndpoint) (*pb.WebhookDelivery, error) {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return nil, err
+	}
+	if _, err := PartyCaller(ctx, in.GetPartyType(), in.GetPartyId(), true); err != nil {
+		return nil, err
+	}
+	if s.webhooks == nil {
+		return nil, status.Error(codes.Unavailable, "webhooks aren't enabled")
+	}
+	endpoint, secret, err := GetWebhookEndpoint(ctx, s.db, in)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.GetStatus() != WebhookEndpointActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "webhook endpoint %s is %s", endpoint.GetId(), endpoint.GetStatus())
+	}
+	return s.webhooks.sendTest(ctx, endpoint, secret, time.Now())
+}
+
+// ListWebhookDeadLetters returns the dead letters of an endpoint of the party, latest first
+func (s *server) ListWebhookDeadLetters(in *pb.WebhookEndpoint, stream pb.InvoiceService_ListWebhookDeadLettersServer) error {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return err
+	}
+	if _, err := PartyCaller(stream.Context(), in.GetPartyType(), in.GetPartyId(), true); err != nil {
+		return err
+	}
+	if in.GetId() == "" {
+		return status.Error(codes.InvalidArgument, "endpoint id is required")
+	}
+	rows, err := s.db.QueryContext(stream.Context(), "SELECT "+webhookDeadLetterColumns+` FROM webhook_dead_letter
+		WHERE endpoint_id IN (SELECT id FROM webhook_endpoint WHERE id::text = $1 AND party_type = $2 AND party_id::text = $3)
+		ORDER BY created_at DESC, id`, in.GetId(), in.GetPartyType(), in.GetPartyId())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		letter, err := scanWebhookDeadLetter(rows)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(letter); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ReplayWebhookDeadLetter queues the delivery of a dead letter of the party again
+func (s *server) ReplayWebhookDeadLetter(ctx context.Context, in *pb.WebhookReplay) (*pb.WebhookDelivery, error) {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return nil, err
+	}
+	if _, err := PartyCaller(ctx, in.GetPartyType(), in.GetPartyId(), true); err != nil {
+		return nil, err
+	}
+	if in.GetDeadLetterId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "dead letter id is required")
+	}
+	delivery, err := ReplayWebhookDeadLetter(ctx, s.db, in)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Webhook dead letter %s replayed as delivery %s", in.GetDeadLetterId(), delivery.GetId())
+	return delivery, nil
 }
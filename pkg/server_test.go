@@ -20,16 +20,21 @@ func (x *mockInvestorStream) Send(m *pb.Investor) error {
 	x.Responses = append(x.Responses, m)
 	return nil
 }
+
+func (x *mockInvestorStream) Context() context.Context {
+	return context.Background()
+}
 func TestGetIssuer(t *testing.T) {
 	// Setup
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 
-	s := &server{db: db}
+	s := &server{db: db, risk: DefaultRiskModel()}
 
 	// Mock database
 	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).AddRow("1", "Issuer Name", 100.0)
 	mock.ExpectQuery("SELECT id, name, balance FROM issuer WHERE id = \\$1").WithArgs("1").WillReturnRows(rows)
+	expectIssuerRisk(mock, "1", RiskInputs{Repaid: 4, Funded: 5, Outstanding: 1000})
 
 	// Test
 	issuer, err := s.GetIssuer(context.Background(), &pb.Issuer{Id: "1"})
@@ -40,6 +45,10 @@ func TestGetIssuer(t *testing.T) {
 	assert.Equal(t, "1", issuer.Id)
 	assert.Equal(t, "Issuer Name", issuer.Name)
 	assert.Equal(t, float32(100.0), issuer.Balance)
+	assert.Equal(t, int32(100), issuer.RiskScore)
+	assert.Equal(t, "A", issuer.RiskGrade)
+	assert.Equal(t, float32(1000000), issuer.FundingLimit)
+	assert.Equal(t, float32(1000), issuer.Outstanding)
 }
 
 // Similarly, you can write tests for other functions such as CreateInvoice, GetIssuer, GetInvestors, and GetInvoice.
@@ -71,7 +80,7 @@ func TestGetInvoiceNotFound(t *testing.T) {
 	s := &server{db: db}
 
 	// Mock database
-	mock.ExpectQuery("SELECT id, issuer_id, status, investor_id FROM invoice WHERE id = \\$1").WithArgs("nonexistent").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("SELECT id, issuer_id, status, (.|\\n)+ FROM invoice WHERE id = \\$1").WithArgs("nonexistent").WillReturnError(sql.ErrNoRows)
 
 	// Test
 	invoice, err := s.GetInvoice(context.Background(), &pb.Invoice{Id: "nonexistent"})
@@ -92,16 +101,16 @@ func TestGetInvestors(t *testing.T) {
 
 	// Define the expected result
 	expectedInvestors := []*pb.Investor{
-		{Id: "1", Balance: 1000.0, Name: "Investor 1"},
-		{Id: "2", Balance: 2000.0, Name: "Investor 2"},
+		{Id: "1", Balance: 1000.0, Name: "Investor 1", Tier: "standard"},
+		{Id: "2", Balance: 2000.0, Name: "Investor 2", Tier: "standard"},
 	}
 
 	// Set up the mock database to return the expected result
-	rows := sqlmock.NewRows([]string{"id", "name", "balance"})
+	rows := sqlmock.NewRows([]string{"id", "name", "balance", "tier"})
 	for _, investor := range expectedInvestors {
-		rows.AddRow(investor.Id, investor.Name, investor.Balance)
+		rows.AddRow(investor.Id, investor.Name, investor.Balance, investor.Tier)
 	}
-	mock.ExpectQuery("SELECT id, name, balance FROM Investor").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT id, name, balance, tier FROM Investor").WillReturnRows(rows)
 
 	// Create a new server with the mock database
 	s := &server{db: db}
@@ -114,3 +123,87 @@ func TestGetInvestors(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedInvestors, stream.Responses)
 }
+
+func TestApproveTradeKeepsWinningBid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	fees := &FeeSchedules{Default: FeeSchedule{Payer: FeePayerBoth, Percentage: 2, Flat: 1}}
+	s := &server{db: db, fees: fees, webhooks: newTestWebhookDispatcher(db)}
+	bid := &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 120}
+
+	// The winning bid is approved before the remaining pending bids are refunded and closed, all in one transaction
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bid SET status = 'approved'").WithArgs("invoice-id", "investor-id", float32(120)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE invoice SET status = 'closed'").WithArgs("investor-id", "invoice-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("UPDATE bid SET status = 'closed'").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"investor_id", "amount"}).AddRow("other-investor-id", 100.0))
+	mock.ExpectExec("UPDATE investor SET balance = balance \\+ \\$1 WHERE id = \\$2").WithArgs(100.0, "other-investor-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	// 1 + 2% of 120 = 3.40, split evenly
+	mock.ExpectQuery("SELECT invoice.issuer_id").WithArgs("invoice-id").WillReturnRows(sqlmock.NewRows([]string{"issuer_id", "volume"}).AddRow("issuer-id", 0.0))
+	mock.ExpectExec("UPDATE investor SET balance = balance - \\$1").WithArgs(float32(1.7), "investor-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE issuer SET balance = balance \\+ \\$1").WithArgs(float32(118.3), "invoice-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE platform_account SET balance = balance \\+ \\$1").WithArgs(float32(3.4)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO trade").WithArgs("invoice-id", "investor-id", "issuer-id", float32(120), float32(1.7), float32(1.7)).WillReturnResult(sqlmock.NewResult(0, 1))
+	// The webhooks are committed with the trade
+	mock.ExpectExec("INSERT INTO webhook_delivery").WithArgs(WebhookEventFunded, PartyIssuer, "issuer-id", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO webhook_delivery").WithArgs(WebhookEventSettled, PartyInvestor, "investor-id", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT id, investor_id, invoice_id, amount, status FROM bid").WillReturnRows(sqlmock.NewRows([]string{"id", "investor_id", "invoice_id", "amount", "status"}))
+	mock.ExpectQuery("SELECT EXTRACT").WillReturnError(sql.ErrNoRows)
+
+	trade, err := s.ApproveTrade(context.Background(), bid)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float32(3.4), trade.GetFees().GetTotalFee())
+	assert.Equal(t, float32(118.3), trade.GetFees().GetIssuerProceeds())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlaceBidCoversInvestorFee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	fees := &FeeSchedules{Default: FeeSchedule{Payer: FeePayerBoth, Percentage: 2, Flat: 1}}
+	s := &server{db: db, fees: fees, dayCount: DayCountACT360}
+
+	// A bid at the asking price settles at once, the balance has to cover the amount and the 1.70 investor fee
+	mock.ExpectBegin()
+	expectInvoiceTerms(mock, invoiceTerms{faceValue: 120, price: 120})
+	expectKycStatus(mock, PartyInvestor, "investor-id", KycVerified)
+	mock.ExpectQuery("SELECT amount, effective_yield FROM bid").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"amount", "effective_yield"}))
+	mock.ExpectQuery("SELECT price FROM invoice WHERE id = \\$1").WithArgs("invoice-id").
+		WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(120.0))
+	mock.ExpectQuery("SELECT invoice.issuer_id").WithArgs("invoice-id").WillReturnRows(sqlmock.NewRows([]string{"issuer_id", "volume"}).AddRow("issuer-id", 0.0))
+	mock.ExpectQuery("SELECT balance FROM investor WHERE id = \\$1 FOR UPDATE").WithArgs("investor-id").
+		WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(120.0))
+	mock.ExpectRollback()
+
+	bid, err := s.PlaceBid(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 120})
+
+	// Nothing is debited or closed before the trade could settle
+	assert.Nil(t, bid)
+	assert.EqualError(t, err, "investor doesn't have enough balance")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApproveTradeWithoutPendingBid(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	s := &server{db: db, fees: &FeeSchedules{}}
+
+	// Approving the same trade twice must not pay the issuer twice
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE bid SET status = 'approved'").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err = s.ApproveTrade(context.Background(), &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 120})
+
+	assert.ErrorIs(t, err, ErrNoPendingBid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
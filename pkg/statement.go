@@ -0,0 +1,260 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+)
+
+// Statement formats
+const (
+	StatementCSV    = "csv"
+	StatementNDJSON = "ndjson"
+	StatementHTML   = "html"
+)
+
+var statementContentTypes = map[string]string{
+	StatementCSV:    "text/csv; charset=utf-8",
+	StatementNDJSON: "application/x-ndjson",
+	StatementHTML:   "text/html; charset=utf-8",
+}
+
+// Kinds of statement entries. The opening and closing balances are rows of the exported statement too.
+const (
+	EntryOpeningBalance = "opening_balance"
+	EntryDeposit        = "deposit"
+	EntryWithdrawal     = "withdrawal"
+	EntryBidReservation = "bid_reservation"
+	EntryBidRefund      = "bid_refund"
+	EntryTrade          = "trade"
+	EntryFee            = "fee"
+	EntryRepayment      = "repayment"
+	EntryClosingBalance = "closing_balance"
+)
+
+// StatementEntry is a movement of an investor's balance
+type StatementEntry struct {
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	InvoiceID string    `json:"invoice_id,omitempty"`
+	// Reference is the id of the bid, trade, repayment or ledger entry
+	Reference string `json:"reference,omitempty"`
+	// Funded is the amount a trade paid for the invoice. It was reserved by the winning bid, so a trade
+	// doesn't change the balance itself, its fee does.
+	Funded float64 `json:"funded,omitempty"`
+	// Amount is the change of the balance
+	Amount  float64 `json:"amount"`
+	Balance float64 `json:"balance"`
+}
+
+// InvestorStatement is the activity of an investor between two days, both included
+type InvestorStatement struct {
+	InvestorID     string
+	InvestorName   string
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64
+	ClosingBalance float64
+	Entries        []StatementEntry
+	GeneratedAt    time.Time
+}
+
+// end is when the last day of the statement ends
+func (s *InvestorStatement) end() time.Time {
+	return s.To.AddDate(0, 0, 1)
+}
+
+// ValidateStatementRequest checks the request and returns the first and last day of the statement
+func ValidateStatementRequest(in *pb.StatementRequest, today time.Time) (from, to time.Time, err error) {
+	if in.GetInvestorId() == "" {
+		return from, to, errors.New("investor id is required")
+	}
+	if _, ok := statementContentTypes[in.GetFormat()]; !ok {
+		return from, to, fmt.Errorf("statement format %q must be csv, ndjson or html", in.GetFormat())
+	}
+	from, err = time.Parse(dueDateLayout, in.GetFrom())
+	if err != nil {
+		return from, to, fmt.Errorf("from date %q must be formatted as YYYY-MM-DD", in.GetFrom())
+	}
+	to = today.UTC().Truncate(24 * time.Hour)
+	if in.GetTo() != "" {
+		to, err = time.Parse(dueDateLayout, in.GetTo())
+		if err != nil {
+			return from, to, fmt.Errorf("to date %q must be formatted as YYYY-MM-DD", in.GetTo())
+		}
+	}
+	if to.Before(from) {
+		return from, to, errors.New("to date must not be before the from date")
+	}
+	return from, to, nil
+}
+
+// GetInvestorStatement builds the statement of the investor from the stored records: ledger deposits and withdrawals,
+// bids and their refunds, trades with their fees and repayment shares. The balances are worked back from the current
+// balance, so read it in a transaction that sees a single snapshot.
+func GetInvestorStatement(ctx context.Context, db dbtx, investorID string, from, to time.Time) (statement *InvestorStatement, err error) {
+	ctx, span := startSpan(ctx, "GetInvestorStatement", nil)
+	defer func() { endSpan(span, err) }()
+	statement = &InvestorStatement{InvestorID: investorID, From: from, To: to, GeneratedAt: time.Now().UTC()}
+
+	var balance float64
+	err = db.QueryRowContext(ctx, "SELECT name, balance FROM investor WHERE id = $1", investorID).Scan(&statement.InvestorName, &balance)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("investor not found")
+		}
+		return nil, fmt.Errorf("failed to get investor: %w", err)
+	}
+
+	// Everything since the start of the statement, what happened after its end is taken off the current balance
+	rows, err := db.QueryContext(ctx, `SELECT time, kind, invoice_id, reference, funded, amount FROM (
+			SELECT created_at AS time, kind, '' AS invoice_id, id::text AS reference, 0 AS funded,
+				CASE WHEN kind = 'withdrawal' THEN -amount ELSE amount END AS amount
+			FROM ledger WHERE account_type = 'investor' AND account_id = $1 AND kind IN ('deposit', 'withdrawal')
+			UNION ALL SELECT created_at, 'bid_reservation', invoice_id::text, id::text, 0, -amount FROM bid WHERE investor_id = $1 AND created_at IS NOT NULL
+			UNION ALL SELECT closed_at, 'bid_refund', invoice_id::text, id::text, 0, amount FROM bid WHERE investor_id = $1 AND closed_at IS NOT NULL
+			UNION ALL SELECT created_at, 'trade', invoice_id::text, id::text, amount, 0 FROM trade WHERE investor_id = $1
+			UNION ALL SELECT created_at, 'fee', invoice_id::text, id::text, 0, -investor_fee FROM trade WHERE investor_id = $1 AND investor_fee > 0
+			UNION ALL SELECT repayment.created_at, 'repayment', repayment.invoice_id::text, repayment.id::text, 0, repayment_share.amount
+			FROM repayment_share JOIN repayment ON repayment.id = repayment_share.repayment_id WHERE repayment_share.investor_id = $1
+		) entry WHERE time >= $2 ORDER BY time, kind`, investorID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query statement entries: %w", err)
+	}
+	defer rows.Close()
+	var inRange, after float64
+	for rows.Next() {
+		var entry StatementEntry
+		if err := rows.Scan(&entry.Time, &entry.Kind, &entry.InvoiceID, &entry.Reference, &entry.Funded, &entry.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan statement entry: %w", err)
+		}
+		if !entry.Time.Before(statement.end()) {
+			after += entry.Amount
+			continue
+		}
+		inRange += entry.Amount
+		statement.Entries = append(statement.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read statement entries: %w", err)
+	}
+
+	statement.ClosingBalance = roundCents(balance - after)
+	statement.OpeningBalance = roundCents(statement.ClosingBalance - inRange)
+	running := statement.OpeningBalance
+	for i := range statement.Entries {
+		running += statement.Entries[i].Amount
+		statement.Entries[i].Balance = roundCents(running)
+	}
+	return statement, nil
+}
+
+// rows are the entries of the statement between its opening and closing balance
+func (s *InvestorStatement) rows() []StatementEntry {
+	rows := make([]StatementEntry, 0, len(s.Entries)+2)
+	rows = append(rows, StatementEntry{Time: s.From, Kind: EntryOpeningBalance, Balance: s.OpeningBalance})
+	rows = append(rows, s.Entries...)
+	return append(rows, StatementEntry{Time: s.end(), Kind: EntryClosingBalance, Balance: s.ClosingBalance})
+}
+
+// WriteStatement renders the statement in the format
+func WriteStatement(w io.Writer, format string, statement *InvestorStatement) error {
+	switch format {
+	case StatementCSV:
+		return writeStatementCSV(w, statement)
+	case StatementNDJSON:
+		return writeStatementNDJSON(w, statement)
+	case StatementHTML:
+		return statementTemplate.Execute(w, statement)
+	default:
+		return fmt.Errorf("unknown statement format %q", format)
+	}
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func writeStatementCSV(w io.Writer, statement *InvestorStatement) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"time", "kind", "invoice_id", "reference", "funded", "amount", "balance"})
+	for _, row := range statement.rows() {
+		out.Write([]string{row.Time.Format(time.RFC3339), row.Kind, row.InvoiceID, row.Reference,
+			formatAmount(row.Funded), formatAmount(row.Amount), formatAmount(row.Balance)})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func writeStatementNDJSON(w io.Writer, statement *InvestorStatement) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range statement.rows() {
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("failed to encode statement entry: %w", err)
+		}
+	}
+	return nil
+}
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"amount": formatAmount,
+	"day":    func(t time.Time) string { return t.Format(dueDateLayout) },
+	"time":   func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Statement {{.InvestorName}} {{day .From}} - {{day .To}}</title>
+<style>
+body { font-family: sans-serif; font-size: 12px; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+td.amount, th.amount { text-align: right; font-variant-numeric: tabular-nums; }
+tr.balance td { font-weight: bold; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Investor statement</h1>
+<p>{{.InvestorName}}<br>Investor {{.InvestorID}}<br>{{day .From}} to {{day .To}}<br>Generated {{time .GeneratedAt}} UTC</p>
+<table>
+<thead><tr><th>Time</th><th>Kind</th><th>Invoice</th><th>Reference</th><th class="amount">Funded</th><th class="amount">Amount</th><th class="amount">Balance</th></tr></thead>
+<tbody>
+<tr class="balance"><td>{{day .From}}</td><td>Opening balance</td><td></td><td></td><td></td><td></td><td class="amount">{{amount .OpeningBalance}}</td></tr>
+{{- range .Entries}}
+<tr><td>{{time .Time}}</td><td>{{.Kind}}</td><td>{{.InvoiceID}}</td><td>{{.Reference}}</td><td class="amount">{{if .Funded}}{{amount .Funded}}{{end}}</td><td class="amount">{{amount .Amount}}</td><td class="amount">{{amount .Balance}}</td></tr>
+{{- end}}
+<tr class="balance"><td>{{day .To}}</td><td>Closing balance</td><td></td><td></td><td></td><td></td><td class="amount">{{amount .ClosingBalance}}</td></tr>
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// statementSender sends what is written to it as chunks of at most documentChunkSize bytes, the first one
+// carrying the content type
+type statementSender struct {
+	stream      pb.InvoiceService_ExportInvestorStatementServer
+	contentType string
+}
+
+func (s *statementSender) Write(p []byte) (int, error) {
+	for sent := 0; sent < len(p); {
+		n := min(documentChunkSize, len(p)-sent)
+		if err := s.stream.Send(&pb.StatementChunk{ContentType: s.contentType, Data: p[sent : sent+n]}); err != nil {
+			return sent, err
+		}
+		s.contentType = ""
+		sent += n
+	}
+	return len(p), nil
+}
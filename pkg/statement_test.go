@@ -0,0 +1,205 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// statementStream keeps the chunks of an exported statement
+type statementStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*pb.StatementChunk
+}
+
+func (s *statementStream) Context() context.Context { return s.ctx }
+
+func (s *statementStream) Send(chunk *pb.StatementChunk) error {
+	s.chunks = append(s.chunks, proto.Clone(chunk).(*pb.StatementChunk))
+	return nil
+}
+
+func (s *statementStream) data() string {
+	var data []byte
+	for _, chunk := range s.chunks {
+		data = append(data, chunk.GetData()...)
+	}
+	return string(data)
+}
+
+func day(date string) time.Time {
+	t, _ := time.Parse(dueDateLayout, date)
+	return t
+}
+
+// expectStatement expects the queries of a statement of investor-id from 2024-03-01 whose investor holds 880 today.
+// The last entry, on 2024-04-02, is after the statement ends.
+func expectStatement(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT name, balance FROM investor WHERE id = \\$1").
+		WithArgs("investor-id").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "balance"}).AddRow("Investor Ltd", 880))
+	mock.ExpectQuery("SELECT time, kind, invoice_id, reference, funded, amount FROM (.+) entry WHERE time >= \\$2 ORDER BY time, kind").
+		WithArgs("investor-id", day("2024-03-01")).
+		WillReturnRows(sqlmock.NewRows([]string{"time", "kind", "invoice_id", "reference", "funded", "amount"}).
+			AddRow(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), EntryDeposit, "", "ledger-1", 0, 1000).
+			AddRow(time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), EntryBidReservation, "invoice-1", "bid-1", 0, -400).
+			AddRow(time.Date(2024, 3, 2, 11, 0, 0, 0, time.UTC), EntryBidReservation, "invoice-2", "bid-2", 0, -300).
+			AddRow(time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC), EntryBidRefund, "invoice-2", "bid-2", 0, 300).
+			AddRow(time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC), EntryFee, "invoice-1", "trade-1", 0, -20).
+			AddRow(time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC), EntryTrade, "invoice-1", "trade-1", 400, 0).
+			AddRow(time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC), EntryRepayment, "invoice-1", "repayment-1", 0, 450).
+			AddRow(time.Date(2024, 4, 2, 8, 0, 0, 0, time.UTC), EntryBidReservation, "invoice-3", "bid-3", 0, -500))
+}
+
+func TestGetInvestorStatement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	expectStatement(mock)
+
+	statement, err := GetInvestorStatement(context.Background(), db, "investor-id", day("2024-03-01"), day("2024-03-31"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Investor Ltd", statement.InvestorName)
+	// 880 today, 500 reserved since the statement ended
+	assert.Equal(t, 1380.0, statement.ClosingBalance)
+	assert.Equal(t, 350.0, statement.OpeningBalance)
+	var balances []float64
+	for _, entry := range statement.Entries {
+		balances = append(balances, entry.Balance)
+	}
+	assert.Equal(t, []float64{1350, 950, 650, 950, 930, 930, 1380}, balances)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateStatementRequest(t *testing.T) {
+	today := time.Date(2024, 4, 10, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		in   *pb.StatementRequest
+		err  string
+	}{
+		{"no investor", &pb.StatementRequest{From: "2024-03-01", Format: StatementCSV}, "investor id is required"},
+		{"unknown format", &pb.StatementRequest{InvestorId: "investor-id", From: "2024-03-01", Format: "pdf"}, `statement format "pdf" must be csv, ndjson or html`},
+		{"no from", &pb.StatementRequest{InvestorId: "investor-id", Format: StatementCSV}, `from date "" must be formatted as YYYY-MM-DD`},
+		{"to before from", &pb.StatementRequest{InvestorId: "investor-id", From: "2024-03-01", To: "2024-02-29", Format: StatementCSV}, "to date must not be before the from date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ValidateStatementRequest(tt.in, today)
+
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	from, to, err := ValidateStatementRequest(&pb.StatementRequest{InvestorId: "investor-id", From: "2024-03-01", Format: StatementHTML}, today)
+	assert.NoError(t, err)
+	assert.Equal(t, day("2024-03-01"), from)
+	assert.Equal(t, day("2024-04-10"), to)
+}
+
+func TestExportInvestorStatementRequiresInvestor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+	in := &pb.StatementRequest{InvestorId: "investor-id", From: "2024-03-01", To: "2024-03-31"}
+
+	// Nothing is read for anonymous callers or other investors
+	err = s.ExportInvestorStatement(in, &statementStream{ctx: context.Background()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	err = s.ExportInvestorStatement(in, &statementStream{ctx: asParty(PartyInvestor, "other-investor-id")})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Admins read every statement
+	mock.ExpectBegin()
+	expectStatement(mock)
+	mock.ExpectCommit()
+	err = s.ExportInvestorStatement(in, &statementStream{ctx: asParty(RoleAdmin, "")})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportInvestorStatement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectBegin()
+	expectStatement(mock)
+	mock.ExpectCommit()
+
+	stream := &statementStream{ctx: asParty(PartyInvestor, "investor-id")}
+	err = s.ExportInvestorStatement(&pb.StatementRequest{InvestorId: "investor-id", From: "2024-03-01", To: "2024-03-31"}, stream)
+
+	assert.NoError(t, err)
+	assert.Len(t, stream.chunks, 1)
+	assert.Equal(t, "text/csv; charset=utf-8", stream.chunks[0].ContentType)
+	lines := strings.Split(strings.TrimSpace(stream.data()), "\n")
+	assert.Equal(t, []string{
+		"time,kind,invoice_id,reference,funded,amount,balance",
+		"2024-03-01T00:00:00Z,opening_balance,,,0.00,0.00,350.00",
+		"2024-03-01T09:00:00Z,deposit,,ledger-1,0.00,1000.00,1350.00",
+		"2024-03-02T10:00:00Z,bid_reservation,invoice-1,bid-1,0.00,-400.00,950.00",
+		"2024-03-02T11:00:00Z,bid_reservation,invoice-2,bid-2,0.00,-300.00,650.00",
+		"2024-03-03T12:00:00Z,bid_refund,invoice-2,bid-2,0.00,300.00,950.00",
+		"2024-03-04T12:00:00Z,fee,invoice-1,trade-1,0.00,-20.00,930.00",
+		"2024-03-04T12:00:00Z,trade,invoice-1,trade-1,400.00,0.00,930.00",
+		"2024-03-31T23:59:00Z,repayment,invoice-1,repayment-1,0.00,450.00,1380.00",
+		"2024-04-01T00:00:00Z,closing_balance,,,0.00,0.00,1380.00",
+	}, lines)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteStatement(t *testing.T) {
+	statement := &InvestorStatement{InvestorID: "investor-id", InvestorName: "<Investor>", From: day("2024-03-01"), To: day("2024-03-31"),
+		OpeningBalance: 100, ClosingBalance: 150,
+		Entries: []StatementEntry{{Time: day("2024-03-05"), Kind: EntryRepayment, InvoiceID: "invoice-1", Reference: "repayment-1", Amount: 50, Balance: 150}}}
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteStatement(&out, StatementNDJSON, statement))
+	var kinds []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry StatementEntry
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		kinds = append(kinds, entry.Kind)
+	}
+	assert.Equal(t, []string{EntryOpeningBalance, EntryRepayment, EntryClosingBalance}, kinds)
+
+	out.Reset()
+	assert.NoError(t, WriteStatement(&out, StatementHTML, statement))
+	assert.Contains(t, out.String(), "&lt;Investor&gt;")
+	assert.Contains(t, out.String(), `<td class="amount">150.00</td></tr>`)
+
+	assert.EqualError(t, WriteStatement(&out, "pdf", statement), `unknown statement format "pdf"`)
+}
+
+func TestStatementSenderChunks(t *testing.T) {
+	stream := &statementStream{}
+	out := bufio.NewWriterSize(&statementSender{stream: stream, contentType: "text/csv"}, documentChunkSize)
+
+	data := strings.Repeat("x", 2*documentChunkSize+10)
+	_, err := out.WriteString(data)
+	assert.NoError(t, err)
+	assert.NoError(t, out.Flush())
+
+	assert.Len(t, stream.chunks, 3)
+	assert.Equal(t, "text/csv", stream.chunks[0].ContentType)
+	assert.Empty(t, stream.chunks[1].ContentType)
+	assert.Equal(t, data, stream.data())
+}
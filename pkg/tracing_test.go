@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestCloseBidsSpans(t *testing.T) {
+	recorder := setupSpanRecorder(t)
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	bid := &pb.Bid{InvestorId: "investor-id", InvoiceId: "invoice-id", Amount: 100}
+
+	mock.ExpectQuery("UPDATE bid SET status = 'closed'").WithArgs(bid.InvoiceId).
+		WillReturnRows(sqlmock.NewRows([]string{"investor_id", "amount"}).AddRow("other-investor-id", 100.0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE investor SET balance = balance + $1 WHERE id = $2")).
+		WithArgs(100.0, "other-investor-id").
+		WillReturnError(assert.AnError)
+
+	err = CloseBids(context.Background(), db, bid)
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	// The nested helper ends first
+	assert.Equal(t, "IncreasePreviousInvestorsBalance", spans[0].Name())
+	assert.Equal(t, "CloseBids", spans[1].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	recorder := setupSpanRecorder(t)
+	_, err := SetupTracing(context.Background(), "none", "", "")
+	assert.NoError(t, err)
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).AddRow("1", "Issuer Name", 100.0)
+	mock.ExpectQuery("SELECT id, name, balance FROM issuer WHERE id = \\$1").WithArgs("1").WillReturnRows(rows)
+	expectIssuerRisk(mock, "1", RiskInputs{})
+
+	// Serve over an in-memory listener
+	lis := bufconn.Listen(1024 * 1024)
+	s, _, _, err := SetupServer(db, &cfg.Config{})
+	assert.NoError(t, err)
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err = pb.NewInvoiceServiceClient(conn).GetIssuer(ctx, &pb.Issuer{Id: "1"})
+	assert.NoError(t, err)
+	s.GracefulStop()
+
+	spans := recorder.Ended()
+	assert.NotEmpty(t, spans)
+	// The server span ends after the spans of the queries it ran
+	server := spans[len(spans)-1]
+	assert.Equal(t, "invoice.InvoiceService/GetIssuer", server.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+}
+
+func TestSetupTracingUnknownExporter(t *testing.T) {
+	_, err := SetupTracing(context.Background(), "zipkin", "", "")
+	assert.Error(t, err)
+}
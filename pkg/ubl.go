@@ -0,0 +1,352 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidEInvoice is wrapped by ImportError
+var ErrInvalidEInvoice = errors.New("invalid e-invoice")
+
+// ublCommercialInvoice is the UNCL 1001 type code of commercial invoices, the only ones that can be financed
+const ublCommercialInvoice = "380"
+
+// supplierEndpointField is the field of an e-invoice the issuer is found by
+const supplierEndpointField = "cac:AccountingSupplierParty/cac:Party/cbc:EndpointID"
+
+// FieldViolation is a problem with one field of an imported e-invoice, Field being its path in the XML
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ImportError lists every problem found in an e-invoice, so they can all be fixed at once
+type ImportError struct {
+	Violations []FieldViolation
+}
+
+func (e *ImportError) add(field, format string, args ...any) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+func (e *ImportError) Error() string {
+	problems := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		problems[i] = v.Field + " " + v.Description
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidEInvoice, strings.Join(problems, "; "))
+}
+
+func (e *ImportError) Unwrap() error {
+	return ErrInvalidEInvoice
+}
+
+// GRPCStatus is used by grpc to turn the error into a status
+func (e *ImportError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(e.Violations))
+	for i, v := range e.Violations {
+		violations[i] = &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description}
+	}
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+// The parts of a UBL 2.1 invoice that are mapped to an invoice. Elements are matched by their local name, the
+// root by its namespace too.
+type ublInvoice struct {
+	XMLName              xml.Name         `xml:"urn:oasis:names:specification:ubl:schema:xsd:Invoice-2 Invoice"`
+	ID                   string           `xml:"ID"`
+	IssueDate            string           `xml:"IssueDate"`
+	DueDate              string           `xml:"DueDate"`
+	InvoiceTypeCode      string           `xml:"InvoiceTypeCode"`
+	DocumentCurrencyCode string           `xml:"DocumentCurrencyCode"`
+	Supplier             ublParty         `xml:"AccountingSupplierParty>Party"`
+	Customer             ublParty         `xml:"AccountingCustomerParty>Party"`
+	TaxTotals            []ublAmount      `xml:"TaxTotal>TaxAmount"`
+	Totals               ublMonetaryTotal `xml:"LegalMonetaryTotal"`
+	Lines                []ublInvoiceLine `xml:"InvoiceLine"`
+}
+
+type ublParty struct {
+	EndpointID       ublIdentifier `xml:"EndpointID"`
+	Name             string        `xml:"PartyName>Name"`
+	RegistrationName string        `xml:"PartyLegalEntity>RegistrationName"`
+	CompanyID        string        `xml:"PartyLegalEntity>CompanyID"`
+}
+
+type ublIdentifier struct {
+	Value    string `xml:",chardata"`
+	SchemeID string `xml:"schemeID,attr"`
+}
+
+type ublAmount struct {
+	Value      string `xml:",chardata"`
+	CurrencyID string `xml:"currencyID,attr"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtensionAmount   ublAmount `xml:"LineExtensionAmount"`
+	TaxExclusiveAmount    ublAmount `xml:"TaxExclusiveAmount"`
+	TaxInclusiveAmount    ublAmount `xml:"TaxInclusiveAmount"`
+	AllowanceTotalAmount  ublAmount `xml:"AllowanceTotalAmount"`
+	ChargeTotalAmount     ublAmount `xml:"ChargeTotalAmount"`
+	PrepaidAmount         ublAmount `xml:"PrepaidAmount"`
+	PayableRoundingAmount ublAmount `xml:"PayableRoundingAmount"`
+	PayableAmount         ublAmount `xml:"PayableAmount"`
+}
+
+type ublInvoiceLine struct {
+	InvoicedQuantity    string    `xml:"InvoicedQuantity"`
+	LineExtensionAmount ublAmount `xml:"LineExtensionAmount"`
+	Name                string    `xml:"Item>Name"`
+	TaxPercent          string    `xml:"Item>ClassifiedTaxCategory>Percent"`
+	PriceAmount         ublAmount `xml:"Price>PriceAmount"`
+	BaseQuantity        string    `xml:"Price>BaseQuantity"`
+}
+
+// ublParser converts the fields of an e-invoice, collecting the violations instead of stopping at the first one
+type ublParser struct {
+	currency string
+	err      ImportError
+}
+
+func (p *ublParser) required(field, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		p.err.add(field, "is required")
+	}
+	return value
+}
+
+func (p *ublParser) date(field, value string) string {
+	value = p.required(field, value)
+	if _, err := time.Parse(dueDateLayout, value); value != "" && err != nil {
+		p.err.add(field, "%q must be formatted as YYYY-MM-DD", value)
+	}
+	return value
+}
+
+func (p *ublParser) number(field, value string) float64 {
+	value = p.required(field, value)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.err.add(field, "%q is not a number", value)
+	}
+	return n
+}
+
+func (p *ublParser) amount(field string, a ublAmount) float64 {
+	if a.CurrencyID != "" && p.currency != "" && a.CurrencyID != p.currency {
+		p.err.add(field, "currency %s doesn't match the document currency %s", a.CurrencyID, p.currency)
+	}
+	return p.number(field, a.Value)
+}
+
+// optionalAmount is 0 when the element is missing
+func (p *ublParser) optionalAmount(field string, a ublAmount) float64 {
+	if strings.TrimSpace(a.Value) == "" {
+		return 0
+	}
+	return p.amount(field, a)
+}
+
+// match reports a violation when an amount of the document doesn't match what its parts add up to
+func (p *ublParser) match(field string, amount, expected float64, what string) {
+	if math.Abs(amount-expected) > balanceTolerance {
+		p.err.add(field, "%.2f doesn't match %s of %.2f", amount, what, expected)
+	}
+}
+
+// peppolID is the participant identifier of an endpoint, its scheme and id
+func peppolID(endpoint ublIdentifier) string {
+	id := strings.TrimSpace(endpoint.Value)
+	if scheme := strings.TrimSpace(endpoint.SchemeID); scheme != "" && id != "" {
+		return scheme + ":" + id
+	}
+	return id
+}
+
+// ParseUBLInvoice maps a UBL 2.1 / Peppol BIS Billing 3.0 invoice to an invoice: the customer party is the debtor,
+// the lines are the line items, the tax exclusive amount and tax total the invoice's totals and the payable amount
+// the face value. The totals have to add up. It also returns
+// the Peppol id of the supplier party, which identifies the issuer. Problems are reported as an ImportError.
+func ParseUBLInvoice(document []byte) (invoice *pb.Invoice, supplier string, err error) {
+	var doc ublInvoice
+	if err := xml.Unmarshal(document, &doc); err != nil {
+		return nil, "", &ImportError{Violations: []FieldViolation{{Field: "Invoice", Description: err.Error()}}}
+	}
+	p := &ublParser{currency: strings.TrimSpace(doc.DocumentCurrencyCode)}
+
+	invoice = &pb.Invoice{
+		InvoiceNumber: p.required("cbc:ID", doc.ID),
+		IssueDate:     p.date("cbc:IssueDate", doc.IssueDate),
+		DueDate:       p.date("cbc:DueDate", doc.DueDate),
+		Currency:      p.required("cbc:DocumentCurrencyCode", doc.DocumentCurrencyCode),
+	}
+	if code := strings.TrimSpace(doc.InvoiceTypeCode); code != "" && code != ublCommercialInvoice {
+		p.err.add("cbc:InvoiceTypeCode", "%s is not a commercial invoice (%s)", code, ublCommercialInvoice)
+	}
+
+	supplier = peppolID(doc.Supplier.EndpointID)
+	if supplier == "" {
+		p.err.add(supplierEndpointField, "is required")
+	}
+	invoice.DebtorName = strings.TrimSpace(doc.Customer.RegistrationName)
+	if invoice.DebtorName == "" {
+		invoice.DebtorName = p.required("cac:AccountingCustomerParty/cac:Party/cac:PartyLegalEntity/cbc:RegistrationName", doc.Customer.Name)
+	}
+	invoice.DebtorReference = strings.TrimSpace(doc.Customer.CompanyID)
+	if invoice.DebtorReference == "" {
+		invoice.DebtorReference = peppolID(doc.Customer.EndpointID)
+	}
+
+	if len(doc.Lines) == 0 {
+		p.err.add("cac:InvoiceLine", "is required")
+	}
+	var linesTotal float64
+	for i, line := range doc.Lines {
+		field := fmt.Sprintf("cac:InvoiceLine[%d]/", i+1)
+		quantity := p.number(field+"cbc:InvoicedQuantity", line.InvoicedQuantity)
+		net := p.amount(field+"cbc:LineExtensionAmount", line.LineExtensionAmount)
+		price := p.amount(field+"cac:Price/cbc:PriceAmount", line.PriceAmount)
+		if line.BaseQuantity != "" {
+			if base := p.number(field+"cac:Price/cbc:BaseQuantity", line.BaseQuantity); base > 0 {
+				price /= base
+			}
+		}
+		var taxRate float64
+		if line.TaxPercent != "" {
+			taxRate = p.number(field+"cac:Item/cac:ClassifiedTaxCategory/cbc:Percent", line.TaxPercent)
+		}
+		p.match(field+"cbc:LineExtensionAmount", net, roundCents(quantity*price), "the quantity times the price")
+		linesTotal += net
+		invoice.LineItems = append(invoice.LineItems, &pb.LineItem{
+			Description: p.required(field+"cac:Item/cbc:Name", line.Name),
+			Quantity:    float32(quantity),
+			UnitPrice:   float32(price),
+			TaxRate:     float32(taxRate),
+		})
+	}
+
+	// Only the tax total in the document currency counts, a second one may be in the tax currency
+	var tax float64
+	taxFound := false
+	for _, amount := range doc.TaxTotals {
+		if amount.CurrencyID == "" || amount.CurrencyID == p.currency {
+			tax += p.number("cac:TaxTotal/cbc:TaxAmount", amount.Value)
+			taxFound = true
+		}
+	}
+	if !taxFound {
+		p.err.add("cac:TaxTotal/cbc:TaxAmount", "is required")
+	}
+
+	const totals = "cac:LegalMonetaryTotal/"
+	lineExtension := p.amount(totals+"cbc:LineExtensionAmount", doc.Totals.LineExtensionAmount)
+	taxExclusive := p.amount(totals+"cbc:TaxExclusiveAmount", doc.Totals.TaxExclusiveAmount)
+	taxInclusive := p.amount(totals+"cbc:TaxInclusiveAmount", doc.Totals.TaxInclusiveAmount)
+	payable := p.amount(totals+"cbc:PayableAmount", doc.Totals.PayableAmount)
+	// Our invoices have no document level allowances, charges or prepayments to map them to
+	for _, unsupported := range []struct {
+		field  string
+		amount ublAmount
+	}{
+		{"cbc:AllowanceTotalAmount", doc.Totals.AllowanceTotalAmount},
+		{"cbc:ChargeTotalAmount", doc.Totals.ChargeTotalAmount},
+		{"cbc:PrepaidAmount", doc.Totals.PrepaidAmount},
+	} {
+		if p.optionalAmount(totals+unsupported.field, unsupported.amount) != 0 {
+			p.err.add(totals+unsupported.field, "is not supported")
+		}
+	}
+	rounding := p.optionalAmount(totals+"cbc:PayableRoundingAmount", doc.Totals.PayableRoundingAmount)
+	p.match(totals+"cbc:LineExtensionAmount", lineExtension, linesTotal, "the sum of the lines")
+	p.match(totals+"cbc:TaxExclusiveAmount", taxExclusive, lineExtension, "the line extension amount")
+	p.match(totals+"cbc:TaxInclusiveAmount", taxInclusive, taxExclusive+tax, "the tax exclusive amount plus tax")
+	p.match(totals+"cbc:PayableAmount", payable, taxInclusive+rounding, "the tax inclusive amount")
+	invoice.NetTotal, invoice.TaxTotal, invoice.FaceValue = float32(taxExclusive), float32(tax), float32(payable)
+
+	if len(p.err.Violations) > 0 {
+		return nil, "", &p.err
+	}
+	return invoice, supplier, nil
+}
+
+// ResolveSupplierIssuer returns the issuer registered with the supplier's Peppol id. When an issuer id is given,
+// an issuer without a Peppol id is registered with the supplier's, so its later imports don't need the id.
+func ResolveSupplierIssuer(ctx context.Context, db dbtx, supplier, issuerID string) (id string, err error) {
+	ctx, span := startSpan(ctx, "ResolveSupplierIssuer", nil)
+	defer func() { endSpan(span, err) }()
+	if issuerID == "" {
+		err = db.QueryRowContext(ctx, "SELECT id FROM issuer WHERE peppol_id = $1", supplier).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", &ImportError{Violations: []FieldViolation{{Field: supplierEndpointField,
+				Description: fmt.Sprintf("%s is not registered to an issuer, import with the issuer id to register it", supplier)}}}
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to find issuer: %w", err)
+		}
+		return id, nil
+	}
+
+	err = db.QueryRowContext(ctx, `UPDATE issuer SET peppol_id = $2 WHERE id = $1 AND (peppol_id IS NULL OR peppol_id = $2)
+		RETURNING id`, issuerID, supplier).Scan(&id)
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", &ImportError{Violations: []FieldViolation{{Field: supplierEndpointField,
+			Description: fmt.Sprintf("%s doesn't belong to issuer %s", supplier, issuerID)}}}
+	case errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "issuer_peppol_id":
+		return "", &ImportError{Violations: []FieldViolation{{Field: supplierEndpointField,
+			Description: fmt.Sprintf("%s is registered to another issuer", supplier)}}}
+	case err != nil:
+		return "", fmt.Errorf("failed to register issuer's peppol id: %w", err)
+	}
+	return id, nil
+}
+
+// FindImportedInvoice returns the id of the invoice imported from the document with the given digest, if any
+func FindImportedInvoice(ctx context.Context, db dbtx, sha256 string) (id string, err error) {
+	ctx, span := startSpan(ctx, "FindImportedInvoice", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, "SELECT id FROM invoice WHERE source_sha256 = $1", sha256).Scan(&id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to find imported invoice: %w", err)
+	}
+	return id, nil
+}
+
+// SetInvoiceSource records the digest of the document an invoice was imported from
+func SetInvoiceSource(ctx context.Context, db dbtx, invoiceID, sha256 string) (err error) {
+	ctx, span := startSpan(ctx, "SetInvoiceSource", nil)
+	defer func() { endSpan(span, err) }()
+	_, err = db.ExecContext(ctx, "UPDATE invoice SET source_sha256 = $1 WHERE id = $2", sha256, invoiceID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "invoice_source_sha256" {
+			return status.Error(codes.AlreadyExists, "document was already imported")
+		}
+		return fmt.Errorf("failed to record invoice source: %w", err)
+	}
+	return nil
+}
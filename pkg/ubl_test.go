@@ -0,0 +1,263 @@
+package pkg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testUBLInvoice is a Peppol BIS Billing 3.0 invoice, with a second tax total in the supplier's tax currency
+const testUBLInvoice = `<?xml version="1.0" encoding="UTF-8"?>
+<Invoice xmlns="urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	xmlns:cac="urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	xmlns:cbc="urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2">
+	<cbc:CustomizationID>urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0</cbc:CustomizationID>
+	<cbc:ProfileID>urn:fdc:peppol.eu:2017:poacc:billing:01:1.0</cbc:ProfileID>
+	<cbc:ID>INV-2024-042</cbc:ID>
+	<cbc:IssueDate>2024-03-01</cbc:IssueDate>
+	<cbc:DueDate>2024-04-30</cbc:DueDate>
+	<cbc:InvoiceTypeCode>380</cbc:InvoiceTypeCode>
+	<cbc:DocumentCurrencyCode>EUR</cbc:DocumentCurrencyCode>
+	<cbc:TaxCurrencyCode>SEK</cbc:TaxCurrencyCode>
+	<cac:AccountingSupplierParty>
+		<cac:Party>
+			<cbc:EndpointID schemeID="0088">7300010000001</cbc:EndpointID>
+			<cac:PartyName><cbc:Name>Supplier AB</cbc:Name></cac:PartyName>
+			<cac:PartyLegalEntity><cbc:RegistrationName>Supplier AB</cbc:RegistrationName></cac:PartyLegalEntity>
+		</cac:Party>
+	</cac:AccountingSupplierParty>
+	<cac:AccountingCustomerParty>
+		<cac:Party>
+			<cbc:EndpointID schemeID="0007">5567321707</cbc:EndpointID>
+			<cac:PartyName><cbc:Name>Buyer</cbc:Name></cac:PartyName>
+			<cac:PartyLegalEntity>
+				<cbc:RegistrationName>Buyer Ltd</cbc:RegistrationName>
+				<cbc:CompanyID>GB123456</cbc:CompanyID>
+			</cac:PartyLegalEntity>
+		</cac:Party>
+	</cac:AccountingCustomerParty>
+	<cac:TaxTotal>
+		<cbc:TaxAmount currencyID="EUR">130.00</cbc:TaxAmount>
+		<cac:TaxSubtotal>
+			<cbc:TaxableAmount currencyID="EUR">650.00</cbc:TaxableAmount>
+			<cbc:TaxAmount currencyID="EUR">130.00</cbc:TaxAmount>
+		</cac:TaxSubtotal>
+	</cac:TaxTotal>
+	<cac:TaxTotal>
+		<cbc:TaxAmount currencyID="SEK">1482.00</cbc:TaxAmount>
+	</cac:TaxTotal>
+	<cac:LegalMonetaryTotal>
+		<cbc:LineExtensionAmount currencyID="EUR">750.00</cbc:LineExtensionAmount>
+		<cbc:TaxExclusiveAmount currencyID="EUR">750.00</cbc:TaxExclusiveAmount>
+		<cbc:TaxInclusiveAmount currencyID="EUR">880.00</cbc:TaxInclusiveAmount>
+		<cbc:PayableAmount currencyID="EUR">880.00</cbc:PayableAmount>
+	</cac:LegalMonetaryTotal>
+	<cac:InvoiceLine>
+		<cbc:ID>1</cbc:ID>
+		<cbc:InvoicedQuantity unitCode="HUR">10</cbc:InvoicedQuantity>
+		<cbc:LineExtensionAmount currencyID="EUR">650.00</cbc:LineExtensionAmount>
+		<cac:Item>
+			<cbc:Name>Consulting</cbc:Name>
+			<cac:ClassifiedTaxCategory><cbc:ID>S</cbc:ID><cbc:Percent>20</cbc:Percent></cac:ClassifiedTaxCategory>
+		</cac:Item>
+		<cac:Price><cbc:PriceAmount currencyID="EUR">65.00</cbc:PriceAmount></cac:Price>
+	</cac:InvoiceLine>
+	<cac:InvoiceLine>
+		<cbc:ID>2</cbc:ID>
+		<cbc:InvoicedQuantity unitCode="C62">4</cbc:InvoicedQuantity>
+		<cbc:LineExtensionAmount currencyID="EUR">100.00</cbc:LineExtensionAmount>
+		<cac:Item>
+			<cbc:Name>Books</cbc:Name>
+			<cac:ClassifiedTaxCategory><cbc:ID>Z</cbc:ID><cbc:Percent>0</cbc:Percent></cac:ClassifiedTaxCategory>
+		</cac:Item>
+		<cac:Price><cbc:PriceAmount currencyID="EUR">250.00</cbc:PriceAmount><cbc:BaseQuantity>10</cbc:BaseQuantity></cac:Price>
+	</cac:InvoiceLine>
+</Invoice>`
+
+const testSupplier = "0088:7300010000001"
+
+func TestParseUBLInvoice(t *testing.T) {
+	invoice, supplier, err := ParseUBLInvoice([]byte(testUBLInvoice))
+
+	assert.NoError(t, err)
+	assert.Equal(t, testSupplier, supplier)
+	expected := &pb.Invoice{
+		InvoiceNumber: "INV-2024-042", IssueDate: "2024-03-01", DueDate: "2024-04-30", Currency: "EUR",
+		DebtorName: "Buyer Ltd", DebtorReference: "GB123456", NetTotal: 750, TaxTotal: 130, FaceValue: 880,
+		LineItems: []*pb.LineItem{
+			{Description: "Consulting", Quantity: 10, UnitPrice: 65, TaxRate: 20},
+			{Description: "Books", Quantity: 4, UnitPrice: 25},
+		},
+	}
+	assert.True(t, proto.Equal(expected, invoice), "got %v", invoice)
+}
+
+func TestPrepareImportedInvoiceKeepsDocumentTotals(t *testing.T) {
+	// The tax is rounded once for the document, 6.01 rather than 2.00 + 4.00 per line, and the payable amount is
+	// rounded to whole euros
+	document := strings.NewReplacer(
+		`<cbc:InvoicedQuantity unitCode="HUR">10<`, `<cbc:InvoicedQuantity unitCode="HUR">1<`,
+		`<cbc:LineExtensionAmount currencyID="EUR">650.00<`, `<cbc:LineExtensionAmount currencyID="EUR">10.01<`,
+		`<cbc:PriceAmount currencyID="EUR">65.00<`, `<cbc:PriceAmount currencyID="EUR">10.01<`,
+		`<cbc:InvoicedQuantity unitCode="C62">4<`, `<cbc:InvoicedQuantity unitCode="C62">2<`,
+		`<cbc:LineExtensionAmount currencyID="EUR">100.00<`, `<cbc:LineExtensionAmount currencyID="EUR">20.02<`,
+		`<cbc:ID>Z</cbc:ID><cbc:Percent>0<`, `<cbc:ID>S</cbc:ID><cbc:Percent>20<`,
+		`<cbc:PriceAmount currencyID="EUR">250.00<`, `<cbc:PriceAmount currencyID="EUR">100.10<`,
+		`<cbc:TaxableAmount currencyID="EUR">650.00<`, `<cbc:TaxableAmount currencyID="EUR">30.03<`,
+		`<cbc:TaxAmount currencyID="EUR">130.00<`, `<cbc:TaxAmount currencyID="EUR">6.01<`,
+		`<cbc:LineExtensionAmount currencyID="EUR">750.00<`, `<cbc:LineExtensionAmount currencyID="EUR">30.03<`,
+		`<cbc:TaxExclusiveAmount currencyID="EUR">750.00<`, `<cbc:TaxExclusiveAmount currencyID="EUR">30.03<`,
+		`<cbc:TaxInclusiveAmount currencyID="EUR">880.00<`, `<cbc:TaxInclusiveAmount currencyID="EUR">36.04<`,
+		`<cbc:PayableAmount currencyID="EUR">880.00<`,
+		`<cbc:PayableRoundingAmount currencyID="EUR">-0.04</cbc:PayableRoundingAmount><cbc:PayableAmount currencyID="EUR">36.00<`,
+	).Replace(testUBLInvoice)
+
+	invoice, _, err := ParseUBLInvoice([]byte(document))
+	assert.NoError(t, err)
+	invoice.Price = 30
+
+	assert.NoError(t, PrepareImportedInvoice(invoice, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, float32(30.03), invoice.NetTotal)
+	assert.Equal(t, float32(6.01), invoice.TaxTotal)
+	assert.Equal(t, float32(36), invoice.FaceValue)
+	assert.Equal(t, float32(2), invoice.LineItems[0].TaxAmount)
+	// Checked per line the invoice would not add up
+	assert.EqualError(t, PrepareInvoice(invoice, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)), "face value 36.00 doesn't match the line items' total of 36.03")
+}
+
+func TestParseUBLInvoiceViolations(t *testing.T) {
+	document := strings.NewReplacer(
+		"<cbc:DueDate>2024-04-30</cbc:DueDate>", "<cbc:DueDate>30/04/2024</cbc:DueDate>",
+		`<cbc:PayableAmount currencyID="EUR">880.00`, `<cbc:PayableAmount currencyID="USD">880.00`,
+		`<cbc:InvoicedQuantity unitCode="HUR">10`, `<cbc:InvoicedQuantity unitCode="HUR">11`,
+		"<cbc:RegistrationName>Buyer Ltd</cbc:RegistrationName>", "",
+		"<cac:PartyName><cbc:Name>Buyer</cbc:Name></cac:PartyName>", "",
+	).Replace(testUBLInvoice)
+
+	_, _, err := ParseUBLInvoice([]byte(document))
+
+	assert.ErrorIs(t, err, ErrInvalidEInvoice)
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		var fields []string
+		for _, v := range st.Details()[0].(*errdetails.BadRequest).GetFieldViolations() {
+			fields = append(fields, v.GetField())
+		}
+		assert.Equal(t, []string{
+			"cbc:DueDate",
+			"cac:AccountingCustomerParty/cac:Party/cac:PartyLegalEntity/cbc:RegistrationName",
+			"cac:InvoiceLine[1]/cbc:LineExtensionAmount",
+			"cac:LegalMonetaryTotal/cbc:PayableAmount",
+		}, fields)
+	}
+	assert.ErrorContains(t, err, "cac:InvoiceLine[1]/cbc:LineExtensionAmount 650.00 doesn't match the quantity times the price of 715.00")
+	assert.ErrorContains(t, err, "cac:LegalMonetaryTotal/cbc:PayableAmount currency USD doesn't match the document currency EUR")
+}
+
+func TestParseUBLInvoiceRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{"credit note", strings.ReplaceAll(testUBLInvoice, "<Invoice", "<CreditNote"), "Invoice expected element type <Invoice> but have <CreditNote>"},
+		{"other namespace", strings.Replace(testUBLInvoice, "xsd:Invoice-2", "xsd:Order-2", 1), "Invoice expected element <Invoice> in name space urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"},
+		{"not xml", "%PDF-1.7", "Invoice EOF"},
+		{"not a commercial invoice", strings.Replace(testUBLInvoice, ">380<", ">386<", 1), "cbc:InvoiceTypeCode 386 is not a commercial invoice (380)"},
+		{"totals", strings.Replace(testUBLInvoice, `<cbc:TaxInclusiveAmount currencyID="EUR">880.00`, `<cbc:TaxInclusiveAmount currencyID="EUR">890.00`, 1),
+			"cac:LegalMonetaryTotal/cbc:TaxInclusiveAmount 890.00 doesn't match the tax exclusive amount plus tax of 880.00"},
+		{"prepaid", strings.Replace(testUBLInvoice, "<cbc:PayableAmount", `<cbc:PrepaidAmount currencyID="EUR">80.00</cbc:PrepaidAmount><cbc:PayableAmount`, 1),
+			"cac:LegalMonetaryTotal/cbc:PrepaidAmount is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseUBLInvoice([]byte(tt.document))
+
+			assert.ErrorIs(t, err, ErrInvalidEInvoice)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestImportInvoice(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+	source := sha256Hex([]byte(testUBLInvoice))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM invoice WHERE source_sha256 = \\$1").WithArgs(source).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("UPDATE issuer SET peppol_id = \\$2").WithArgs("issuer-id", testSupplier).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("issuer-id"))
+	expectKycStatus(mock, PartyIssuer, "issuer-id", KycVerified)
+	expectLockIssuer(mock, "issuer-id")
+	expectIssuerRisk(mock, "issuer-id", RiskInputs{})
+	mock.ExpectQuery("INSERT INTO invoice").
+		WithArgs("issuer-id", "open", "", float32(800), float32(880), "2024-04-30", "INV-2024-042", "Buyer Ltd", "GB123456", "2024-03-01", "EUR",
+			float32(750), float32(130), "", float32(0), float32(0), float32(0), int32(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invoice-id"))
+	mock.ExpectExec("INSERT INTO invoice_line_item").WithArgs("invoice-id", 1, "Consulting", float32(10), float32(65), float32(20), float32(650), float32(130)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO invoice_line_item").WithArgs("invoice-id", 2, "Books", float32(4), float32(25), float32(0), float32(100), float32(0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE invoice SET source_sha256 = \\$1 WHERE id = \\$2").WithArgs(source, "invoice-id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	invoice, err := s.ImportInvoice(context.Background(), &pb.InvoiceImport{Document: []byte(testUBLInvoice), Price: 800, IssuerId: "issuer-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "invoice-id", invoice.Id)
+	assert.Equal(t, "issuer-id", invoice.IssuerId)
+	assert.Equal(t, float32(880), invoice.FaceValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImportInvoiceAlreadyImported(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, risk: DefaultRiskModel()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM invoice WHERE source_sha256 = \\$1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("invoice-id"))
+	mock.ExpectRollback()
+
+	invoice, err := s.ImportInvoice(context.Background(), &pb.InvoiceImport{Document: []byte(testUBLInvoice), Price: 800})
+
+	assert.Nil(t, invoice)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.ErrorContains(t, err, "already imported as invoice invoice-id")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveSupplierIssuer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id FROM issuer WHERE peppol_id = \\$1").WithArgs(testSupplier).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("issuer-id"))
+	mock.ExpectQuery("SELECT id FROM issuer WHERE peppol_id = \\$1").WithArgs(testSupplier).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("UPDATE issuer SET peppol_id = \\$2").WithArgs("other-id", testSupplier).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	id, err := ResolveSupplierIssuer(context.Background(), db, testSupplier, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "issuer-id", id)
+
+	_, err = ResolveSupplierIssuer(context.Background(), db, testSupplier, "")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "is not registered to an issuer")
+
+	_, err = ResolveSupplierIssuer(context.Background(), db, testSupplier, "other-id")
+	assert.ErrorContains(t, err, "0088:7300010000001 doesn't belong to issuer other-id")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,540 @@
+package pkg
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Events pushed to the webhook endpoints of a party
+const (
+	// WebhookEventOutbid goes to the investors whose best bid was just beaten
+	WebhookEventOutbid = "bid.outbid"
+	// WebhookEventFunded goes to the issuer when a trade funds its invoice
+	WebhookEventFunded = "invoice.funded"
+	// WebhookEventSettled goes to the investor who won the trade
+	WebhookEventSettled = "trade.settled"
+	// WebhookEventRepaid goes to the issuer and the investors of the invoice on every repayment
+	WebhookEventRepaid = "invoice.repaid"
+	// WebhookEventTest is the sample event of TestWebhookEndpoint, endpoints can't subscribe to it
+	WebhookEventTest = "webhook.test"
+)
+
+var webhookEventTypes = []string{WebhookEventOutbid, WebhookEventFunded, WebhookEventSettled, WebhookEventRepaid}
+
+// Statuses of a webhook endpoint, deleted endpoints are disabled so their deliveries are kept
+const (
+	WebhookEndpointActive   = "active"
+	WebhookEndpointDisabled = "disabled"
+)
+
+// Statuses of a webhook delivery
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryDead deliveries failed every attempt and have a dead letter
+	WebhookDeliveryDead = "dead"
+	// WebhookDeliveryFailed is only reported by test deliveries, which aren't retried
+	WebhookDeliveryFailed = "failed"
+)
+
+// Headers of a webhook request. The signature is the hex HMAC-SHA256 of the timestamp, a dot and the body,
+// keyed with the endpoint's secret. Receivers should reject old timestamps so a captured request can't be replayed.
+const (
+	WebhookIDHeader        = "Webhook-Id"
+	WebhookEventHeader     = "Webhook-Event"
+	WebhookTimestampHeader = "Webhook-Timestamp"
+	WebhookSignatureHeader = "Webhook-Signature"
+)
+
+// webhookBatchSize is the number of due deliveries sent on every tick
+const webhookBatchSize = 100
+
+// maxWebhookBackoff caps the delay between two attempts of a delivery
+const maxWebhookBackoff = 24 * time.Hour
+
+// webhookEndpointColumns are scanned by scanWebhookEndpoint, the secret is never listed
+const webhookEndpointColumns = `id, party_type, party_id, url, event_types, status, to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+// webhookDeliveryColumns are scanned by scanWebhookDelivery
+const webhookDeliveryColumns = `webhook_delivery.id, webhook_delivery.endpoint_id, webhook_delivery.event_type, webhook_delivery.payload,
+	webhook_delivery.status, webhook_delivery.attempts, webhook_delivery.response_code, webhook_delivery.last_error,
+	to_char(webhook_delivery.next_attempt_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), to_char(webhook_delivery.created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`
+
+// webhookDeadLetterColumns are scanned by scanWebhookDeadLetter
+const webhookDeadLetterColumns = `id, delivery_id, endpoint_id, event_type, payload, attempts, last_error,
+	to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), COALESCE(to_char(replayed_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"'), '')`
+
+// WebhookEvent is an event for the endpoints of a party, Payload is the JSON body sent to them
+type WebhookEvent struct {
+	Type      string
+	PartyType string
+	PartyID   string
+	Payload   string
+}
+
+// webhookPayload is the body of every webhook request
+type webhookPayload struct {
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewWebhookEvent builds the event, data is sent as protojson with the proto field names
+func NewWebhookEvent(eventType, partyType, partyID string, data proto.Message, now time.Time) (WebhookEvent, error) {
+	raw, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(data)
+	if err != nil {
+		return WebhookEvent{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	body, err := json.Marshal(webhookPayload{Type: eventType, CreatedAt: now.UTC().Format(time.RFC3339), Data: raw})
+	if err != nil {
+		return WebhookEvent{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return WebhookEvent{Type: eventType, PartyType: partyType, PartyID: partyID, Payload: string(body)}, nil
+}
+
+// SignWebhookPayload returns the signature of the body sent at timestamp (Unix seconds)
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether signature is the signature of the body sent at timestamp
+func VerifyWebhookSignature(secret, timestamp, signature string, body []byte) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, timestamp, body)), []byte(signature))
+}
+
+// webhookBackoff is the delay after the given number of failed attempts: base, then doubled every attempt
+func webhookBackoff(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxWebhookBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookBackoff {
+		return maxWebhookBackoff
+	}
+	return delay
+}
+
+// newWebhookSecret returns a random signing key
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ErrWebhookAddressBlocked is returned for webhook requests to an address the server may not reach
+var ErrWebhookAddressBlocked = errors.New("webhook endpoint resolves to a loopback, link-local, private or unspecified address")
+
+// WebhookDispatcher pushes the events to the webhook endpoints. Publish stores a delivery per subscribed endpoint
+// in the transaction of the change the event is about, and Run sends them when due. Failed deliveries are retried
+// with an exponential backoff and dead-lettered after maxAttempts.
+type WebhookDispatcher struct {
+	db          *sql.DB
+	client      *http.Client
+	interval    time.Duration
+	backoff     time.Duration
+	maxAttempts int
+	// now is the clock of the attempts, read right before each one is sent
+	now func() time.Time
+}
+
+func newWebhookDispatcher(db *sql.DB, config *cfg.Config) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db:          db,
+		client:      newWebhookClient(config.WebhookTimeout, config.WebhookAllowPrivateNetworks),
+		interval:    config.WebhookDeliveryInterval,
+		backoff:     config.WebhookRetryBackoff,
+		maxAttempts: config.WebhookMaxAttempts,
+		now:         time.Now,
+	}
+}
+
+// newWebhookClient returns the HTTP client of the webhook requests. Unless private networks are allowed it refuses
+// to connect to loopback, link-local, private and unspecified addresses. The check runs on the address dialled,
+// after DNS resolution and on every redirect, so an endpoint's host name can't point it at an internal service.
+func newWebhookClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = blockPrivateAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Through a proxy the dialer would only see the proxy's address
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// blockPrivateAddress is the net.Dialer Control rejecting the addresses webhooks may not reach
+func blockPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, address)
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsPrivate() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, ip)
+	}
+	return nil
+}
+
+// Publish stores a delivery of the event for every endpoint of the party subscribed to it. Run it in the transaction
+// of the change the event is about, so the event is delivered if and only if the change is committed. Nothing is
+// stored when webhooks are disabled.
+func (d *WebhookDispatcher) Publish(ctx context.Context, db dbtx, eventType, partyType, partyID string, data proto.Message) error {
+	if d == nil || d.interval <= 0 || partyID == "" {
+		return nil
+	}
+	event, err := NewWebhookEvent(eventType, partyType, partyID, data, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = InsertWebhookDeliveries(ctx, db, event)
+	return err
+}
+
+// Run sends the due deliveries every interval until ctx is cancelled. The deliveries are claimed with SKIP LOCKED,
+// so every server of the marketplace can run it without sending a delivery twice.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	if d.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.deliverDue(ctx); err != nil {
+				slog.Error("Error delivering webhooks", "err", err)
+			}
+		}
+	}
+}
+
+// deliverDue claims a batch of due deliveries, sends them and records the outcome of every attempt. The claim lasts
+// as long as the batch can take, after that the deliveries of a server that stopped are sent by another one.
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) error {
+	lease := time.Duration(webhookBatchSize)*d.client.Timeout + d.interval
+	deliveries, err := ClaimDueWebhookDeliveries(ctx, d.db, webhookBatchSize, lease)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		// Every attempt is signed and retried from when it is sent, the ones before it may have taken up to their timeout
+		now := d.now()
+		code, sendErr := d.send(ctx, delivery.url, delivery.secret, delivery.id, delivery.eventType, delivery.payload, now)
+		attempt := webhookAttempt{id: delivery.id, attempts: delivery.attempts + 1, responseCode: code}
+		switch {
+		case sendErr == nil:
+			attempt.status = WebhookDeliveryDelivered
+		case attempt.attempts >= d.maxAttempts:
+			attempt.status = WebhookDeliveryDead
+			attempt.lastError = sendErr.Error()
+		default:
+			attempt.status = WebhookDeliveryPending
+			attempt.lastError = sendErr.Error()
+			attempt.nextAttemptAt = now.Add(webhookBackoff(d.backoff, attempt.attempts))
+		}
+		if err := RecordWebhookAttempt(ctx, d.db, attempt); err != nil {
+			return err
+		}
+		outcome := attempt.status
+		if outcome == WebhookDeliveryPending {
+			outcome = "retried"
+		}
+		webhookDeliveries.WithLabelValues(outcome).Inc()
+	}
+	return nil
+}
+
+// send posts the signed payload to the endpoint and returns its response code, non-2xx responses are errors
+func (d *WebhookDispatcher) send(ctx context.Context, endpointURL, secret, id, eventType, payload string, now time.Time) (code int, err error) {
+	ctx, span := startSpan(ctx, "SendWebhook", nil)
+	defer func() { endSpan(span, err) }()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL, strings.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, id)
+	req.Header.Set(WebhookEventHeader, eventType)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, timestamp, []byte(payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Draining the body lets the connection be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// sendTest posts a sample event to the endpoint once and returns the outcome as a delivery that isn't stored
+func (d *WebhookDispatcher) sendTest(ctx context.Context, endpoint *pb.WebhookEndpoint, secret string, now time.Time) (*pb.WebhookDelivery, error) {
+	sample := &pb.Bid{
+		Id: "00000000-0000-0000-0000-000000000000", InvoiceId: "00000000-0000-0000-0000-000000000000", Amount: 100, Status: "approved",
+	}
+	event, err := NewWebhookEvent(WebhookEventTest, endpoint.GetPartyType(), endpoint.GetPartyId(), sample, now)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	delivery := &pb.WebhookDelivery{
+		Id: "test-" + id[:16], EndpointId: endpoint.GetId(), EventType: event.Type, Payload: event.Payload, Attempts: 1,
+		CreatedAt: now.UTC().Format(time.RFC3339),
+	}
+	code, err := d.send(ctx, endpoint.GetUrl(), secret, delivery.GetId(), event.Type, event.Payload, now)
+	delivery.ResponseCode = int32(code)
+	delivery.Status = WebhookDeliveryDelivered
+	if err != nil {
+		delivery.Status = WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+	}
+	return delivery, nil
+}
+
+// ValidateWebhookEndpoint checks an endpoint before it is registered
+func ValidateWebhookEndpoint(in *pb.WebhookEndpoint) error {
+	if err := ValidateKycParty(in.GetPartyType(), in.GetPartyId()); err != nil {
+		return err
+	}
+	u, err := url.Parse(in.GetUrl())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return status.Errorf(codes.InvalidArgument, "url %q must be an absolute http or https URL", in.GetUrl())
+	}
+	for _, eventType := range in.GetEventTypes() {
+		if !containsString(webhookEventTypes, eventType) {
+			return status.Errorf(codes.InvalidArgument, "event type %q must be one of %s", eventType, strings.Join(webhookEventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// scanWebhookEndpoint reads a row of webhookEndpointColumns
+func scanWebhookEndpoint(row interface{ Scan(...any) error }) (*pb.WebhookEndpoint, error) {
+	endpoint := &pb.WebhookEndpoint{}
+	err := row.Scan(&endpoint.Id, &endpoint.PartyType, &endpoint.PartyId, &endpoint.Url, pq.Array(&endpoint.EventTypes), &endpoint.Status,
+		&endpoint.CreatedAt)
+	return endpoint, err
+}
+
+// scanWebhookDelivery reads a row of webhookDeliveryColumns
+func scanWebhookDelivery(row interface{ Scan(...any) error }) (*pb.WebhookDelivery, error) {
+	delivery := &pb.WebhookDelivery{}
+	err := row.Scan(&delivery.Id, &delivery.EndpointId, &delivery.EventType, &delivery.Payload, &delivery.Status, &delivery.Attempts,
+		&delivery.ResponseCode, &delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt)
+	return delivery, err
+}
+
+// scanWebhookDeadLetter reads a row of webhookDeadLetterColumns
+func scanWebhookDeadLetter(row interface{ Scan(...any) error }) (*pb.WebhookDeadLetter, error) {
+	letter := &pb.WebhookDeadLetter{}
+	err := row.Scan(&letter.Id, &letter.DeliveryId, &letter.EndpointId, &letter.EventType, &letter.Payload, &letter.Attempts,
+		&letter.LastError, &letter.CreatedAt, &letter.ReplayedAt)
+	return letter, err
+}
+
+// InsertWebhookEndpoint stores a new active endpoint and sets its id, status and creation time
+func InsertWebhookEndpoint(ctx context.Context, db dbtx, in *pb.WebhookEndpoint) (err error) {
+	ctx, span := startSpan(ctx, "InsertWebhookEndpoint", nil)
+	defer func() { endSpan(span, err) }()
+	err = db.QueryRowContext(ctx, `INSERT INTO webhook_endpoint (party_type, party_id, url, secret, event_types)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id, status, to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`,
+		in.GetPartyType(), in.GetPartyId(), in.GetUrl(), in.GetSecret(), pq.Array(in.GetEventTypes())).
+		Scan(&in.Id, &in.Status, &in.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookEndpoint returns an endpoint of the party with its secret
+func GetWebhookEndpoint(ctx context.Context, db dbtx, in *pb.WebhookEndpoint) (endpoint *pb.WebhookEndpoint, secret string, err error) {
+	ctx, span := startSpan(ctx, "GetWebhookEndpoint", nil)
+	defer func() { endSpan(span, err) }()
+	row := db.QueryRowContext(ctx, `SELECT `+webhookEndpointColumns+`, secret FROM webhook_endpoint
+		WHERE id::text = $1 AND party_type = $2 AND party_id::text = $3`, in.GetId(), in.GetPartyType(), in.GetPartyId())
+	endpoint = &pb.WebhookEndpoint{}
+	err = row.Scan(&endpoint.Id, &endpoint.PartyType, &endpoint.PartyId, &endpoint.Url, pq.Array(&endpoint.EventTypes), &endpoint.Status,
+		&endpoint.CreatedAt, &secret)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", status.Errorf(codes.NotFound, "webhook endpoint %s of %s %s not found", in.GetId(), in.GetPartyType(), in.GetPartyId())
+		}
+		return nil, "", fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+	return endpoint, secret, nil
+}
+
+// DisableWebhookEndpoint stops the deliveries to an endpoint of the party and returns the stored endpoint
+func DisableWebhookEndpoint(ctx context.Context, db dbtx, in *pb.WebhookEndpoint) (endpoint *pb.WebhookEndpoint, err error) {
+	ctx, span := startSpan(ctx, "DisableWebhookEndpoint", nil)
+	defer func() { endSpan(span, err) }()
+	endpoint, err = scanWebhookEndpoint(db.QueryRowContext(ctx, `UPDATE webhook_endpoint SET status = 'disabled'
+		WHERE id::text = $1 AND party_type = $2 AND party_id::text = $3 RETURNING `+webhookEndpointColumns,
+		in.GetId(), in.GetPartyType(), in.GetPartyId()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "webhook endpoint %s of %s %s not found", in.GetId(), in.GetPartyType(), in.GetPartyId())
+		}
+		return nil, fmt.Errorf("failed to disable webhook endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+// InsertWebhookDeliveries queues the event for every active endpoint of its party subscribed to it
+func InsertWebhookDeliveries(ctx context.Context, db dbtx, event WebhookEvent) (queued int64, err error) {
+	ctx, span := startSpan(ctx, "InsertWebhookDeliveries", nil)
+	defer func() { endSpan(span, err) }()
+	res, err := db.ExecContext(ctx, `INSERT INTO webhook_delivery (endpoint_id, event_type, payload)
+		SELECT id, $1, $4 FROM webhook_endpoint
+		WHERE party_type = $2 AND party_id::text = $3 AND status = 'active' AND (cardinality(event_types) = 0 OR $1 = ANY(event_types))`,
+		event.Type, event.PartyType, event.PartyID, event.Payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// dueWebhookDelivery is a pending delivery with where it goes
+type dueWebhookDelivery struct {
+	id        string
+	url       string
+	secret    string
+	eventType string
+	payload   string
+	attempts  int
+}
+
+// ClaimDueWebhookDeliveries claims up to limit pending deliveries of active endpoints whose next attempt is due,
+// oldest first. Rows locked by another server are skipped, and the claimed ones are only due again after lease.
+func ClaimDueWebhookDeliveries(ctx context.Context, db dbtx, limit int, lease time.Duration) (deliveries []dueWebhookDelivery, err error) {
+	ctx, span := startSpan(ctx, "ClaimDueWebhookDeliveries", nil)
+	defer func() { endSpan(span, err) }()
+	rows, err := db.QueryContext(ctx, `WITH due AS (
+			SELECT delivery.id FROM webhook_delivery delivery JOIN webhook_endpoint endpoint ON endpoint.id = delivery.endpoint_id
+			WHERE delivery.status = 'pending' AND delivery.next_attempt_at <= now() AND endpoint.status = 'active'
+			ORDER BY delivery.next_attempt_at, delivery.id LIMIT $1
+			FOR UPDATE OF delivery SKIP LOCKED)
+		UPDATE webhook_delivery delivery SET next_attempt_at = now() + $2 * interval '1 second'
+		FROM due, webhook_endpoint endpoint WHERE delivery.id = due.id AND endpoint.id = delivery.endpoint_id
+		RETURNING delivery.id, endpoint.url, endpoint.secret, delivery.event_type, delivery.payload, delivery.attempts`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var delivery dueWebhookDelivery
+		if err := rows.Scan(&delivery.id, &delivery.url, &delivery.secret, &delivery.eventType, &delivery.payload, &delivery.attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// webhookAttempt is the outcome of sending a delivery. Pending deliveries are retried at nextAttemptAt.
+type webhookAttempt struct {
+	id            string
+	status        string
+	attempts      int
+	responseCode  int
+	lastError     string
+	nextAttemptAt time.Time
+}
+
+// RecordWebhookAttempt stores the outcome of an attempt, a dead delivery also gets its dead letter
+func RecordWebhookAttempt(ctx context.Context, db dbtx, attempt webhookAttempt) (err error) {
+	ctx, span := startSpan(ctx, "RecordWebhookAttempt", nil)
+	defer func() { endSpan(span, err) }()
+	switch attempt.status {
+	case WebhookDeliveryDead:
+		_, err = db.ExecContext(ctx, `WITH dead AS (
+			UPDATE webhook_delivery SET status = 'dead', attempts = $2, response_code = $3, last_error = $4
+			WHERE id = $1 RETURNING id, endpoint_id, event_type, payload, attempts, last_error)
+		INSERT INTO webhook_dead_letter (delivery_id, endpoint_id, event_type, payload, attempts, last_error)
+		SELECT id, endpoint_id, event_type, payload, attempts, last_error FROM dead`,
+			attempt.id, attempt.attempts, attempt.responseCode, attempt.lastError)
+	case WebhookDeliveryPending:
+		_, err = db.ExecContext(ctx, `UPDATE webhook_delivery SET attempts = $2, response_code = $3, last_error = $4, next_attempt_at = $5
+			WHERE id = $1`, attempt.id, attempt.attempts, attempt.responseCode, attempt.lastError, attempt.nextAttemptAt.UTC())
+	default:
+		_, err = db.ExecContext(ctx, `UPDATE webhook_delivery SET status = $2, attempts = $3, response_code = $4, last_error = ''
+			WHERE id = $1`, attempt.id, attempt.status, attempt.attempts, attempt.responseCode)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// ReplayWebhookDeadLetter queues the delivery of a dead letter of the party again, with a fresh set of attempts
+func ReplayWebhookDeadLetter(ctx context.Context, db dbtx, in *pb.WebhookReplay) (delivery *pb.WebhookDelivery, err error) {
+	ctx, span := startSpan(ctx, "ReplayWebhookDeadLetter", nil)
+	defer func() { endSpan(span, err) }()
+	delivery, err = scanWebhookDelivery(db.QueryRowContext(ctx, `WITH replayed AS (
+			UPDATE webhook_dead_letter letter SET replayed_at = now()
+			FROM webhook_endpoint endpoint
+			WHERE letter.id::text = $1 AND letter.replayed_at IS NULL AND endpoint.id = letter.endpoint_id
+			AND endpoint.party_type = $2 AND endpoint.party_id::text = $3 AND endpoint.status = 'active'
+			RETURNING letter.delivery_id)
+		UPDATE webhook_delivery SET status = 'pending', attempts = 0, response_code = 0, last_error = '', next_attempt_at = now()
+		FROM replayed WHERE webhook_delivery.id = replayed.delivery_id RETURNING `+webhookDeliveryColumns,
+		in.GetDeadLetterId(), in.GetPartyType(), in.GetPartyId()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "dead letter %s of %s %s not found, already replayed or its endpoint is disabled",
+				in.GetDeadLetterId(), in.GetPartyType(), in.GetPartyId())
+		}
+		return nil, fmt.Errorf("failed to replay dead letter: %w", err)
+	}
+	return delivery, nil
+}
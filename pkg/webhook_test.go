@@ -0,0 +1,390 @@
+package pkg
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	cfg "github.com/berdebotond/bankable_technical_test/config"
+	pb "github.com/berdebotond/bankable_technical_test/protos"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var webhookEndpointRow = []string{"id", "party_type", "party_id", "url", "event_types", "status", "created_at"}
+
+func newTestWebhookDispatcher(db *sql.DB) *WebhookDispatcher {
+	return newWebhookDispatcher(db, &cfg.Config{
+		WebhookDeliveryInterval: time.Second, WebhookTimeout: time.Second, WebhookRetryBackoff: 30 * time.Second, WebhookMaxAttempts: 3,
+		// The test endpoints listen on the loopback
+		WebhookAllowPrivateNetworks: true,
+	})
+}
+
+// capturedArg matches any argument and keeps it
+type capturedArg struct{ value *string }
+
+func (a capturedArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*a.value = s
+	return ok
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"type":"bid.outbid"}`)
+	signature := SignWebhookPayload("secret", "1700000000", body)
+
+	assert.Equal(t, "ba706a16c13b1c44369c74a07474c4c301f4de8a026e81ab53aff32d68d218b1", signature)
+	assert.True(t, VerifyWebhookSignature("secret", "1700000000", signature, body))
+	// The timestamp is signed, so it can't be swapped for a fresh one
+	assert.False(t, VerifyWebhookSignature("secret", "1700000001", signature, body))
+	assert.False(t, VerifyWebhookSignature("other", "1700000000", signature, body))
+	assert.False(t, VerifyWebhookSignature("secret", "1700000000", signature, []byte(`{"type":"trade.settled"}`)))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookBackoff(30*time.Second, 1))
+	assert.Equal(t, time.Minute, webhookBackoff(30*time.Second, 2))
+	assert.Equal(t, 4*time.Minute, webhookBackoff(30*time.Second, 4))
+	assert.Equal(t, maxWebhookBackoff, webhookBackoff(30*time.Second, 40))
+}
+
+func TestValidateWebhookEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *pb.WebhookEndpoint
+		ok   bool
+	}{
+		{"valid", &pb.WebhookEndpoint{PartyType: PartyInvestor, PartyId: "investor-id", Url: "https://example.com/hooks"}, true},
+		{"subscribed", &pb.WebhookEndpoint{PartyType: PartyIssuer, PartyId: "issuer-id", Url: "http://localhost:8000",
+			EventTypes: []string{WebhookEventFunded, WebhookEventRepaid}}, true},
+		{"unknown party", &pb.WebhookEndpoint{PartyType: "debtor", PartyId: "id", Url: "https://example.com"}, false},
+		{"relative url", &pb.WebhookEndpoint{PartyType: PartyInvestor, PartyId: "investor-id", Url: "/hooks"}, false},
+		{"other scheme", &pb.WebhookEndpoint{PartyType: PartyInvestor, PartyId: "investor-id", Url: "ftp://example.com"}, false},
+		{"unknown event", &pb.WebhookEndpoint{PartyType: PartyInvestor, PartyId: "investor-id", Url: "https://example.com",
+			EventTypes: []string{WebhookEventTest}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWebhookEndpoint(tt.in)
+			assert.Equal(t, tt.ok, err == nil, "%v", err)
+		})
+	}
+}
+
+func TestPublishWebhookEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	d := newTestWebhookDispatcher(db)
+	s := &server{webhooks: d}
+
+	// The deliveries are stored through the caller's transaction
+	var funded string
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO webhook_delivery").WithArgs(WebhookEventFunded, PartyIssuer, "issuer-id", capturedArg{&funded}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO webhook_delivery").WithArgs(WebhookEventSettled, PartyInvestor, "investor-id", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+
+	err = s.publishTrade(context.Background(), tx, &pb.Bid{Id: "bid-id", InvoiceId: "invoice-id", InvestorId: "investor-id", Amount: 100}, "issuer-id")
+
+	assert.NoError(t, err)
+	var payload map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(funded), &payload))
+	assert.Equal(t, WebhookEventFunded, payload["type"])
+	assert.Equal(t, "invoice-id", payload["data"].(map[string]any)["invoice_id"])
+
+	// Without a dispatcher or with deliveries disabled nothing is stored
+	var disabled *WebhookDispatcher
+	assert.NoError(t, disabled.Publish(context.Background(), tx, WebhookEventOutbid, PartyInvestor, "investor-id", &pb.Bid{}))
+	d.interval = 0
+	assert.NoError(t, d.Publish(context.Background(), tx, WebhookEventOutbid, PartyInvestor, "investor-id", &pb.Bid{}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertWebhookDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	event := WebhookEvent{Type: WebhookEventOutbid, PartyType: PartyInvestor, PartyID: "investor-id", Payload: "{}"}
+	mock.ExpectExec("INSERT INTO webhook_delivery \\(endpoint_id, event_type, payload\\)").
+		WithArgs(WebhookEventOutbid, PartyInvestor, "investor-id", "{}").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	queued, err := InsertWebhookDeliveries(context.Background(), db, event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), queued)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectDueWebhookDelivery claims a due delivery to url that was already attempted attempts times, for as long as a
+// batch of the test dispatcher can take
+func expectDueWebhookDelivery(mock sqlmock.Sqlmock, url string, attempts int) {
+	expectDueWebhookDeliveries(mock, sqlmock.NewRows([]string{"id", "url", "secret", "event_type", "payload", "attempts"}).
+		AddRow("delivery-id", url, "secret", WebhookEventOutbid, `{"type":"bid.outbid"}`, attempts))
+}
+
+// expectDueWebhookDeliveries claims the due deliveries of rows
+func expectDueWebhookDeliveries(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectQuery("FOR UPDATE OF delivery SKIP LOCKED\\)\\s+UPDATE webhook_delivery delivery SET next_attempt_at").
+		WithArgs(webhookBatchSize, float64(webhookBatchSize+1)).WillReturnRows(rows)
+}
+
+func TestDeliverDueWebhooks(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var received *http.Request
+	var body []byte
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer endpoint.Close()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	d := newTestWebhookDispatcher(db)
+	d.now = func() time.Time { return now }
+
+	expectDueWebhookDelivery(mock, endpoint.URL, 0)
+	mock.ExpectExec("UPDATE webhook_delivery SET status = \\$2, attempts = \\$3, response_code = \\$4").
+		WithArgs("delivery-id", WebhookDeliveryDelivered, 1, http.StatusOK).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = d.deliverDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, "delivery-id", received.Header.Get(WebhookIDHeader))
+	assert.Equal(t, WebhookEventOutbid, received.Header.Get(WebhookEventHeader))
+	assert.Equal(t, "1700000000", received.Header.Get(WebhookTimestampHeader))
+	assert.True(t, VerifyWebhookSignature("secret", "1700000000", received.Header.Get(WebhookSignatureHeader), body))
+	assert.Equal(t, `{"type":"bid.outbid"}`, string(body))
+}
+
+func TestDeliverDueWebhooksRetries(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer endpoint.Close()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	d := newTestWebhookDispatcher(db)
+	d.now = func() time.Time { return now }
+
+	// The second failure waits twice the base backoff
+	expectDueWebhookDelivery(mock, endpoint.URL, 1)
+	mock.ExpectExec("UPDATE webhook_delivery SET attempts = \\$2, response_code = \\$3, last_error = \\$4, next_attempt_at = \\$5").
+		WithArgs("delivery-id", 2, http.StatusServiceUnavailable, "endpoint responded 503 Service Unavailable", now.Add(time.Minute).UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, d.deliverDue(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeliverDueWebhooksTimestampEachAttempt(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var timestamps []string
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Blocks for 5 minutes on the dispatcher's clock
+		timestamps = append(timestamps, r.Header.Get(WebhookTimestampHeader))
+		now = now.Add(5 * time.Minute)
+	}))
+	defer slow.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamps = append(timestamps, r.Header.Get(WebhookTimestampHeader))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	d := newTestWebhookDispatcher(db)
+	d.now = func() time.Time { return now }
+
+	expectDueWebhookDeliveries(mock, sqlmock.NewRows([]string{"id", "url", "secret", "event_type", "payload", "attempts"}).
+		AddRow("slow-id", slow.URL, "secret", WebhookEventOutbid, "{}", 0).
+		AddRow("failing-id", failing.URL, "secret", WebhookEventOutbid, "{}", 0))
+	mock.ExpectExec("UPDATE webhook_delivery SET status").WithArgs("slow-id", WebhookDeliveryDelivered, 1, http.StatusOK).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The retry is measured from the second attempt, not from when the batch started
+	mock.ExpectExec("UPDATE webhook_delivery SET attempts").
+		WithArgs("failing-id", 1, http.StatusServiceUnavailable, sqlmock.AnyArg(), time.Unix(1700000000, 0).Add(5*time.Minute+30*time.Second).UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, d.deliverDue(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []string{"1700000000", "1700000300"}, timestamps)
+}
+
+func TestDeliverDueWebhooksDeadLetter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	d := newTestWebhookDispatcher(db)
+
+	// Nothing listens on the port, the last attempt fails and the delivery is dead-lettered
+	expectDueWebhookDelivery(mock, "http://127.0.0.1:1/hooks", 2)
+	mock.ExpectExec("UPDATE webhook_delivery SET status = 'dead'.*INSERT INTO webhook_dead_letter").
+		WithArgs("delivery-id", 3, 0, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, d.deliverDue(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookClientBlocksPrivateAddresses(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer endpoint.Close()
+
+	// The loopback is refused at dial time, whatever name the URL uses
+	_, err := newWebhookClient(time.Second, false).Post(endpoint.URL, "application/json", nil)
+	assert.ErrorIs(t, err, ErrWebhookAddressBlocked)
+
+	for _, address := range []string{"127.0.0.1:80", "[::1]:443", "10.1.2.3:80", "172.16.0.1:80", "192.168.1.1:80", "169.254.169.254:80",
+		"[fe80::1]:80", "[fd00::1]:80", "0.0.0.0:80", "[::ffff:10.0.0.1]:80"} {
+		assert.ErrorIs(t, blockPrivateAddress("tcp4", address, nil), ErrWebhookAddressBlocked, address)
+	}
+	assert.NoError(t, blockPrivateAddress("tcp4", "93.184.216.34:443", nil))
+	assert.NoError(t, blockPrivateAddress("tcp6", "[2606:2800:220:1::]:443", nil))
+}
+
+func TestRegisterWebhookEndpoint(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectQuery("SELECT kyc_status FROM investor WHERE id = \\$1").WithArgs("investor-id").
+		WillReturnRows(sqlmock.NewRows([]string{"kyc_status"}).AddRow(KycVerified))
+	mock.ExpectQuery("INSERT INTO webhook_endpoint \\(party_type, party_id, url, secret, event_types\\)").
+		WithArgs(PartyInvestor, "investor-id", "https://example.com/hooks", sqlmock.AnyArg(), `{"bid.outbid"}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at"}).AddRow("endpoint-id", WebhookEndpointActive, "2024-01-01T00:00:00Z"))
+
+	endpoint, err := s.RegisterWebhookEndpoint(asParty(PartyInvestor, "investor-id"), &pb.WebhookEndpoint{
+		PartyType: PartyInvestor, PartyId: "investor-id", Url: "https://example.com/hooks", EventTypes: []string{WebhookEventOutbid},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "endpoint-id", endpoint.Id)
+	assert.Equal(t, WebhookEndpointActive, endpoint.Status)
+	assert.Len(t, endpoint.Secret, 64)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRPCsRequireParty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, webhooks: newTestWebhookDispatcher(db)}
+	endpoint := &pb.WebhookEndpoint{Id: "endpoint-id", PartyType: PartyInvestor, PartyId: "investor-id", Url: "https://example.com/hooks"}
+
+	// Nobody else reads or changes the endpoints of the investor, and nothing reaches the database
+	_, err = s.RegisterWebhookEndpoint(context.Background(), endpoint)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.RegisterWebhookEndpoint(asParty(PartyInvestor, "other-investor-id"), endpoint)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.DeleteWebhookEndpoint(asParty(PartyIssuer, "investor-id"), endpoint)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.TestWebhookEndpoint(asParty(PartyInvestor, "other-investor-id"), endpoint)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.ReplayWebhookDeadLetter(context.Background(), &pb.WebhookReplay{DeadLetterId: "letter-id", PartyType: PartyInvestor, PartyId: "investor-id"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTestWebhookEndpoint(t *testing.T) {
+	var payload map[string]any
+	var signed bool
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		signed = VerifyWebhookSignature("secret", r.Header.Get(WebhookTimestampHeader), r.Header.Get(WebhookSignatureHeader), body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer endpoint.Close()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, webhooks: newTestWebhookDispatcher(db)}
+
+	mock.ExpectQuery("SELECT .* FROM webhook_endpoint").WithArgs("endpoint-id", PartyIssuer, "issuer-id").
+		WillReturnRows(sqlmock.NewRows(append(webhookEndpointRow, "secret")).
+			AddRow("endpoint-id", PartyIssuer, "issuer-id", endpoint.URL, "{}", WebhookEndpointActive, "2024-01-01T00:00:00Z", "secret"))
+
+	delivery, err := s.TestWebhookEndpoint(asParty(PartyIssuer, "issuer-id"), &pb.WebhookEndpoint{Id: "endpoint-id", PartyType: PartyIssuer, PartyId: "issuer-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, int32(http.StatusNoContent), delivery.ResponseCode)
+	assert.Equal(t, WebhookEventTest, delivery.EventType)
+	assert.Equal(t, WebhookEventTest, payload["type"])
+	assert.True(t, signed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTestWebhookEndpointDisabled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db, webhooks: newTestWebhookDispatcher(db)}
+
+	mock.ExpectQuery("SELECT .* FROM webhook_endpoint").
+		WillReturnRows(sqlmock.NewRows(append(webhookEndpointRow, "secret")).
+			AddRow("endpoint-id", PartyIssuer, "issuer-id", "https://example.com", "{}", WebhookEndpointDisabled, "2024-01-01T00:00:00Z", "secret"))
+
+	// Admins manage the endpoints of every party
+	_, err = s.TestWebhookEndpoint(asParty(RoleAdmin, ""), &pb.WebhookEndpoint{Id: "endpoint-id", PartyType: PartyIssuer, PartyId: "issuer-id"})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplayWebhookDeadLetter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectQuery("UPDATE webhook_dead_letter letter SET replayed_at = now\\(\\).*UPDATE webhook_delivery SET status = 'pending', attempts = 0").
+		WithArgs("letter-id", PartyInvestor, "investor-id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "endpoint_id", "event_type", "payload", "status", "attempts", "response_code", "last_error",
+			"next_attempt_at", "created_at"}).
+			AddRow("delivery-id", "endpoint-id", WebhookEventOutbid, "{}", WebhookDeliveryPending, 0, 0, "", "2024-01-02T00:00:00Z", "2024-01-01T00:00:00Z"))
+
+	delivery, err := s.ReplayWebhookDeadLetter(asParty(PartyInvestor, "investor-id"), &pb.WebhookReplay{DeadLetterId: "letter-id", PartyType: PartyInvestor, PartyId: "investor-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "delivery-id", delivery.Id)
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplayWebhookDeadLetterNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	s := &server{db: db}
+
+	mock.ExpectQuery("UPDATE webhook_dead_letter").WillReturnError(sql.ErrNoRows)
+
+	_, err = s.ReplayWebhookDeadLetter(asParty(PartyInvestor, "investor-id"), &pb.WebhookReplay{DeadLetterId: "letter-id", PartyType: PartyInvestor, PartyId: "investor-id"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
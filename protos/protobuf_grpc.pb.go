@@ -20,12 +20,39 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	InvoiceService_CreateInvoice_FullMethodName = "/invoice.InvoiceService/CreateInvoice"
-	InvoiceService_GetInvoice_FullMethodName    = "/invoice.InvoiceService/GetInvoice"
-	InvoiceService_GetIssuer_FullMethodName     = "/invoice.InvoiceService/GetIssuer"
-	InvoiceService_GetInvestors_FullMethodName  = "/invoice.InvoiceService/GetInvestors"
-	InvoiceService_PlaceBid_FullMethodName      = "/invoice.InvoiceService/PlaceBid"
-	InvoiceService_ApproveTrade_FullMethodName  = "/invoice.InvoiceService/ApproveTrade"
+	InvoiceService_CreateInvoice_FullMethodName           = "/invoice.InvoiceService/CreateInvoice"
+	InvoiceService_GetInvoice_FullMethodName              = "/invoice.InvoiceService/GetInvoice"
+	InvoiceService_GetIssuer_FullMethodName               = "/invoice.InvoiceService/GetIssuer"
+	InvoiceService_GetIssuerSummary_FullMethodName        = "/invoice.InvoiceService/GetIssuerSummary"
+	InvoiceService_GetInvestors_FullMethodName            = "/invoice.InvoiceService/GetInvestors"
+	InvoiceService_PlaceBid_FullMethodName                = "/invoice.InvoiceService/PlaceBid"
+	InvoiceService_ApproveTrade_FullMethodName            = "/invoice.InvoiceService/ApproveTrade"
+	InvoiceService_CancelBid_FullMethodName               = "/invoice.InvoiceService/CancelBid"
+	InvoiceService_CreateAutoBidRule_FullMethodName       = "/invoice.InvoiceService/CreateAutoBidRule"
+	InvoiceService_UpdateAutoBidRule_FullMethodName       = "/invoice.InvoiceService/UpdateAutoBidRule"
+	InvoiceService_SetAutoBidRuleStatus_FullMethodName    = "/invoice.InvoiceService/SetAutoBidRuleStatus"
+	InvoiceService_ListAutoBidRules_FullMethodName        = "/invoice.InvoiceService/ListAutoBidRules"
+	InvoiceService_ListAutoBidExecutions_FullMethodName   = "/invoice.InvoiceService/ListAutoBidExecutions"
+	InvoiceService_RecordRepayment_FullMethodName         = "/invoice.InvoiceService/RecordRepayment"
+	InvoiceService_SetInvestorTier_FullMethodName         = "/invoice.InvoiceService/SetInvestorTier"
+	InvoiceService_ListInvestorTiers_FullMethodName       = "/invoice.InvoiceService/ListInvestorTiers"
+	InvoiceService_AssignInvestorTier_FullMethodName      = "/invoice.InvoiceService/AssignInvestorTier"
+	InvoiceService_SubmitKycDocument_FullMethodName       = "/invoice.InvoiceService/SubmitKycDocument"
+	InvoiceService_GetKycStatus_FullMethodName            = "/invoice.InvoiceService/GetKycStatus"
+	InvoiceService_VerifyKyc_FullMethodName               = "/invoice.InvoiceService/VerifyKyc"
+	InvoiceService_ReviewKyc_FullMethodName               = "/invoice.InvoiceService/ReviewKyc"
+	InvoiceService_UploadInvoiceDocument_FullMethodName   = "/invoice.InvoiceService/UploadInvoiceDocument"
+	InvoiceService_DownloadInvoiceDocument_FullMethodName = "/invoice.InvoiceService/DownloadInvoiceDocument"
+	InvoiceService_ImportInvoice_FullMethodName           = "/invoice.InvoiceService/ImportInvoice"
+	InvoiceService_ImportInvoicesCsv_FullMethodName       = "/invoice.InvoiceService/ImportInvoicesCsv"
+	InvoiceService_ExportInvestorStatement_FullMethodName = "/invoice.InvoiceService/ExportInvestorStatement"
+	InvoiceService_GetPortfolio_FullMethodName            = "/invoice.InvoiceService/GetPortfolio"
+	InvoiceService_RegisterWebhookEndpoint_FullMethodName = "/invoice.InvoiceService/RegisterWebhookEndpoint"
+	InvoiceService_ListWebhookEndpoints_FullMethodName    = "/invoice.InvoiceService/ListWebhookEndpoints"
+	InvoiceService_DeleteWebhookEndpoint_FullMethodName   = "/invoice.InvoiceService/DeleteWebhookEndpoint"
+	InvoiceService_TestWebhookEndpoint_FullMethodName     = "/invoice.InvoiceService/TestWebhookEndpoint"
+	InvoiceService_ListWebhookDeadLetters_FullMethodName  = "/invoice.InvoiceService/ListWebhookDeadLetters"
+	InvoiceService_ReplayWebhookDeadLetter_FullMethodName = "/invoice.InvoiceService/ReplayWebhookDeadLetter"
 )
 
 // InvoiceServiceClient is the client API for InvoiceService service.
@@ -35,10 +62,49 @@ type InvoiceServiceClient interface {
 	CreateInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*Invoice, error)
 	GetInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*Invoice, error)
 	GetIssuer(ctx context.Context, in *Issuer, opts ...grpc.CallOption) (*Issuer, error)
+	// Dashboard of an issuer: invoices by status, best bids, financing raised and what is owed
+	GetIssuerSummary(ctx context.Context, in *Issuer, opts ...grpc.CallOption) (*IssuerSummary, error)
 	//I'm using a stream to get all the investors since we don't know how many there are
 	GetInvestors(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (InvoiceService_GetInvestorsClient, error)
 	PlaceBid(ctx context.Context, in *Bid, opts ...grpc.CallOption) (*Bid, error)
 	ApproveTrade(ctx context.Context, in *Bid, opts ...grpc.CallOption) (*Bid, error)
+	// Withdraws a pending bid and refunds it, the cancelled bid is returned
+	CancelBid(ctx context.Context, in *BidCancellation, opts ...grpc.CallOption) (*Bid, error)
+	// Standing orders bidding on behalf of an investor, with the log of what they did
+	CreateAutoBidRule(ctx context.Context, in *AutoBidRule, opts ...grpc.CallOption) (*AutoBidRule, error)
+	UpdateAutoBidRule(ctx context.Context, in *AutoBidRule, opts ...grpc.CallOption) (*AutoBidRule, error)
+	SetAutoBidRuleStatus(ctx context.Context, in *AutoBidRuleStatus, opts ...grpc.CallOption) (*AutoBidRule, error)
+	ListAutoBidRules(ctx context.Context, in *Investor, opts ...grpc.CallOption) (InvoiceService_ListAutoBidRulesClient, error)
+	ListAutoBidExecutions(ctx context.Context, in *AutoBidExecutionRequest, opts ...grpc.CallOption) (InvoiceService_ListAutoBidExecutionsClient, error)
+	RecordRepayment(ctx context.Context, in *Repayment, opts ...grpc.CallOption) (*Repayment, error)
+	// Admin RPCs managing the investor tiers and their exposure limits
+	SetInvestorTier(ctx context.Context, in *InvestorTier, opts ...grpc.CallOption) (*InvestorTier, error)
+	ListInvestorTiers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (InvoiceService_ListInvestorTiersClient, error)
+	AssignInvestorTier(ctx context.Context, in *Investor, opts ...grpc.CallOption) (*Investor, error)
+	// Onboarding: only verified issuers list invoices and only verified investors bid
+	SubmitKycDocument(ctx context.Context, in *KycDocument, opts ...grpc.CallOption) (*KycDocument, error)
+	GetKycStatus(ctx context.Context, in *KycParty, opts ...grpc.CallOption) (*KycStatus, error)
+	VerifyKyc(ctx context.Context, in *KycParty, opts ...grpc.CallOption) (*KycStatus, error)
+	ReviewKyc(ctx context.Context, in *KycReview, opts ...grpc.CallOption) (*KycStatus, error)
+	// Invoice documents are streamed in chunks, only the issuer uploads them
+	UploadInvoiceDocument(ctx context.Context, opts ...grpc.CallOption) (InvoiceService_UploadInvoiceDocumentClient, error)
+	DownloadInvoiceDocument(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (InvoiceService_DownloadInvoiceDocumentClient, error)
+	// Lists an invoice from a UBL e-invoice, each document is imported once
+	ImportInvoice(ctx context.Context, in *InvoiceImport, opts ...grpc.CallOption) (*Invoice, error)
+	// Lists the invoices of a CSV file, the valid rows in one transaction
+	ImportInvoicesCsv(ctx context.Context, opts ...grpc.CallOption) (InvoiceService_ImportInvoicesCsvClient, error)
+	// Statements are generated from the stored bids, trades, repayments and deposits
+	ExportInvestorStatement(ctx context.Context, in *StatementRequest, opts ...grpc.CallOption) (InvoiceService_ExportInvestorStatementClient, error)
+	// Positions, returns and exposure of an investor, the positions a page at a time
+	GetPortfolio(ctx context.Context, in *PortfolioRequest, opts ...grpc.CallOption) (*Portfolio, error)
+	// Signed push notifications of bids, trades and repayments, failed deliveries end up as replayable dead letters
+	RegisterWebhookEndpoint(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (*WebhookEndpoint, error)
+	ListWebhookEndpoints(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (InvoiceService_ListWebhookEndpointsClient, error)
+	DeleteWebhookEndpoint(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (*WebhookEndpoint, error)
+	// Sends a sample event to the endpoint right away and returns the outcome, nothing is retried
+	TestWebhookEndpoint(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (*WebhookDelivery, error)
+	ListWebhookDeadLetters(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (InvoiceService_ListWebhookDeadLettersClient, error)
+	ReplayWebhookDeadLetter(ctx context.Context, in *WebhookReplay, opts ...grpc.CallOption) (*WebhookDelivery, error)
 }
 
 type invoiceServiceClient struct {
@@ -76,6 +142,15 @@ func (c *invoiceServiceClient) GetIssuer(ctx context.Context, in *Issuer, opts .
 	return out, nil
 }
 
+func (c *invoiceServiceClient) GetIssuerSummary(ctx context.Context, in *Issuer, opts ...grpc.CallOption) (*IssuerSummary, error) {
+	out := new(IssuerSummary)
+	err := c.cc.Invoke(ctx, InvoiceService_GetIssuerSummary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *invoiceServiceClient) GetInvestors(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (InvoiceService_GetInvestorsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[0], InvoiceService_GetInvestors_FullMethodName, opts...)
 	if err != nil {
@@ -96,192 +171,1280 @@ type InvoiceService_GetInvestorsClient interface {
 	grpc.ClientStream
 }
 
-type invoiceServiceGetInvestorsClient struct {
-	grpc.ClientStream
+type invoiceServiceGetInvestorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceGetInvestorsClient) Recv() (*Investor, error) {
+	m := new(Investor)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) PlaceBid(ctx context.Context, in *Bid, opts ...grpc.CallOption) (*Bid, error) {
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, InvoiceService_PlaceBid_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ApproveTrade(ctx context.Context, in *Bid, opts ...grpc.CallOption) (*Bid, error) {
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, InvoiceService_ApproveTrade_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) CancelBid(ctx context.Context, in *BidCancellation, opts ...grpc.CallOption) (*Bid, error) {
+	out := new(Bid)
+	err := c.cc.Invoke(ctx, InvoiceService_CancelBid_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) CreateAutoBidRule(ctx context.Context, in *AutoBidRule, opts ...grpc.CallOption) (*AutoBidRule, error) {
+	out := new(AutoBidRule)
+	err := c.cc.Invoke(ctx, InvoiceService_CreateAutoBidRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) UpdateAutoBidRule(ctx context.Context, in *AutoBidRule, opts ...grpc.CallOption) (*AutoBidRule, error) {
+	out := new(AutoBidRule)
+	err := c.cc.Invoke(ctx, InvoiceService_UpdateAutoBidRule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) SetAutoBidRuleStatus(ctx context.Context, in *AutoBidRuleStatus, opts ...grpc.CallOption) (*AutoBidRule, error) {
+	out := new(AutoBidRule)
+	err := c.cc.Invoke(ctx, InvoiceService_SetAutoBidRuleStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListAutoBidRules(ctx context.Context, in *Investor, opts ...grpc.CallOption) (InvoiceService_ListAutoBidRulesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[1], InvoiceService_ListAutoBidRules_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceListAutoBidRulesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoiceService_ListAutoBidRulesClient interface {
+	Recv() (*AutoBidRule, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceListAutoBidRulesClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceListAutoBidRulesClient) Recv() (*AutoBidRule, error) {
+	m := new(AutoBidRule)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) ListAutoBidExecutions(ctx context.Context, in *AutoBidExecutionRequest, opts ...grpc.CallOption) (InvoiceService_ListAutoBidExecutionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[2], InvoiceService_ListAutoBidExecutions_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceListAutoBidExecutionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoiceService_ListAutoBidExecutionsClient interface {
+	Recv() (*AutoBidExecution, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceListAutoBidExecutionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceListAutoBidExecutionsClient) Recv() (*AutoBidExecution, error) {
+	m := new(AutoBidExecution)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) RecordRepayment(ctx context.Context, in *Repayment, opts ...grpc.CallOption) (*Repayment, error) {
+	out := new(Repayment)
+	err := c.cc.Invoke(ctx, InvoiceService_RecordRepayment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) SetInvestorTier(ctx context.Context, in *InvestorTier, opts ...grpc.CallOption) (*InvestorTier, error) {
+	out := new(InvestorTier)
+	err := c.cc.Invoke(ctx, InvoiceService_SetInvestorTier_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListInvestorTiers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (InvoiceService_ListInvestorTiersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[3], InvoiceService_ListInvestorTiers_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceListInvestorTiersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoiceService_ListInvestorTiersClient interface {
+	Recv() (*InvestorTier, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceListInvestorTiersClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceListInvestorTiersClient) Recv() (*InvestorTier, error) {
+	m := new(InvestorTier)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) AssignInvestorTier(ctx context.Context, in *Investor, opts ...grpc.CallOption) (*Investor, error) {
+	out := new(Investor)
+	err := c.cc.Invoke(ctx, InvoiceService_AssignInvestorTier_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) SubmitKycDocument(ctx context.Context, in *KycDocument, opts ...grpc.CallOption) (*KycDocument, error) {
+	out := new(KycDocument)
+	err := c.cc.Invoke(ctx, InvoiceService_SubmitKycDocument_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) GetKycStatus(ctx context.Context, in *KycParty, opts ...grpc.CallOption) (*KycStatus, error) {
+	out := new(KycStatus)
+	err := c.cc.Invoke(ctx, InvoiceService_GetKycStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) VerifyKyc(ctx context.Context, in *KycParty, opts ...grpc.CallOption) (*KycStatus, error) {
+	out := new(KycStatus)
+	err := c.cc.Invoke(ctx, InvoiceService_VerifyKyc_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ReviewKyc(ctx context.Context, in *KycReview, opts ...grpc.CallOption) (*KycStatus, error) {
+	out := new(KycStatus)
+	err := c.cc.Invoke(ctx, InvoiceService_ReviewKyc_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) UploadInvoiceDocument(ctx context.Context, opts ...grpc.CallOption) (InvoiceService_UploadInvoiceDocumentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[4], InvoiceService_UploadInvoiceDocument_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceUploadInvoiceDocumentClient{stream}
+	return x, nil
+}
+
+type InvoiceService_UploadInvoiceDocumentClient interface {
+	Send(*InvoiceDocumentChunk) error
+	CloseAndRecv() (*InvoiceDocument, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceUploadInvoiceDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceUploadInvoiceDocumentClient) Send(m *InvoiceDocumentChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *invoiceServiceUploadInvoiceDocumentClient) CloseAndRecv() (*InvoiceDocument, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(InvoiceDocument)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) DownloadInvoiceDocument(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (InvoiceService_DownloadInvoiceDocumentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[5], InvoiceService_DownloadInvoiceDocument_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceDownloadInvoiceDocumentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoiceService_DownloadInvoiceDocumentClient interface {
+	Recv() (*InvoiceDocumentChunk, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceDownloadInvoiceDocumentClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceDownloadInvoiceDocumentClient) Recv() (*InvoiceDocumentChunk, error) {
+	m := new(InvoiceDocumentChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) ImportInvoice(ctx context.Context, in *InvoiceImport, opts ...grpc.CallOption) (*Invoice, error) {
+	out := new(Invoice)
+	err := c.cc.Invoke(ctx, InvoiceService_ImportInvoice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ImportInvoicesCsv(ctx context.Context, opts ...grpc.CallOption) (InvoiceService_ImportInvoicesCsvClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[6], InvoiceService_ImportInvoicesCsv_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceImportInvoicesCsvClient{stream}
+	return x, nil
+}
+
+type InvoiceService_ImportInvoicesCsvClient interface {
+	Send(*InvoiceCsvChunk) error
+	CloseAndRecv() (*InvoiceImportSummary, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceImportInvoicesCsvClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceImportInvoicesCsvClient) Send(m *InvoiceCsvChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *invoiceServiceImportInvoicesCsvClient) CloseAndRecv() (*InvoiceImportSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(InvoiceImportSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) ExportInvestorStatement(ctx context.Context, in *StatementRequest, opts ...grpc.CallOption) (InvoiceService_ExportInvestorStatementClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[7], InvoiceService_ExportInvestorStatement_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceExportInvestorStatementClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoiceService_ExportInvestorStatementClient interface {
+	Recv() (*StatementChunk, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceExportInvestorStatementClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceExportInvestorStatementClient) Recv() (*StatementChunk, error) {
+	m := new(StatementChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) GetPortfolio(ctx context.Context, in *PortfolioRequest, opts ...grpc.CallOption) (*Portfolio, error) {
+	out := new(Portfolio)
+	err := c.cc.Invoke(ctx, InvoiceService_GetPortfolio_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) RegisterWebhookEndpoint(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (*WebhookEndpoint, error) {
+	out := new(WebhookEndpoint)
+	err := c.cc.Invoke(ctx, InvoiceService_RegisterWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListWebhookEndpoints(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (InvoiceService_ListWebhookEndpointsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[8], InvoiceService_ListWebhookEndpoints_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceListWebhookEndpointsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoiceService_ListWebhookEndpointsClient interface {
+	Recv() (*WebhookEndpoint, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceListWebhookEndpointsClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceListWebhookEndpointsClient) Recv() (*WebhookEndpoint, error) {
+	m := new(WebhookEndpoint)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) DeleteWebhookEndpoint(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (*WebhookEndpoint, error) {
+	out := new(WebhookEndpoint)
+	err := c.cc.Invoke(ctx, InvoiceService_DeleteWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) TestWebhookEndpoint(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (*WebhookDelivery, error) {
+	out := new(WebhookDelivery)
+	err := c.cc.Invoke(ctx, InvoiceService_TestWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListWebhookDeadLetters(ctx context.Context, in *WebhookEndpoint, opts ...grpc.CallOption) (InvoiceService_ListWebhookDeadLettersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoiceService_ServiceDesc.Streams[9], InvoiceService_ListWebhookDeadLetters_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoiceServiceListWebhookDeadLettersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoiceService_ListWebhookDeadLettersClient interface {
+	Recv() (*WebhookDeadLetter, error)
+	grpc.ClientStream
+}
+
+type invoiceServiceListWebhookDeadLettersClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoiceServiceListWebhookDeadLettersClient) Recv() (*WebhookDeadLetter, error) {
+	m := new(WebhookDeadLetter)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoiceServiceClient) ReplayWebhookDeadLetter(ctx context.Context, in *WebhookReplay, opts ...grpc.CallOption) (*WebhookDelivery, error) {
+	out := new(WebhookDelivery)
+	err := c.cc.Invoke(ctx, InvoiceService_ReplayWebhookDeadLetter_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvoiceServiceServer is the server API for InvoiceService service.
+// All implementations must embed UnimplementedInvoiceServiceServer
+// for forward compatibility
+type InvoiceServiceServer interface {
+	CreateInvoice(context.Context, *Invoice) (*Invoice, error)
+	GetInvoice(context.Context, *Invoice) (*Invoice, error)
+	GetIssuer(context.Context, *Issuer) (*Issuer, error)
+	// Dashboard of an issuer: invoices by status, best bids, financing raised and what is owed
+	GetIssuerSummary(context.Context, *Issuer) (*IssuerSummary, error)
+	//I'm using a stream to get all the investors since we don't know how many there are
+	GetInvestors(*empty.Empty, InvoiceService_GetInvestorsServer) error
+	PlaceBid(context.Context, *Bid) (*Bid, error)
+	ApproveTrade(context.Context, *Bid) (*Bid, error)
+	// Withdraws a pending bid and refunds it, the cancelled bid is returned
+	CancelBid(context.Context, *BidCancellation) (*Bid, error)
+	// Standing orders bidding on behalf of an investor, with the log of what they did
+	CreateAutoBidRule(context.Context, *AutoBidRule) (*AutoBidRule, error)
+	UpdateAutoBidRule(context.Context, *AutoBidRule) (*AutoBidRule, error)
+	SetAutoBidRuleStatus(context.Context, *AutoBidRuleStatus) (*AutoBidRule, error)
+	ListAutoBidRules(*Investor, InvoiceService_ListAutoBidRulesServer) error
+	ListAutoBidExecutions(*AutoBidExecutionRequest, InvoiceService_ListAutoBidExecutionsServer) error
+	RecordRepayment(context.Context, *Repayment) (*Repayment, error)
+	// Admin RPCs managing the investor tiers and their exposure limits
+	SetInvestorTier(context.Context, *InvestorTier) (*InvestorTier, error)
+	ListInvestorTiers(*empty.Empty, InvoiceService_ListInvestorTiersServer) error
+	AssignInvestorTier(context.Context, *Investor) (*Investor, error)
+	// Onboarding: only verified issuers list invoices and only verified investors bid
+	SubmitKycDocument(context.Context, *KycDocument) (*KycDocument, error)
+	GetKycStatus(context.Context, *KycParty) (*KycStatus, error)
+	VerifyKyc(context.Context, *KycParty) (*KycStatus, error)
+	ReviewKyc(context.Context, *KycReview) (*KycStatus, error)
+	// Invoice documents are streamed in chunks, only the issuer uploads them
+	UploadInvoiceDocument(InvoiceService_UploadInvoiceDocumentServer) error
+	DownloadInvoiceDocument(*DocumentRequest, InvoiceService_DownloadInvoiceDocumentServer) error
+	// Lists an invoice from a UBL e-invoice, each document is imported once
+	ImportInvoice(context.Context, *InvoiceImport) (*Invoice, error)
+	// Lists the invoices of a CSV file, the valid rows in one transaction
+	ImportInvoicesCsv(InvoiceService_ImportInvoicesCsvServer) error
+	// Statements are generated from the stored bids, trades, repayments and deposits
+	ExportInvestorStatement(*StatementRequest, InvoiceService_ExportInvestorStatementServer) error
+	// Positions, returns and exposure of an investor, the positions a page at a time
+	GetPortfolio(context.Context, *PortfolioRequest) (*Portfolio, error)
+	// Signed push notifications of bids, trades and repayments, failed deliveries end up as replayable dead letters
+	RegisterWebhookEndpoint(context.Context, *WebhookEndpoint) (*WebhookEndpoint, error)
+	ListWebhookEndpoints(*WebhookEndpoint, InvoiceService_ListWebhookEndpointsServer) error
+	DeleteWebhookEndpoint(context.Context, *WebhookEndpoint) (*WebhookEndpoint, error)
+	// Sends a sample event to the endpoint right away and returns the outcome, nothing is retried
+	TestWebhookEndpoint(context.Context, *WebhookEndpoint) (*WebhookDelivery, error)
+	ListWebhookDeadLetters(*WebhookEndpoint, InvoiceService_ListWebhookDeadLettersServer) error
+	ReplayWebhookDeadLetter(context.Context, *WebhookReplay) (*WebhookDelivery, error)
+	mustEmbedUnimplementedInvoiceServiceServer()
+}
+
+// UnimplementedInvoiceServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInvoiceServiceServer struct {
+}
+
+func (UnimplementedInvoiceServiceServer) CreateInvoice(context.Context, *Invoice) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetInvoice(context.Context, *Invoice) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetIssuer(context.Context, *Issuer) (*Issuer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssuer not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetIssuerSummary(context.Context, *Issuer) (*IssuerSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIssuerSummary not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetInvestors(*empty.Empty, InvoiceService_GetInvestorsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetInvestors not implemented")
+}
+func (UnimplementedInvoiceServiceServer) PlaceBid(context.Context, *Bid) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceBid not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ApproveTrade(context.Context, *Bid) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveTrade not implemented")
+}
+func (UnimplementedInvoiceServiceServer) CancelBid(context.Context, *BidCancellation) (*Bid, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelBid not implemented")
+}
+func (UnimplementedInvoiceServiceServer) CreateAutoBidRule(context.Context, *AutoBidRule) (*AutoBidRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAutoBidRule not implemented")
+}
+func (UnimplementedInvoiceServiceServer) UpdateAutoBidRule(context.Context, *AutoBidRule) (*AutoBidRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAutoBidRule not implemented")
+}
+func (UnimplementedInvoiceServiceServer) SetAutoBidRuleStatus(context.Context, *AutoBidRuleStatus) (*AutoBidRule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAutoBidRuleStatus not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListAutoBidRules(*Investor, InvoiceService_ListAutoBidRulesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListAutoBidRules not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListAutoBidExecutions(*AutoBidExecutionRequest, InvoiceService_ListAutoBidExecutionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListAutoBidExecutions not implemented")
+}
+func (UnimplementedInvoiceServiceServer) RecordRepayment(context.Context, *Repayment) (*Repayment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordRepayment not implemented")
+}
+func (UnimplementedInvoiceServiceServer) SetInvestorTier(context.Context, *InvestorTier) (*InvestorTier, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetInvestorTier not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListInvestorTiers(*empty.Empty, InvoiceService_ListInvestorTiersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListInvestorTiers not implemented")
+}
+func (UnimplementedInvoiceServiceServer) AssignInvestorTier(context.Context, *Investor) (*Investor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignInvestorTier not implemented")
+}
+func (UnimplementedInvoiceServiceServer) SubmitKycDocument(context.Context, *KycDocument) (*KycDocument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitKycDocument not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetKycStatus(context.Context, *KycParty) (*KycStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKycStatus not implemented")
+}
+func (UnimplementedInvoiceServiceServer) VerifyKyc(context.Context, *KycParty) (*KycStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyKyc not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ReviewKyc(context.Context, *KycReview) (*KycStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReviewKyc not implemented")
+}
+func (UnimplementedInvoiceServiceServer) UploadInvoiceDocument(InvoiceService_UploadInvoiceDocumentServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadInvoiceDocument not implemented")
+}
+func (UnimplementedInvoiceServiceServer) DownloadInvoiceDocument(*DocumentRequest, InvoiceService_DownloadInvoiceDocumentServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadInvoiceDocument not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ImportInvoice(context.Context, *InvoiceImport) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportInvoice not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ImportInvoicesCsv(InvoiceService_ImportInvoicesCsvServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportInvoicesCsv not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ExportInvestorStatement(*StatementRequest, InvoiceService_ExportInvestorStatementServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportInvestorStatement not implemented")
+}
+func (UnimplementedInvoiceServiceServer) GetPortfolio(context.Context, *PortfolioRequest) (*Portfolio, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortfolio not implemented")
+}
+func (UnimplementedInvoiceServiceServer) RegisterWebhookEndpoint(context.Context, *WebhookEndpoint) (*WebhookEndpoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWebhookEndpoint not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListWebhookEndpoints(*WebhookEndpoint, InvoiceService_ListWebhookEndpointsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListWebhookEndpoints not implemented")
+}
+func (UnimplementedInvoiceServiceServer) DeleteWebhookEndpoint(context.Context, *WebhookEndpoint) (*WebhookEndpoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhookEndpoint not implemented")
+}
+func (UnimplementedInvoiceServiceServer) TestWebhookEndpoint(context.Context, *WebhookEndpoint) (*WebhookDelivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestWebhookEndpoint not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ListWebhookDeadLetters(*WebhookEndpoint, InvoiceService_ListWebhookDeadLettersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListWebhookDeadLetters not implemented")
+}
+func (UnimplementedInvoiceServiceServer) ReplayWebhookDeadLetter(context.Context, *WebhookReplay) (*WebhookDelivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayWebhookDeadLetter not implemented")
+}
+func (UnimplementedInvoiceServiceServer) mustEmbedUnimplementedInvoiceServiceServer() {}
+
+// UnsafeInvoiceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InvoiceServiceServer will
+// result in compilation errors.
+type UnsafeInvoiceServiceServer interface {
+	mustEmbedUnimplementedInvoiceServiceServer()
+}
+
+func RegisterInvoiceServiceServer(s grpc.ServiceRegistrar, srv InvoiceServiceServer) {
+	s.RegisterService(&InvoiceService_ServiceDesc, srv)
+}
+
+func _InvoiceService_CreateInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Invoice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).CreateInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_CreateInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).CreateInvoice(ctx, req.(*Invoice))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Invoice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetInvoice(ctx, req.(*Invoice))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetIssuer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Issuer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetIssuer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetIssuer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetIssuer(ctx, req.(*Issuer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetIssuerSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Issuer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetIssuerSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetIssuerSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetIssuerSummary(ctx, req.(*Issuer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetInvestors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(empty.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).GetInvestors(m, &invoiceServiceGetInvestorsServer{stream})
+}
+
+type InvoiceService_GetInvestorsServer interface {
+	Send(*Investor) error
+	grpc.ServerStream
+}
+
+type invoiceServiceGetInvestorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoiceServiceGetInvestorsServer) Send(m *Investor) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvoiceService_PlaceBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Bid)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).PlaceBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_PlaceBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).PlaceBid(ctx, req.(*Bid))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ApproveTrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Bid)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ApproveTrade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ApproveTrade_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ApproveTrade(ctx, req.(*Bid))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_CancelBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BidCancellation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).CancelBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_CancelBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).CancelBid(ctx, req.(*BidCancellation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_CreateAutoBidRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutoBidRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).CreateAutoBidRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_CreateAutoBidRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).CreateAutoBidRule(ctx, req.(*AutoBidRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_UpdateAutoBidRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutoBidRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).UpdateAutoBidRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_UpdateAutoBidRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).UpdateAutoBidRule(ctx, req.(*AutoBidRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_SetAutoBidRuleStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutoBidRuleStatus)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).SetAutoBidRuleStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_SetAutoBidRuleStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).SetAutoBidRuleStatus(ctx, req.(*AutoBidRuleStatus))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListAutoBidRules_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Investor)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).ListAutoBidRules(m, &invoiceServiceListAutoBidRulesServer{stream})
+}
+
+type InvoiceService_ListAutoBidRulesServer interface {
+	Send(*AutoBidRule) error
+	grpc.ServerStream
+}
+
+type invoiceServiceListAutoBidRulesServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoiceServiceListAutoBidRulesServer) Send(m *AutoBidRule) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvoiceService_ListAutoBidExecutions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AutoBidExecutionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).ListAutoBidExecutions(m, &invoiceServiceListAutoBidExecutionsServer{stream})
+}
+
+type InvoiceService_ListAutoBidExecutionsServer interface {
+	Send(*AutoBidExecution) error
+	grpc.ServerStream
+}
+
+type invoiceServiceListAutoBidExecutionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoiceServiceListAutoBidExecutionsServer) Send(m *AutoBidExecution) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvoiceService_RecordRepayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Repayment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).RecordRepayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_RecordRepayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).RecordRepayment(ctx, req.(*Repayment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_SetInvestorTier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvestorTier)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).SetInvestorTier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_SetInvestorTier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).SetInvestorTier(ctx, req.(*InvestorTier))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListInvestorTiers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(empty.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).ListInvestorTiers(m, &invoiceServiceListInvestorTiersServer{stream})
+}
+
+type InvoiceService_ListInvestorTiersServer interface {
+	Send(*InvestorTier) error
+	grpc.ServerStream
+}
+
+type invoiceServiceListInvestorTiersServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoiceServiceListInvestorTiersServer) Send(m *InvestorTier) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvoiceService_AssignInvestorTier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Investor)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).AssignInvestorTier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_AssignInvestorTier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).AssignInvestorTier(ctx, req.(*Investor))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_SubmitKycDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KycDocument)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).SubmitKycDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_SubmitKycDocument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).SubmitKycDocument(ctx, req.(*KycDocument))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *invoiceServiceGetInvestorsClient) Recv() (*Investor, error) {
-	m := new(Investor)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _InvoiceService_GetKycStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KycParty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetKycStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_GetKycStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetKycStatus(ctx, req.(*KycParty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *invoiceServiceClient) PlaceBid(ctx context.Context, in *Bid, opts ...grpc.CallOption) (*Bid, error) {
-	out := new(Bid)
-	err := c.cc.Invoke(ctx, InvoiceService_PlaceBid_FullMethodName, in, out, opts...)
-	if err != nil {
+func _InvoiceService_VerifyKyc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KycParty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).VerifyKyc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_VerifyKyc_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).VerifyKyc(ctx, req.(*KycParty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *invoiceServiceClient) ApproveTrade(ctx context.Context, in *Bid, opts ...grpc.CallOption) (*Bid, error) {
-	out := new(Bid)
-	err := c.cc.Invoke(ctx, InvoiceService_ApproveTrade_FullMethodName, in, out, opts...)
-	if err != nil {
+func _InvoiceService_ReviewKyc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KycReview)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ReviewKyc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ReviewKyc_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ReviewKyc(ctx, req.(*KycReview))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// InvoiceServiceServer is the server API for InvoiceService service.
-// All implementations must embed UnimplementedInvoiceServiceServer
-// for forward compatibility
-type InvoiceServiceServer interface {
-	CreateInvoice(context.Context, *Invoice) (*Invoice, error)
-	GetInvoice(context.Context, *Invoice) (*Invoice, error)
-	GetIssuer(context.Context, *Issuer) (*Issuer, error)
-	//I'm using a stream to get all the investors since we don't know how many there are
-	GetInvestors(*empty.Empty, InvoiceService_GetInvestorsServer) error
-	PlaceBid(context.Context, *Bid) (*Bid, error)
-	ApproveTrade(context.Context, *Bid) (*Bid, error)
-	mustEmbedUnimplementedInvoiceServiceServer()
+func _InvoiceService_UploadInvoiceDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InvoiceServiceServer).UploadInvoiceDocument(&invoiceServiceUploadInvoiceDocumentServer{stream})
 }
 
-// UnimplementedInvoiceServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedInvoiceServiceServer struct {
+type InvoiceService_UploadInvoiceDocumentServer interface {
+	SendAndClose(*InvoiceDocument) error
+	Recv() (*InvoiceDocumentChunk, error)
+	grpc.ServerStream
 }
 
-func (UnimplementedInvoiceServiceServer) CreateInvoice(context.Context, *Invoice) (*Invoice, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateInvoice not implemented")
-}
-func (UnimplementedInvoiceServiceServer) GetInvoice(context.Context, *Invoice) (*Invoice, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetInvoice not implemented")
+type invoiceServiceUploadInvoiceDocumentServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedInvoiceServiceServer) GetIssuer(context.Context, *Issuer) (*Issuer, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetIssuer not implemented")
+
+func (x *invoiceServiceUploadInvoiceDocumentServer) SendAndClose(m *InvoiceDocument) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedInvoiceServiceServer) GetInvestors(*empty.Empty, InvoiceService_GetInvestorsServer) error {
-	return status.Errorf(codes.Unimplemented, "method GetInvestors not implemented")
+
+func (x *invoiceServiceUploadInvoiceDocumentServer) Recv() (*InvoiceDocumentChunk, error) {
+	m := new(InvoiceDocumentChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
-func (UnimplementedInvoiceServiceServer) PlaceBid(context.Context, *Bid) (*Bid, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PlaceBid not implemented")
+
+func _InvoiceService_DownloadInvoiceDocument_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DocumentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).DownloadInvoiceDocument(m, &invoiceServiceDownloadInvoiceDocumentServer{stream})
 }
-func (UnimplementedInvoiceServiceServer) ApproveTrade(context.Context, *Bid) (*Bid, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ApproveTrade not implemented")
+
+type InvoiceService_DownloadInvoiceDocumentServer interface {
+	Send(*InvoiceDocumentChunk) error
+	grpc.ServerStream
 }
-func (UnimplementedInvoiceServiceServer) mustEmbedUnimplementedInvoiceServiceServer() {}
 
-// UnsafeInvoiceServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to InvoiceServiceServer will
-// result in compilation errors.
-type UnsafeInvoiceServiceServer interface {
-	mustEmbedUnimplementedInvoiceServiceServer()
+type invoiceServiceDownloadInvoiceDocumentServer struct {
+	grpc.ServerStream
 }
 
-func RegisterInvoiceServiceServer(s grpc.ServiceRegistrar, srv InvoiceServiceServer) {
-	s.RegisterService(&InvoiceService_ServiceDesc, srv)
+func (x *invoiceServiceDownloadInvoiceDocumentServer) Send(m *InvoiceDocumentChunk) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _InvoiceService_CreateInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Invoice)
+func _InvoiceService_ImportInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvoiceImport)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InvoiceServiceServer).CreateInvoice(ctx, in)
+		return srv.(InvoiceServiceServer).ImportInvoice(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: InvoiceService_CreateInvoice_FullMethodName,
+		FullMethod: InvoiceService_ImportInvoice_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InvoiceServiceServer).CreateInvoice(ctx, req.(*Invoice))
+		return srv.(InvoiceServiceServer).ImportInvoice(ctx, req.(*InvoiceImport))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InvoiceService_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Invoice)
+func _InvoiceService_ImportInvoicesCsv_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InvoiceServiceServer).ImportInvoicesCsv(&invoiceServiceImportInvoicesCsvServer{stream})
+}
+
+type InvoiceService_ImportInvoicesCsvServer interface {
+	SendAndClose(*InvoiceImportSummary) error
+	Recv() (*InvoiceCsvChunk, error)
+	grpc.ServerStream
+}
+
+type invoiceServiceImportInvoicesCsvServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoiceServiceImportInvoicesCsvServer) SendAndClose(m *InvoiceImportSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *invoiceServiceImportInvoicesCsvServer) Recv() (*InvoiceCsvChunk, error) {
+	m := new(InvoiceCsvChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _InvoiceService_ExportInvestorStatement_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatementRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).ExportInvestorStatement(m, &invoiceServiceExportInvestorStatementServer{stream})
+}
+
+type InvoiceService_ExportInvestorStatementServer interface {
+	Send(*StatementChunk) error
+	grpc.ServerStream
+}
+
+type invoiceServiceExportInvestorStatementServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoiceServiceExportInvestorStatementServer) Send(m *StatementChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvoiceService_GetPortfolio_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PortfolioRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InvoiceServiceServer).GetInvoice(ctx, in)
+		return srv.(InvoiceServiceServer).GetPortfolio(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: InvoiceService_GetInvoice_FullMethodName,
+		FullMethod: InvoiceService_GetPortfolio_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InvoiceServiceServer).GetInvoice(ctx, req.(*Invoice))
+		return srv.(InvoiceServiceServer).GetPortfolio(ctx, req.(*PortfolioRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InvoiceService_GetIssuer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Issuer)
+func _InvoiceService_RegisterWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookEndpoint)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InvoiceServiceServer).GetIssuer(ctx, in)
+		return srv.(InvoiceServiceServer).RegisterWebhookEndpoint(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: InvoiceService_GetIssuer_FullMethodName,
+		FullMethod: InvoiceService_RegisterWebhookEndpoint_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InvoiceServiceServer).GetIssuer(ctx, req.(*Issuer))
+		return srv.(InvoiceServiceServer).RegisterWebhookEndpoint(ctx, req.(*WebhookEndpoint))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InvoiceService_GetInvestors_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(empty.Empty)
+func _InvoiceService_ListWebhookEndpoints_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WebhookEndpoint)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(InvoiceServiceServer).GetInvestors(m, &invoiceServiceGetInvestorsServer{stream})
+	return srv.(InvoiceServiceServer).ListWebhookEndpoints(m, &invoiceServiceListWebhookEndpointsServer{stream})
 }
 
-type InvoiceService_GetInvestorsServer interface {
-	Send(*Investor) error
+type InvoiceService_ListWebhookEndpointsServer interface {
+	Send(*WebhookEndpoint) error
 	grpc.ServerStream
 }
 
-type invoiceServiceGetInvestorsServer struct {
+type invoiceServiceListWebhookEndpointsServer struct {
 	grpc.ServerStream
 }
 
-func (x *invoiceServiceGetInvestorsServer) Send(m *Investor) error {
+func (x *invoiceServiceListWebhookEndpointsServer) Send(m *WebhookEndpoint) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _InvoiceService_PlaceBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Bid)
+func _InvoiceService_DeleteWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookEndpoint)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InvoiceServiceServer).PlaceBid(ctx, in)
+		return srv.(InvoiceServiceServer).DeleteWebhookEndpoint(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: InvoiceService_PlaceBid_FullMethodName,
+		FullMethod: InvoiceService_DeleteWebhookEndpoint_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InvoiceServiceServer).PlaceBid(ctx, req.(*Bid))
+		return srv.(InvoiceServiceServer).DeleteWebhookEndpoint(ctx, req.(*WebhookEndpoint))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _InvoiceService_ApproveTrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Bid)
+func _InvoiceService_TestWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookEndpoint)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(InvoiceServiceServer).ApproveTrade(ctx, in)
+		return srv.(InvoiceServiceServer).TestWebhookEndpoint(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: InvoiceService_ApproveTrade_FullMethodName,
+		FullMethod: InvoiceService_TestWebhookEndpoint_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InvoiceServiceServer).ApproveTrade(ctx, req.(*Bid))
+		return srv.(InvoiceServiceServer).TestWebhookEndpoint(ctx, req.(*WebhookEndpoint))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListWebhookDeadLetters_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WebhookEndpoint)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoiceServiceServer).ListWebhookDeadLetters(m, &invoiceServiceListWebhookDeadLettersServer{stream})
+}
+
+type InvoiceService_ListWebhookDeadLettersServer interface {
+	Send(*WebhookDeadLetter) error
+	grpc.ServerStream
+}
+
+type invoiceServiceListWebhookDeadLettersServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoiceServiceListWebhookDeadLettersServer) Send(m *WebhookDeadLetter) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvoiceService_ReplayWebhookDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WebhookReplay)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ReplayWebhookDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoiceService_ReplayWebhookDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ReplayWebhookDeadLetter(ctx, req.(*WebhookReplay))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -305,6 +1468,10 @@ var InvoiceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetIssuer",
 			Handler:    _InvoiceService_GetIssuer_Handler,
 		},
+		{
+			MethodName: "GetIssuerSummary",
+			Handler:    _InvoiceService_GetIssuerSummary_Handler,
+		},
 		{
 			MethodName: "PlaceBid",
 			Handler:    _InvoiceService_PlaceBid_Handler,
@@ -313,6 +1480,74 @@ var InvoiceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ApproveTrade",
 			Handler:    _InvoiceService_ApproveTrade_Handler,
 		},
+		{
+			MethodName: "CancelBid",
+			Handler:    _InvoiceService_CancelBid_Handler,
+		},
+		{
+			MethodName: "CreateAutoBidRule",
+			Handler:    _InvoiceService_CreateAutoBidRule_Handler,
+		},
+		{
+			MethodName: "UpdateAutoBidRule",
+			Handler:    _InvoiceService_UpdateAutoBidRule_Handler,
+		},
+		{
+			MethodName: "SetAutoBidRuleStatus",
+			Handler:    _InvoiceService_SetAutoBidRuleStatus_Handler,
+		},
+		{
+			MethodName: "RecordRepayment",
+			Handler:    _InvoiceService_RecordRepayment_Handler,
+		},
+		{
+			MethodName: "SetInvestorTier",
+			Handler:    _InvoiceService_SetInvestorTier_Handler,
+		},
+		{
+			MethodName: "AssignInvestorTier",
+			Handler:    _InvoiceService_AssignInvestorTier_Handler,
+		},
+		{
+			MethodName: "SubmitKycDocument",
+			Handler:    _InvoiceService_SubmitKycDocument_Handler,
+		},
+		{
+			MethodName: "GetKycStatus",
+			Handler:    _InvoiceService_GetKycStatus_Handler,
+		},
+		{
+			MethodName: "VerifyKyc",
+			Handler:    _InvoiceService_VerifyKyc_Handler,
+		},
+		{
+			MethodName: "ReviewKyc",
+			Handler:    _InvoiceService_ReviewKyc_Handler,
+		},
+		{
+			MethodName: "ImportInvoice",
+			Handler:    _InvoiceService_ImportInvoice_Handler,
+		},
+		{
+			MethodName: "GetPortfolio",
+			Handler:    _InvoiceService_GetPortfolio_Handler,
+		},
+		{
+			MethodName: "RegisterWebhookEndpoint",
+			Handler:    _InvoiceService_RegisterWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "DeleteWebhookEndpoint",
+			Handler:    _InvoiceService_DeleteWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "TestWebhookEndpoint",
+			Handler:    _InvoiceService_TestWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "ReplayWebhookDeadLetter",
+			Handler:    _InvoiceService_ReplayWebhookDeadLetter_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -320,6 +1555,51 @@ var InvoiceService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _InvoiceService_GetInvestors_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListAutoBidRules",
+			Handler:       _InvoiceService_ListAutoBidRules_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListAutoBidExecutions",
+			Handler:       _InvoiceService_ListAutoBidExecutions_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListInvestorTiers",
+			Handler:       _InvoiceService_ListInvestorTiers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadInvoiceDocument",
+			Handler:       _InvoiceService_UploadInvoiceDocument_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadInvoiceDocument",
+			Handler:       _InvoiceService_DownloadInvoiceDocument_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportInvoicesCsv",
+			Handler:       _InvoiceService_ImportInvoicesCsv_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportInvestorStatement",
+			Handler:       _InvoiceService_ExportInvestorStatement_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListWebhookEndpoints",
+			Handler:       _InvoiceService_ListWebhookEndpoints_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListWebhookDeadLetters",
+			Handler:       _InvoiceService_ListWebhookDeadLetters_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "protos/protobuf.proto",
 }
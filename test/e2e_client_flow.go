@@ -4,12 +4,14 @@ import (
 	"context"
 	"io"
 	"log"
+	"os"
 	"time"
 
 	cfg "github.com/berdebotond/bankable_technical_test/config"
 	"github.com/berdebotond/bankable_technical_test/pkg"
 	pb "github.com/berdebotond/bankable_technical_test/protos"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -32,7 +34,10 @@ func main() {
 	defer cancel()
 
 	// Get first investor
-	db := pkg.SetupDatabase(config.DatabaseHost, config.DatabasePort, config.DatabaseUser, config.DatabasePassword, config.DatabaseName)
+	db, err := pkg.SetupDatabase(config)
+	if err != nil {
+		log.Fatalf("could not set up database: %v", err)
+	}
 	var investorId string
 	err = db.QueryRow("SELECT id FROM investor LIMIT 1").Scan(&investorId)
 	if err != nil {
@@ -46,6 +51,16 @@ func main() {
 		log.Fatalf("could not get issuer: %v", err)
 	}
 
+	// Only verified parties can trade. The parties submit their own documents and an admin verifies them, so
+	// onboarding needs the tokens of all three in the server's AuthTokenFile.
+	adminToken, issuerToken, investorToken := os.Getenv("E2E_ADMIN_TOKEN"), os.Getenv("E2E_ISSUER_TOKEN"), os.Getenv("E2E_INVESTOR_TOKEN")
+	if adminToken != "" && issuerToken != "" && investorToken != "" {
+		onboard(ctx, c, pkg.PartyIssuer, issuerId, issuerToken, adminToken)
+		onboard(ctx, c, pkg.PartyInvestor, investorId, investorToken, adminToken)
+	} else {
+		log.Printf("Skipping onboarding, set E2E_ADMIN_TOKEN, E2E_ISSUER_TOKEN and E2E_INVESTOR_TOKEN to test it")
+	}
+
 	// Call CreateInvoice
 	invoice, err := c.CreateInvoice(ctx, &pb.Invoice{IssuerId: issuerId, Status: "open", InvestorId: investorId, Price: 10})
 	if err != nil {
@@ -72,6 +87,14 @@ func main() {
 		log.Fatalf("could not get invoice: %v", err)
 	}
 
+	// Attach a document to the invoice and read it back as the investor. The document RPCs act for the caller of
+	// the bearer token, so this step needs tokens of the issuer and investor above in the server's AuthTokenFile.
+	if issuerToken != "" && investorToken != "" {
+		checkDocument(ctx, c, invoice.GetId(), issuerToken, investorToken)
+	} else {
+		log.Printf("Skipping invoice documents, set E2E_ISSUER_TOKEN and E2E_INVESTOR_TOKEN to test them")
+	}
+
 	// Call GetIssuer
 	_, err = c.GetIssuer(ctx, &pb.Issuer{Id: issuerId})
 	if err != nil {
@@ -135,7 +158,7 @@ func main() {
 	}
 
 	// list all invoices
-	row, err = db.Query("SELECT * FROM invoice")
+	row, err = db.Query("SELECT id, issuer_id, investor_id, status, price FROM invoice")
 	if err != nil {
 		log.Fatalf("could not get invoices: %v", err)
 	}
@@ -160,3 +183,68 @@ func main() {
 	log.Println("Trade approved")
 	log.Println("All tests passed")
 }
+
+// onboard submits every missing checklist document of a pending party as the party and has the admin verify it
+func onboard(ctx context.Context, c pb.InvoiceServiceClient, partyType, partyId, partyToken, adminToken string) {
+	kycStatus, err := c.GetKycStatus(ctx, &pb.KycParty{PartyType: partyType, PartyId: partyId})
+	if err != nil {
+		log.Fatalf("could not get kyc status: %v", err)
+	}
+	if kycStatus.GetStatus() != pkg.KycPending {
+		return
+	}
+	for _, document := range kycStatus.GetChecklist() {
+		if document.GetStatus() != pkg.DocumentMissing {
+			continue
+		}
+		_, err := c.SubmitKycDocument(withToken(ctx, partyToken), &pb.KycDocument{PartyType: partyType, PartyId: partyId, DocumentType: document.GetDocumentType(), Reference: "e2e/" + document.GetDocumentType() + ".pdf"})
+		if err != nil {
+			log.Fatalf("could not submit kyc document: %v", err)
+		}
+	}
+	kycStatus, err = c.VerifyKyc(withToken(ctx, adminToken), &pb.KycParty{PartyType: partyType, PartyId: partyId})
+	if err != nil {
+		log.Fatalf("could not verify %s: %v", partyType, err)
+	}
+	log.Printf("%s %s is %s", partyType, partyId, kycStatus.GetStatus())
+}
+
+// checkDocument uploads a document of the invoice as the issuer and downloads it as the investor
+func checkDocument(ctx context.Context, c pb.InvoiceServiceClient, invoiceId, issuerToken, investorToken string) {
+	upload, err := c.UploadInvoiceDocument(withToken(ctx, issuerToken))
+	if err != nil {
+		log.Fatalf("could not upload document: %v", err)
+	}
+	content := []byte("%PDF-1.7\ne2e invoice\n")
+	err = upload.Send(&pb.InvoiceDocumentChunk{InvoiceId: invoiceId, FileName: "invoice.pdf", Data: content})
+	if err != nil {
+		log.Fatalf("could not upload document: %v", err)
+	}
+	document, err := upload.CloseAndRecv()
+	if err != nil {
+		log.Fatalf("could not upload document: %v", err)
+	}
+	download, err := c.DownloadInvoiceDocument(withToken(ctx, investorToken), &pb.DocumentRequest{InvoiceId: invoiceId, DocumentId: document.GetId()})
+	if err != nil {
+		log.Fatalf("could not download document: %v", err)
+	}
+	var downloaded []byte
+	for {
+		chunk, err := download.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("could not download document: %v", err)
+		}
+		downloaded = append(downloaded, chunk.GetData()...)
+	}
+	if string(downloaded) != string(content) {
+		log.Fatalf("downloaded document doesn't match the upload")
+	}
+}
+
+// withToken returns ctx sending the bearer token
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}